@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdACM() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "acm",
+		Short: "ACM Certificates",
+		Run: func(cmd *cobra.Command, args []string) {
+			certificates, err := c.GetACMCertificatesWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(certificates))
+		},
+	}
+
+	return cmd
+}
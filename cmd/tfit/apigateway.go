@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdAPIGateway() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apigateway",
+		Short: "API Gateway REST APIs, Resources, Methods & Integrations",
+		Run: func(cmd *cobra.Command, args []string) {
+			apis, err := c.GetRestAPIsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(apis))
+		},
+	}
+
+	return cmd
+}
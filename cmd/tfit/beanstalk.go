@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdBeanstalk() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "beanstalk",
+		Short: "Elastic Beanstalk Related",
+	}
+
+	cmd.AddCommand(NewCmdBeanstalkApp())
+	cmd.AddCommand(NewCmdBeanstalkEnv())
+
+	return cmd
+}
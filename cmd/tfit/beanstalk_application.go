@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdBeanstalkApp() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "application",
+		Short: "Elastic Beanstalk Applications",
+		Run: func(cmd *cobra.Command, args []string) {
+			apps, err := c.GetBeanstalkAppsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(apps))
+		},
+	}
+
+	return cmd
+}
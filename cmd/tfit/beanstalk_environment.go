@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdBeanstalkEnv() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "environment",
+		Short: "Elastic Beanstalk Environments, except terminated ones",
+		Run: func(cmd *cobra.Command, args []string) {
+			envs, err := c.GetBeanstalkEnvsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(envs))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdCloudFront() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cloudfront",
+		Short: "CloudFront Distributions",
+		Run: func(cmd *cobra.Command, args []string) {
+			distributions, err := c.GetDistributionsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(distributions))
+		},
+	}
+
+	return cmd
+}
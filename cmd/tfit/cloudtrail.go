@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdCloudTrail() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cloudtrail",
+		Short: "CloudTrail Trails",
+		Run: func(cmd *cobra.Command, args []string) {
+			trails, err := c.GetTrailsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(trails))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdCloudWatch() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cloudwatch",
+		Short: "CloudWatch Metric Alarms",
+		Run: func(cmd *cobra.Command, args []string) {
+			alarms, err := c.GetMetricAlarmsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(alarms))
+		},
+	}
+
+	return cmd
+}
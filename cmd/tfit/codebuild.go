@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdCodeBuild() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "codebuild",
+		Short: "CodeBuild Projects",
+		Run: func(cmd *cobra.Command, args []string) {
+			projects, err := c.GetCodeBuildProjectsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(projects))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdDynamoDB() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dynamodb",
+		Short: "DynamoDB Related",
+	}
+
+	cmd.AddCommand(NewCmdDynamoDBAutoScaling())
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdDynamoDBAutoScaling() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "autoscaling",
+		Short: "DynamoDB Auto Scaling Targets & Policies",
+		Run: func(cmd *cobra.Command, args []string) {
+			targets, err := c.GetDynamoDBScalableTargetsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(targets))
+		},
+	}
+
+	return cmd
+}
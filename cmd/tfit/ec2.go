@@ -15,6 +15,18 @@ func NewCmdEC2() *cobra.Command {
 	cmd.AddCommand(NewCmdEC2VPCs())
 	cmd.AddCommand(NewCmdEC2Subnets())
 	cmd.AddCommand(NewCmdEC2RouteTables())
+	cmd.AddCommand(NewCmdEC2NetworkACLs())
+	cmd.AddCommand(NewCmdEC2VPCPeerings())
+	cmd.AddCommand(NewCmdEC2VPCEndpoints())
+	cmd.AddCommand(NewCmdEC2DHCPOptions())
+	cmd.AddCommand(NewCmdEC2NetworkInterfaces())
+	cmd.AddCommand(NewCmdEC2PlacementGroups())
+	cmd.AddCommand(NewCmdEC2SpotRequests())
+	cmd.AddCommand(NewCmdEC2TransitGateways())
+	cmd.AddCommand(NewCmdEC2TransitGatewayVPCAttachments())
+	cmd.AddCommand(NewCmdEC2VPNGateways())
+	cmd.AddCommand(NewCmdEC2CustomerGateways())
+	cmd.AddCommand(NewCmdEC2VPNConnections())
 
 	return cmd
 }
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2CustomerGateways() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "customer-gateways",
+		Short: "EC2 Customer Gateways",
+		Run: func(cmd *cobra.Command, args []string) {
+			gateways, err := c.GetCustomerGatewaysWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(gateways))
+		},
+	}
+
+	return cmd
+}
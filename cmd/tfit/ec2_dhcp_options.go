@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2DHCPOptions() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dhcp-options",
+		Short: "EC2 DHCP Options Sets",
+		Run: func(cmd *cobra.Command, args []string) {
+			options, err := c.GetDHCPOptionsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(options))
+		},
+	}
+
+	return cmd
+}
@@ -9,9 +9,9 @@ func NewCmdEC2Instances() *cobra.Command {
 		Use:   "instances",
 		Short: "EC2 Instances",
 		Run: func(cmd *cobra.Command, args []string) {
-			ec2, err := c.GetInstances()
-			handleError(err)
-			handleError(ec2.WriteHCL(w))
+			ec2, err := c.GetInstancesWithContext(ctx)
+			handlePartialError(err)
+			handleError(writeResource(ec2))
 		},
 	}
 
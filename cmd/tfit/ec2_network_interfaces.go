@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2NetworkInterfaces() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eni",
+		Short: "EC2 Elastic Network Interfaces",
+		Run: func(cmd *cobra.Command, args []string) {
+			enis, err := c.GetENIsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(enis))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2PlacementGroups() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "placement-groups",
+		Short: "EC2 Placement Groups",
+		Run: func(cmd *cobra.Command, args []string) {
+			groups, err := c.GetPlacementGroupsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(groups))
+		},
+	}
+
+	return cmd
+}
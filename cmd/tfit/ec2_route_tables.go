@@ -9,9 +9,9 @@ func NewCmdEC2RouteTables() *cobra.Command {
 		Use:   "rtb",
 		Short: "VPC Route & Route Table",
 		Run: func(cmd *cobra.Command, args []string) {
-			rtb, err := c.GetRouteTables()
+			rtb, err := c.GetRouteTablesWithContext(ctx)
 			handleError(err)
-			handleError(rtb.WriteHCL(w))
+			handleError(writeResource(rtb))
 		},
 	}
 
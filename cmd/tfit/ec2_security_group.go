@@ -9,11 +9,11 @@ func NewCmdEC2SecurityGroups() *cobra.Command {
 		Use:   "secgroup",
 		Short: "EC2 Security Groups",
 		Run: func(cmd *cobra.Command, args []string) {
-			AccountId, err := rootCommand.cfg.GetAccountId()
+			AccountId, err := rootCommand.cfg.GetAccountIdWithContext(ctx)
 			handleError(err)
-			sg, err := c.GetSecurityGroups(AccountId)
+			sg, err := c.GetSecurityGroupsWithContext(ctx, AccountId)
 			handleError(err)
-			handleError(sg.WriteHCL(w))
+			handleError(writeResource(sg))
 		},
 	}
 
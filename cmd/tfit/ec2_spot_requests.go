@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2SpotRequests() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "spot-requests",
+		Short: "EC2 Spot Instance Requests",
+		Run: func(cmd *cobra.Command, args []string) {
+			requests, err := c.GetSpotRequestsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(requests))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2TransitGatewayVPCAttachments() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transit-gateway-vpc-attachments",
+		Short: "EC2 Transit Gateway VPC Attachments",
+		Run: func(cmd *cobra.Command, args []string) {
+			attachments, err := c.GetTransitGatewayVPCAttachmentsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(attachments))
+		},
+	}
+
+	return cmd
+}
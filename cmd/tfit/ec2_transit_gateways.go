@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2TransitGateways() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transit-gateways",
+		Short: "EC2 Transit Gateways",
+		Run: func(cmd *cobra.Command, args []string) {
+			gateways, err := c.GetTransitGatewaysWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(gateways))
+		},
+	}
+
+	return cmd
+}
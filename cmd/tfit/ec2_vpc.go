@@ -9,9 +9,9 @@ func NewCmdEC2VPCs() *cobra.Command {
 		Use:   "vpc",
 		Short: "EC2 VPC",
 		Run: func(cmd *cobra.Command, args []string) {
-			vpc, err := c.GetVPCs()
-			handleError(err)
-			handleError(vpc.WriteHCL(w))
+			vpc, err := c.GetVPCsWithContext(ctx)
+			handlePartialError(err)
+			handleError(writeResource(vpc))
 		},
 	}
 
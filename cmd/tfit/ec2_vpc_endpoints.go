@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2VPCEndpoints() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vpc-endpoint",
+		Short: "EC2 VPC Endpoints",
+		Run: func(cmd *cobra.Command, args []string) {
+			endpoints, err := c.GetVPCEndpointsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(endpoints))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2VPCPeerings() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vpc-peering",
+		Short: "EC2 VPC Peering Connections",
+		Run: func(cmd *cobra.Command, args []string) {
+			peerings, err := c.GetVPCPeeringsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(peerings))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2VPNConnections() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vpn-connections",
+		Short: "EC2 VPN Connections & their static routes",
+		Run: func(cmd *cobra.Command, args []string) {
+			connections, err := c.GetVPNConnectionsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(connections))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2VPNGateways() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vpn-gateways",
+		Short: "EC2 VPN Gateways",
+		Run: func(cmd *cobra.Command, args []string) {
+			gateways, err := c.GetVPNGatewaysWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(gateways))
+		},
+	}
+
+	return cmd
+}
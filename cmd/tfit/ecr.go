@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdECR() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ecr",
+		Short: "ECR Repositories",
+		Run: func(cmd *cobra.Command, args []string) {
+			repositories, err := c.GetECRRepositoriesWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(repositories))
+		},
+	}
+
+	return cmd
+}
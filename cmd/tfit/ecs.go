@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdECS() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ecs",
+		Short: "ECS Related",
+	}
+
+	cmd.AddCommand(NewCmdECSCluster())
+	cmd.AddCommand(NewCmdECSService())
+
+	return cmd
+}
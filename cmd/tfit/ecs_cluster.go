@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdECSCluster() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "ECS Clusters",
+		Run: func(cmd *cobra.Command, args []string) {
+			clusters, err := c.GetECSClustersWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(clusters))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdECSService() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "service",
+		Short: "ECS Services, referencing the clusters exported by 'ecs cluster'",
+		Run: func(cmd *cobra.Command, args []string) {
+			services, err := c.GetECSServicesWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(services))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEFS() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "efs",
+		Short: "EFS File Systems & Mount Targets",
+		Run: func(cmd *cobra.Command, args []string) {
+			fileSystems, err := c.GetEFSFileSystemsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(fileSystems))
+		},
+	}
+
+	return cmd
+}
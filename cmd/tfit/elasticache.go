@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdElastiCache() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "elasticache",
+		Short: "ElastiCache Related",
+	}
+
+	cmd.AddCommand(NewCmdElastiCacheCluster())
+	cmd.AddCommand(NewCmdElastiCacheReplicationGroup())
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdElastiCacheCluster() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "ElastiCache Clusters, except replication group nodes",
+		Run: func(cmd *cobra.Command, args []string) {
+			clusters, err := c.GetElastiCacheClustersWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(clusters))
+		},
+	}
+
+	return cmd
+}
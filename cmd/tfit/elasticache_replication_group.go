@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdElastiCacheReplicationGroup() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replication-group",
+		Short: "ElastiCache Replication Groups",
+		Run: func(cmd *cobra.Command, args []string) {
+			groups, err := c.GetElastiCacheReplicationGroupsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(groups))
+		},
+	}
+
+	return cmd
+}
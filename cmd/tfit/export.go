@@ -0,0 +1,540 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
+	"github.com/spf13/cobra"
+)
+
+// collection is one supported resource type of the export command.
+// collect stores the resources into the snapshot and resource returns
+// them back, after the PostCollect hook had a chance to modify them
+type collection struct {
+	name     string
+	collect  func(s *tfit.Snapshot) error
+	resource func(s *tfit.Snapshot) resource
+	// global resources are exported once whatever the number of regions
+	global bool
+}
+
+// collections returns every supported resource type, cfg being the
+// configuration of the client c. They are exported in this order, so that
+// the resources of the combined output follow their dependencies: VPCs,
+// then subnets, gateways, route tables and finally instances
+func collections(cfg tfit.Config) []collection {
+	return []collection{
+		{"vpc",
+			func(s *tfit.Snapshot) (err error) { s.VPCs, err = c.GetVPCsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.VPCs }, false},
+		{"dhcp_options",
+			func(s *tfit.Snapshot) (err error) { s.DHCPOptions, err = c.GetDHCPOptionsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.DHCPOptions }, false},
+		{"subnets",
+			func(s *tfit.Snapshot) (err error) { s.Subnets, err = c.GetSubnetsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.Subnets }, false},
+		{"vpc_peerings",
+			func(s *tfit.Snapshot) (err error) { s.VPCPeerings, err = c.GetVPCPeeringsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.VPCPeerings }, false},
+		{"transit_gateways",
+			func(s *tfit.Snapshot) (err error) { s.TransitGateways, err = c.GetTransitGatewaysWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.TransitGateways }, false},
+		{"transit_gateway_vpc_attachments",
+			func(s *tfit.Snapshot) (err error) {
+				s.TransitGatewayVPCAttachments, err = c.GetTransitGatewayVPCAttachmentsWithContext(ctx)
+				return
+			},
+			func(s *tfit.Snapshot) resource { return s.TransitGatewayVPCAttachments }, false},
+		{"vpn_gateways",
+			func(s *tfit.Snapshot) (err error) { s.VPNGateways, err = c.GetVPNGatewaysWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.VPNGateways }, false},
+		{"customer_gateways",
+			func(s *tfit.Snapshot) (err error) { s.CustomerGateways, err = c.GetCustomerGatewaysWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.CustomerGateways }, false},
+		{"vpn_connections",
+			func(s *tfit.Snapshot) (err error) { s.VPNConnections, err = c.GetVPNConnectionsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.VPNConnections }, false},
+		{"security_groups",
+			func(s *tfit.Snapshot) (err error) {
+				accountId, err := cfg.GetAccountIdWithContext(ctx)
+				if err != nil {
+					return err
+				}
+				s.SecurityGroups, err = c.GetSecurityGroupsWithContext(ctx, accountId)
+				return
+			},
+			func(s *tfit.Snapshot) resource { return s.SecurityGroups }, false},
+		{"route_tables",
+			func(s *tfit.Snapshot) (err error) { s.RouteTables, err = c.GetRouteTablesWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.RouteTables }, false},
+		{"vpc_endpoints",
+			func(s *tfit.Snapshot) (err error) { s.VPCEndpoints, err = c.GetVPCEndpointsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.VPCEndpoints }, false},
+		{"network_acls",
+			func(s *tfit.Snapshot) (err error) { s.NetworkACLs, err = c.GetNetworkACLsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.NetworkACLs }, false},
+		{"network_interfaces",
+			func(s *tfit.Snapshot) (err error) { s.ENIs, err = c.GetENIsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.ENIs }, false},
+		{"placement_groups",
+			func(s *tfit.Snapshot) (err error) {
+				s.PlacementGroups, err = c.GetPlacementGroupsWithContext(ctx)
+				return
+			},
+			func(s *tfit.Snapshot) resource { return s.PlacementGroups }, false},
+		{"instances",
+			func(s *tfit.Snapshot) (err error) { s.Instances, err = c.GetInstancesWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.Instances }, false},
+		{"spot_requests",
+			func(s *tfit.Snapshot) (err error) { s.SpotRequests, err = c.GetSpotRequestsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.SpotRequests }, false},
+		{"route53_zones",
+			func(s *tfit.Snapshot) (err error) { s.Zones, err = c.GetHostZonesWithContext(ctx, 5); return },
+			func(s *tfit.Snapshot) resource { return s.Zones }, true},
+		{"route53_records",
+			func(s *tfit.Snapshot) (err error) { s.RecordSets, err = c.GetAllResourceRecordSetsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.RecordSets }, true},
+		{"iam_policies",
+			func(s *tfit.Snapshot) (err error) { s.Policies, err = c.GetPoliciesWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.Policies }, true},
+		{"iam_roles",
+			func(s *tfit.Snapshot) (err error) { s.Roles, err = c.ListRolesWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.Roles }, true},
+		{"iam_instance_profiles",
+			func(s *tfit.Snapshot) (err error) {
+				s.InstanceProfiles, err = c.ListInstanceProfilesWithContext(ctx)
+				return
+			},
+			func(s *tfit.Snapshot) resource { return s.InstanceProfiles }, true},
+		{"iam_users",
+			func(s *tfit.Snapshot) (err error) { s.Users, err = c.ListUsersWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.Users }, true},
+		{"iam_groups",
+			func(s *tfit.Snapshot) (err error) { s.IAMGroups, err = c.ListIAMGroupsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.IAMGroups }, true},
+		{"s3_buckets",
+			func(s *tfit.Snapshot) (err error) { s.Buckets, err = c.GetBucketsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.Buckets }, true},
+		{"autoscaling_groups",
+			func(s *tfit.Snapshot) (err error) { s.AutoScalingGroups, err = c.GetAutoScalingGroupsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.AutoScalingGroups }, false},
+		{"launch_configurations",
+			func(s *tfit.Snapshot) (err error) { s.LaunchConfigurations, err = c.GetLaunchConfigurationsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.LaunchConfigurations }, false},
+		{"elb",
+			func(s *tfit.Snapshot) (err error) { s.ELBs, err = c.ListELBsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.ELBs }, false},
+		{"kms_keys",
+			func(s *tfit.Snapshot) (err error) { s.KMSKeys, err = c.GetKMSKeysWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.KMSKeys }, false},
+		{"log_groups",
+			func(s *tfit.Snapshot) (err error) { s.LogGroups, err = c.GetLogGroupsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.LogGroups }, false},
+		{"metric_alarms",
+			func(s *tfit.Snapshot) (err error) { s.MetricAlarms, err = c.GetMetricAlarmsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.MetricAlarms }, false},
+		{"ecr_repositories",
+			func(s *tfit.Snapshot) (err error) { s.ECRRepositories, err = c.GetECRRepositoriesWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.ECRRepositories }, false},
+		{"elasticache_clusters",
+			func(s *tfit.Snapshot) (err error) { s.ElastiCacheClusters, err = c.GetElastiCacheClustersWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.ElastiCacheClusters }, false},
+		{"elasticache_replication_groups",
+			func(s *tfit.Snapshot) (err error) {
+				s.ElastiCacheReplicationGroups, err = c.GetElastiCacheReplicationGroupsWithContext(ctx)
+				return
+			},
+			func(s *tfit.Snapshot) resource { return s.ElastiCacheReplicationGroups }, false},
+		{"ssm_parameters",
+			func(s *tfit.Snapshot) (err error) { s.SSMParameters, err = c.GetSSMParametersWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.SSMParameters }, false},
+		{"secrets",
+			func(s *tfit.Snapshot) (err error) { s.Secrets, err = c.GetSecretsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.Secrets }, false},
+		{"efs_file_systems",
+			func(s *tfit.Snapshot) (err error) { s.EFSFileSystems, err = c.GetEFSFileSystemsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.EFSFileSystems }, false},
+		{"acm_certificates",
+			func(s *tfit.Snapshot) (err error) { s.ACMCertificates, err = c.GetACMCertificatesWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.ACMCertificates }, false},
+		{"rest_apis",
+			func(s *tfit.Snapshot) (err error) { s.RestAPIs, err = c.GetRestAPIsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.RestAPIs }, false},
+		{"redshift_clusters",
+			func(s *tfit.Snapshot) (err error) { s.RedshiftClusters, err = c.GetRedshiftClustersWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.RedshiftClusters }, false},
+		{"ecs_clusters",
+			func(s *tfit.Snapshot) (err error) { s.ECSClusters, err = c.GetECSClustersWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.ECSClusters }, false},
+		{"ecs_services",
+			func(s *tfit.Snapshot) (err error) { s.ECSServices, err = c.GetECSServicesWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.ECSServices }, false},
+		{"cloudfront_distributions",
+			func(s *tfit.Snapshot) (err error) { s.Distributions, err = c.GetDistributionsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.Distributions }, true},
+		{"dynamodb_autoscaling",
+			func(s *tfit.Snapshot) (err error) {
+				s.DynamoDBScalableTargets, err = c.GetDynamoDBScalableTargetsWithContext(ctx)
+				return
+			},
+			func(s *tfit.Snapshot) resource { return s.DynamoDBScalableTargets }, false},
+		{"beanstalk_applications",
+			func(s *tfit.Snapshot) (err error) { s.BeanstalkApps, err = c.GetBeanstalkAppsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.BeanstalkApps }, false},
+		{"beanstalk_environments",
+			func(s *tfit.Snapshot) (err error) { s.BeanstalkEnvs, err = c.GetBeanstalkEnvsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.BeanstalkEnvs }, false},
+		{"kinesis_streams",
+			func(s *tfit.Snapshot) (err error) { s.KinesisStreams, err = c.GetKinesisStreamsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.KinesisStreams }, false},
+		{"cloudtrail_trails",
+			func(s *tfit.Snapshot) (err error) { s.Trails, err = c.GetTrailsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.Trails }, false},
+		{"ses_identities",
+			func(s *tfit.Snapshot) (err error) { s.SESIdentities, err = c.GetSESIdentitiesWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.SESIdentities }, false},
+		{"sfn_state_machines",
+			func(s *tfit.Snapshot) (err error) { s.StateMachines, err = c.GetStateMachinesWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.StateMachines }, false},
+		{"glacier_vaults",
+			func(s *tfit.Snapshot) (err error) { s.GlacierVaults, err = c.GetGlacierVaultsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.GlacierVaults }, false},
+		{"codebuild_projects",
+			func(s *tfit.Snapshot) (err error) { s.CodeBuildProjects, err = c.GetCodeBuildProjectsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.CodeBuildProjects }, false},
+		{"db_subnet_groups",
+			func(s *tfit.Snapshot) (err error) { s.DBSubnetGroups, err = c.GetDBSubnetGroupsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.DBSubnetGroups }, false},
+		{"db_parameter_groups",
+			func(s *tfit.Snapshot) (err error) { s.DBParameterGroups, err = c.GetDBParameterGroupsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.DBParameterGroups }, false},
+		{"rds_clusters",
+			func(s *tfit.Snapshot) (err error) { s.DBClusters, err = c.GetDBClustersWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.DBClusters }, false},
+		{"organizations_accounts",
+			func(s *tfit.Snapshot) (err error) { s.Accounts, err = c.GetAccountsWithContext(ctx); return },
+			func(s *tfit.Snapshot) resource { return s.Accounts }, true},
+	}
+}
+
+func NewCmdExport() *cobra.Command {
+	var outDir string
+	var regions, profiles []string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all supported resources",
+		Run: func(cmd *cobra.Command, args []string) {
+			if validate {
+				outDir = ""
+			}
+
+			// The data sources would only describe the default provider
+			if len(profiles) > 0 && tfit.RenderOpts.DataSources {
+				handleError(fmt.Errorf("--data-sources can not be used with --profiles"))
+			}
+
+			if len(outDir) > 0 {
+				handleError(os.MkdirAll(outDir, 0755))
+			}
+
+			if tfit.TFVersionAtLeast("0.13") && format != formatJSON {
+				handleError(exportHeader("versions.tf", tfit.WriteTerraformBlock, outDir))
+			}
+			if tfit.RenderOpts.DataSources && format != formatJSON {
+				handleError(exportHeader("data.tf", tfit.WriteDataSources, outDir))
+			}
+
+			failed := make(map[string]error)
+			var names []string
+			fail := func(name string, err error) {
+				failed[name] = err
+				names = append(names, name)
+			}
+
+			if len(profiles) == 0 {
+				exportProfile(rootCommand.cfg, "", regions, outDir, fail)
+			}
+			for _, profile := range profiles {
+				cfg := rootCommand.cfg
+				cfg.Profile = profile
+				exportProfile(cfg, profile, regions, outDir, fail)
+			}
+
+			if len(jsonOutput) > 0 {
+				handleError(tfit.WriteJSON(w, jsonOutput))
+			}
+
+			if len(failed) > 0 {
+				fmt.Fprintf(os.Stderr, "%d resource types failed to export:\n", len(failed))
+				for _, name := range names {
+					fmt.Fprintf(os.Stderr, "  %s: %s\n", name, failed[name])
+				}
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&regions, "regions", nil, "Export these regions (or \"all\") instead of --region. Labels & file names are prefixed by the region and resources use the \"aws.<region>\" provider alias, whose block is written")
+	cmd.Flags().StringSliceVar(&profiles, "profiles", nil, "Export these AWS profiles instead of --profile. Labels & file names are prefixed by the profile and resources use the \"aws.<profile>\" provider alias, whose block is written")
+	cmd.Flags().BoolVar(&linkRefs, "link-refs", false, "Render the IDs of exported resources held by vpc_id, subnet_id, security groups, ... as Terraform references. IDs of resources not exported are kept as is")
+	cmd.Flags().StringVar(&outDir, "out-dir", "", "Write one <resource type>.tf file per resource type into this directory instead of a single output")
+
+	return cmd
+}
+
+// exportProfile exports the account of cfg, either its region or every
+// one of regions. scope, the profile name when several profiles are
+// exported, prefixes labels & names. Errors are reported to fail, which
+// lets the other profiles be exported
+func exportProfile(cfg tfit.Config, scope string, regions []string, outDir string, fail func(name string, err error)) {
+	var err error
+	c, err = newClient(cfg)
+	if err != nil {
+		fail(scope, err)
+		return
+	}
+
+	if len(regions) == 0 {
+		exportAll(collections(cfg), cfg, scope, outDir, fail)
+		return
+	}
+
+	if len(regions) == 1 && regions[0] == "all" {
+		regions, err = c.GetRegionsWithContext(ctx)
+		if err != nil {
+			fail(scope, err)
+			return
+		}
+	}
+
+	var global, regional []collection
+	for _, col := range collections(cfg) {
+		if col.global {
+			global = append(global, col)
+		} else {
+			regional = append(regional, col)
+		}
+	}
+
+	// Every region is collected before anything is rendered, so that the
+	// regional & global resources reference each other. The buckets,
+	// collected once, are bound to the provider of their region
+	tfit.RenderOpts.Regions = make(map[string]string)
+	tfit.RenderOpts.GlobalLabelPrefix = ""
+	if len(scope) > 0 {
+		tfit.RenderOpts.GlobalLabelPrefix = scope + "_"
+	}
+	defer func() {
+		tfit.RenderOpts.Regions = nil
+		tfit.RenderOpts.GlobalLabelPrefix = ""
+	}()
+
+	globalPass := collectAll(global, cfg, scope, fail)
+	var passes []*exportPass
+	for _, region := range regions {
+		regionCfg := cfg
+		regionCfg.Region = region
+		regionScope := joinScope(scope, region)
+		c, err = newClient(regionCfg)
+		if err != nil {
+			fail(regionScope, err)
+			continue
+		}
+		tfit.RenderOpts.Regions[region] = regionScope
+		passes = append(passes, collectAll(regional, regionCfg, regionScope, fail))
+	}
+
+	// The global snapshot is resolved last, see WithGlobal
+	for _, pass := range passes {
+		pass.snapshot.WithGlobal(globalPass.snapshot, pass.cfg.Region).ResolveReferences()
+	}
+	globalPass.snapshot.ResolveReferences()
+
+	renderAll(globalPass, outDir, fail)
+	for _, pass := range passes {
+		renderAll(pass, outDir, fail)
+	}
+}
+
+// joinScope appends region to scope, e.g. "prod_us-east-1"
+func joinScope(scope, region string) string {
+	if len(scope) == 0 {
+		return region
+	}
+
+	return scope + "_" + region
+}
+
+// exportPass is the snapshot of cols collected for scope, a region
+// and/or a profile, with the collections which failed
+type exportPass struct {
+	cols     []collection
+	cfg      tfit.Config
+	scope    string
+	snapshot *tfit.Snapshot
+	failed   map[string]bool
+}
+
+// exportAll collects cols into a snapshot, resolves the references
+// between its resources and writes them (see collectAll & renderAll)
+func exportAll(cols []collection, cfg tfit.Config, scope string, outDir string, fail func(name string, err error)) {
+	pass := collectAll(cols, cfg, scope, fail)
+	pass.snapshot.ResolveReferences()
+	renderAll(pass, outDir, fail)
+}
+
+// collectAll collects cols into a snapshot with c and runs the PostCollect
+// hook on it. The references are left to be resolved
+func collectAll(cols []collection, cfg tfit.Config, scope string, fail func(name string, err error)) *exportPass {
+	prefix := ""
+	if len(scope) > 0 {
+		prefix = scope + "_"
+	}
+
+	pass := &exportPass{cols: cols, cfg: cfg, scope: scope, snapshot: &tfit.Snapshot{}, failed: make(map[string]bool)}
+	for _, col := range cols {
+		handleError(ctx.Err())
+		err := col.collect(pass.snapshot)
+		if _, partial := err.(*tfit.MultiError); partial {
+			// The resources fetched are exported all the same
+			fail(prefix+col.name, err)
+		} else if err != nil {
+			fail(prefix+col.name, err)
+			pass.failed[col.name] = true
+		}
+	}
+	handleError(tfit.RunPostCollect(pass.snapshot))
+
+	return pass
+}
+
+// renderAll writes every collection of pass which did not fail. With
+// --link-refs, the IDs of the resources of pass are rendered as references.
+// When scope, a region and/or a profile, is set, labels & names are
+// prefixed by it and resources are bound to the "aws.<scope>" provider
+// alias. The provider block of cfg, aliased to scope, is then written
+// first so that the alias is declared, as it is for the default provider
+// with --emit-provider
+func renderAll(pass *exportPass, outDir string, fail func(name string, err error)) {
+	prefix := ""
+	tfit.RenderOpts.LabelPrefix = ""
+	tfit.RenderOpts.Provider = ""
+	if len(pass.scope) > 0 {
+		prefix = pass.scope + "_"
+		tfit.RenderOpts.LabelPrefix = prefix
+		tfit.RenderOpts.Provider = "aws." + pass.scope
+	}
+
+	if (emitProvider || len(pass.scope) > 0) && format != formatJSON {
+		provider := newProvider(pass.cfg)
+		provider.Alias = pass.scope
+		write := func(w io.Writer) error { return tfit.WriteProvider(w, provider) }
+		if err := exportHeader(prefix+"provider.tf", write, outDir); err != nil {
+			fail(prefix+"provider", err)
+		}
+	}
+
+	// The import commands give the address of every resource by AWS ID
+	tfit.RenderOpts.References = nil
+	if linkRefs && format != formatJSON {
+		buf := bytes.NewBuffer(nil)
+		for _, col := range pass.cols {
+			if !pass.failed[col.name] {
+				handleError(col.resource(pass.snapshot).WriteImports(buf))
+			}
+		}
+		tfit.RenderOpts.References = tfit.ReferenceMap(buf.String())
+	}
+
+	for _, col := range pass.cols {
+		if pass.failed[col.name] {
+			continue
+		}
+		if err := exportCollection(prefix+col.name, col.resource(pass.snapshot), outDir); err != nil {
+			fail(prefix+col.name, err)
+		}
+	}
+}
+
+// exportHeader writes the blocks shared by all the resources, e.g. the
+// data sources used with --data-sources, either to the global output or
+// to <outDir>/<name>
+func exportHeader(name string, write func(io.Writer) error, outDir string) error {
+	if len(outDir) == 0 {
+		if err := write(w); err != nil {
+			return err
+		}
+		_, err := fmt.Fprint(w, "\n\n")
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(outDir, name), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := write(f); err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(f, "\n")
+	return err
+}
+
+// linkRefs is set by --link-refs
+var linkRefs bool
+
+// jsonOutput gathers the resource types exported to the global output
+// with --format json, which are written as a single document by name
+var jsonOutput = make(map[string]resource)
+
+// exportCollection writes the HCL of a resource type either to the
+// global output or to <outDir>/<name>.tf. With --format json, it goes
+// to jsonOutput or to <outDir>/<name>.json
+func exportCollection(name string, res resource, outDir string) error {
+	if iw != nil {
+		if err := res.WriteImports(iw); err != nil {
+			return err
+		}
+	}
+
+	if format == formatJSON {
+		if len(outDir) == 0 {
+			jsonOutput[name] = res
+			return nil
+		}
+
+		f, err := os.OpenFile(filepath.Join(outDir, name+".json"), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		return tfit.WriteJSON(f, res)
+	}
+
+	if len(outDir) == 0 {
+		if err := res.WriteHCL(w); err != nil {
+			return err
+		}
+		_, err := fmt.Fprint(w, "\n\n")
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(outDir, name+".tf"), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := res.WriteHCL(f); err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(f, "\n")
+	return err
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdFmt() *cobra.Command {
+	var write bool
+
+	cmd := &cobra.Command{
+		Use:   "fmt [file.tf...]",
+		Short: "Reformat Terraform files, e.g. hand-edited exports. StdIn when no file or \"-\" is given",
+		// Replaces initConfig: no AWS client is needed and the output is
+		// only opened when the files are not rewritten in place
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			w = os.Stdout
+			if !write {
+				var err error
+				w, err = openOutput()
+				handleError(err)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				args = []string{"-"}
+			}
+
+			// A file which does not parse is reported and left untouched,
+			// the other ones are formatted all the same
+			failed := 0
+			for _, path := range args {
+				if err := fmtFile(path, write); err != nil {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+					failed++
+				}
+			}
+
+			if failed > 0 {
+				handleError(fmt.Errorf("%d of %d files failed to parse", failed, len(args)))
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&write, "write", false, "Rewrite the files in place instead of writing them to --output")
+
+	return cmd
+}
+
+// fmtFile formats the HCL of path, "-" being StdIn, to w or back to path
+// with write
+func fmtFile(path string, write bool) error {
+	var src []byte
+	var err error
+	if path == "-" {
+		src, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		src, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tfit.HCLFmt(bytes.NewReader(src), &buf); err != nil {
+		return err
+	}
+	buf.WriteString("\n")
+
+	if !write || path == "-" {
+		_, err := buf.WriteTo(w)
+		return err
+	}
+
+	if bytes.Equal(src, buf.Bytes()) {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), info.Mode())
+}
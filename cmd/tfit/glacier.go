@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdGlacier() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "glacier",
+		Short: "Glacier Vaults",
+		Run: func(cmd *cobra.Command, args []string) {
+			vaults, err := c.GetGlacierVaultsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(vaults))
+		},
+	}
+
+	return cmd
+}
@@ -14,6 +14,7 @@ func NewCmdIAM() *cobra.Command {
 	cmd.AddCommand(NewCmdIAMRole())
 	cmd.AddCommand(NewCmdIAMUser())
 	cmd.AddCommand(NewCmdIAMGroup())
+	cmd.AddCommand(NewCmdIAMInstanceProfile())
 
 	return cmd
 }
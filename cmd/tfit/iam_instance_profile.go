@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdIAMInstanceProfile() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "instance-profile",
+		Short: "IAM Instance Profiles",
+		Run: func(cmd *cobra.Command, args []string) {
+			profiles, err := c.ListInstanceProfilesWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(profiles))
+		},
+	}
+
+	return cmd
+}
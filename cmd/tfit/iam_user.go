@@ -9,9 +9,9 @@ func NewCmdIAMUser() *cobra.Command {
 		Use:   "user",
 		Short: "IAM Users",
 		Run: func(cmd *cobra.Command, args []string) {
-			users, err := c.ListUsers()
+			users, err := c.ListUsersWithContext(ctx)
 			handleError(err)
-			handleError(users.WriteHCL(w))
+			handleError(writeResource(users))
 		},
 	}
 
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdKinesis() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kinesis",
+		Short: "Kinesis Streams",
+		Run: func(cmd *cobra.Command, args []string) {
+			streams, err := c.GetKinesisStreamsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(streams))
+		},
+	}
+
+	return cmd
+}
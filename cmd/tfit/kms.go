@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdKMS() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kms",
+		Short: "KMS Keys & Aliases",
+		Run: func(cmd *cobra.Command, args []string) {
+			keys, err := c.GetKMSKeysWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(keys))
+		},
+	}
+
+	return cmd
+}
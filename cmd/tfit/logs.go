@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdLogs() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "CloudWatch Log Groups, Metric & Subscription Filters",
+		Run: func(cmd *cobra.Command, args []string) {
+			groups, err := c.GetLogGroupsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(groups))
+		},
+	}
+
+	return cmd
+}
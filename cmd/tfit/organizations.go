@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdOrganizations() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "organizations",
+		Short: "Organizations Accounts, listed from the management account only",
+		Run: func(cmd *cobra.Command, args []string) {
+			accounts, err := c.GetAccountsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(accounts))
+		},
+	}
+
+	return cmd
+}
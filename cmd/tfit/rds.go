@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdRDS() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rds",
+		Short: "RDS Related",
+	}
+
+	cmd.AddCommand(NewCmdDBSubnetGroup())
+	cmd.AddCommand(NewCmdDBParameterGroup())
+	cmd.AddCommand(NewCmdDBCluster())
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdDBCluster() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Aurora/RDS Clusters with their instances, except the ones being deleted",
+		Run: func(cmd *cobra.Command, args []string) {
+			clusters, err := c.GetDBClustersWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(clusters))
+		},
+	}
+
+	return cmd
+}
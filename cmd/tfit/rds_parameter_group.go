@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdDBParameterGroup() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "parameter-group",
+		Short: "DB Parameter Groups with their non default parameters, except the default groups",
+		Run: func(cmd *cobra.Command, args []string) {
+			groups, err := c.GetDBParameterGroupsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(groups))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdDBSubnetGroup() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subnet-group",
+		Short: "DB Subnet Groups",
+		Run: func(cmd *cobra.Command, args []string) {
+			groups, err := c.GetDBSubnetGroupsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(groups))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdRedshift() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "redshift",
+		Short: "Redshift Clusters",
+		Run: func(cmd *cobra.Command, args []string) {
+			clusters, err := c.GetRedshiftClustersWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(clusters))
+		},
+	}
+
+	return cmd
+}
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"log"
 	"os"
+	"os/signal"
+	"time"
 
 	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
 	"github.com/spf13/cobra"
@@ -14,9 +19,39 @@ type RootCmd struct {
 	cfg          tfit.Config
 }
 
+// Output formats of --format
+const (
+	formatHCL  = "hcl"
+	formatJSON = "json"
+)
+
 var c *tfit.AWSClient
 var output string
 var w io.Writer
+var imports string
+var debug bool
+var progress bool
+var validate bool
+var format string
+var tagFilter map[string]string
+var ids, excludeIDs []string
+var createdAfter string
+var decryptSecureParams bool
+var detailed bool
+var skipASGInstances bool
+var continueOnError bool
+var maxVPCRoutines int
+
+// partialErrors gathers the errors of the resources skipped with
+// --continue-on-error, they are reported once the command is done
+var partialErrors []error
+var tfVersion string
+var emitProvider bool
+var iw io.Writer
+
+// ctx is cancelled on the first SIGINT so that Ctrl-C stops a scan
+// between two API calls. A second SIGINT kills tfit right away
+var ctx, cancel = context.WithCancel(context.Background())
 
 var rootCommand = RootCmd{
 	cobraCommand: &cobra.Command{
@@ -25,32 +60,83 @@ var rootCommand = RootCmd{
 }
 
 func Execute() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		<-sig
+		signal.Stop(sig)
+		cancel()
+	}()
+
 	if err := rootCommand.cobraCommand.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+
+	if len(partialErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "%d resources failed and were skipped:\n", len(partialErrors))
+		for _, err := range partialErrors {
+			fmt.Fprintf(os.Stderr, "  %s\n", err)
+		}
+		os.Exit(1)
+	}
+
+	if validate {
+		fmt.Fprintln(os.Stderr, "The generated HCL is valid")
+	}
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
 	NewRootCmd()
 }
 
 func NewRootCmd() *cobra.Command {
 	cmd := rootCommand.cobraCommand
+	// Sub-commands which do not call AWS, e.g. fmt, override it
+	cmd.PersistentPreRun = func(cmd *cobra.Command, args []string) { initConfig() }
 
 	defaultAccesKey := os.Getenv("AWS_ACCESS_KEY_ID")
 	cmd.PersistentFlags().StringVar(&rootCommand.cfg.AccessKey, "access-key", defaultAccesKey, "AWS Access Key ID. Overrides AWS_ACCESS_KEY_ID environment variable")
 	defaultSecretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
 	cmd.PersistentFlags().StringVar(&rootCommand.cfg.SecretKey, "secret-key", defaultSecretKey, "AWS Secret Key. Overrides AWS_SECRET_ACCESS_KEY environment variable")
 
-	defaultRegion := os.Getenv("AWS_REGION")
-	cmd.PersistentFlags().StringVar(&rootCommand.cfg.Region, "region", defaultRegion, "AWS Region. Overrides AWS_REGION environment variable")
+	defaults := tfit.NewConfig()
+	rootCommand.cfg.CredsFile = defaults.CredsFile
+	cmd.PersistentFlags().StringVar(&rootCommand.cfg.Region, "region", defaults.Region, "AWS Region. Overrides AWS_REGION & AWS_DEFAULT_REGION environment variables")
 
 	defaultProfile := os.Getenv("AWS_PROFILE")
 	cmd.PersistentFlags().StringVar(&rootCommand.cfg.Profile, "profile", defaultProfile, "AWS Profile. Overrides AWS_PROFILE environment variable")
 
-	cmd.PersistentFlags().StringVar(&output, "output", "", "The output of HCL (Terraform config) contents (Default to StdOut)")
+	cmd.PersistentFlags().StringVar(&rootCommand.cfg.RoleARN, "role-arn", "", "IAM role assumed before calling the AWS APIs, e.g. for cross-account access")
+	cmd.PersistentFlags().StringVar(&rootCommand.cfg.ExternalID, "external-id", "", "External ID passed along when assuming --role-arn")
+	cmd.PersistentFlags().StringVar(&rootCommand.cfg.MFASerial, "mfa-serial", "", "MFA device required to assume --role-arn, its token code is asked on StdIn")
+	rootCommand.cfg.TokenProvider = mfaToken
+
+	cmd.PersistentFlags().StringVar(&rootCommand.cfg.Endpoint, "endpoint-url", "", "URL every AWS API is called at, e.g. http://localhost:4566 for LocalStack")
+	cmd.PersistentFlags().IntVar(&rootCommand.cfg.MaxRetries, "max-retries", defaults.MaxRetries, "Number of times a throttled or failed AWS API call is retried, with an exponential backoff (0 fails at once)")
+	cmd.PersistentFlags().StringVarP(&output, "output", "o", "", "The output of HCL (Terraform config) contents, truncated if it exists. StdOut when omitted or \"-\"")
+	cmd.PersistentFlags().StringVar(&format, "format", formatHCL, "Output format: \"hcl\", or \"json\" to write the collected resources for other tools (keys are the tfit field names)")
+	cmd.PersistentFlags().BoolVar(&validate, "validate", false, "Dry run: check that the generated HCL parses, reporting the resource & lines at fault, without writing anything")
+	cmd.PersistentFlags().BoolVar(&debug, "debug", false, "Print debug messages, e.g. pagination progress, to StdErr")
+	cmd.PersistentFlags().BoolVar(&progress, "progress", false, "Print the count of resources collected so far to StdErr, after every page of results")
+	cmd.PersistentFlags().StringVar(&imports, "with-imports", "", "Write terraform import commands for the exported resources to this file")
+	cmd.PersistentFlags().BoolVar(&tfit.RenderOpts.DataSources, "data-sources", false, "Emit aws_caller_identity & aws_region data sources and use them for the account ID & region of ARNs")
+	cmd.PersistentFlags().BoolVar(&tfit.RenderOpts.ProviderV4, "aws-provider-v4", false, "Follow the AWS provider v4+ conventions, e.g. separate resources for S3 bucket website, logging, versioning, encryption, lifecycle & replication")
+	cmd.PersistentFlags().BoolVar(&tfit.RenderOpts.HCL2, "hcl2", false, "Render Terraform 0.12+ syntax (tags = { ... }) instead of 0.11, implied by --tf-version 0.12+")
+	cmd.PersistentFlags().BoolVar(&emitProvider, "emit-provider", false, "Emit the provider \"aws\" block of the region, profile & role used. The blocks aliased per region/profile by the export command are always written")
+	cmd.PersistentFlags().StringVar(&tfVersion, "tf-version", "0.11", "Terraform version of the generated HCL, e.g. 0.13 (0.12+ renders the HCL2 syntax, 0.13+ adds the required_providers block)")
+	cmd.PersistentFlags().BoolVar(&tfit.RenderOpts.CommentID, "comment-id", false, "Emit a stable \"# tfit-id: <hash>\" comment above every resource")
+	cmd.PersistentFlags().StringToStringVar(&tagFilter, "filter-tag", nil, "Only export resources carrying this tag, e.g. Environment=prod (can be repeated, all must match)")
+	cmd.PersistentFlags().StringSliceVar(&ids, "ids", nil, "Only export the resources with these IDs, names or ARNs (comma separated, can be repeated)")
+	cmd.PersistentFlags().StringVar(&createdAfter, "created-after", "", "Only export the resources created after this RFC3339 time, e.g. 2020-01-31T00:00:00Z (resources whose API gives no creation time, e.g. security groups, are kept)")
+	cmd.PersistentFlags().StringSliceVar(&excludeIDs, "exclude-ids", nil, "Skip the resources with these IDs, names or ARNs (comma separated, can be repeated)")
+	cmd.PersistentFlags().BoolVar(&continueOnError, "continue-on-error", false, "Skip the instances & VPCs failing to be described, export the others and report the errors at the end")
+	cmd.PersistentFlags().BoolVar(&skipASGInstances, "skip-asg-instances", false, "Skip the instances launched by an auto scaling group (tagged with aws:autoscaling:groupName), which manages them already")
+	cmd.PersistentFlags().BoolVar(&decryptSecureParams, "decrypt-secure-params", false, "Write the decrypted values of SSM SecureString parameters instead of a placeholder")
+	cmd.PersistentFlags().IntVar(&maxVPCRoutines, "max-vpc-routines", tfit.DefaultVPCRoutines, "Number of VPCs whose attributes are looked up concurrently, lower it when the EC2 API throttles")
+	cmd.PersistentFlags().BoolVar(&detailed, "detailed", false, "Fetch the attributes costing an API call per resource, e.g. the termination protection & shutdown behavior of instances")
+	cmd.PersistentFlags().BoolVar(&tfit.RenderOpts.SecretPlaceholders, "secret-placeholders", false, "Emit an aws_secretsmanager_secret_version with a placeholder value for every secret")
+	cmd.PersistentFlags().StringToStringVar(&tfit.RenderOpts.ExtraTags, "inject-tag", nil, "Tag added to every exported resource which supports tags, e.g. ManagedBy=terraform (can be repeated)")
 
 	// Sub-commands
 	cmd.AddCommand(NewCmdEC2())
@@ -59,21 +145,204 @@ func NewRootCmd() *cobra.Command {
 	cmd.AddCommand(NewCmdS3())
 	cmd.AddCommand(NewCmdAutoScaling())
 	cmd.AddCommand(NewCmdELB())
+	cmd.AddCommand(NewCmdKMS())
+	cmd.AddCommand(NewCmdLogs())
+	cmd.AddCommand(NewCmdCloudWatch())
+	cmd.AddCommand(NewCmdECR())
+	cmd.AddCommand(NewCmdElastiCache())
+	cmd.AddCommand(NewCmdSSM())
+	cmd.AddCommand(NewCmdSecretsManager())
+	cmd.AddCommand(NewCmdEFS())
+	cmd.AddCommand(NewCmdACM())
+	cmd.AddCommand(NewCmdAPIGateway())
+	cmd.AddCommand(NewCmdRedshift())
+	cmd.AddCommand(NewCmdECS())
+	cmd.AddCommand(NewCmdCloudFront())
+	cmd.AddCommand(NewCmdDynamoDB())
+	cmd.AddCommand(NewCmdBeanstalk())
+	cmd.AddCommand(NewCmdKinesis())
+	cmd.AddCommand(NewCmdCloudTrail())
+	cmd.AddCommand(NewCmdSES())
+	cmd.AddCommand(NewCmdSFN())
+	cmd.AddCommand(NewCmdGlacier())
+	cmd.AddCommand(NewCmdCodeBuild())
+	cmd.AddCommand(NewCmdRDS())
+	cmd.AddCommand(NewCmdOrganizations())
+	cmd.AddCommand(NewCmdExport())
+	cmd.AddCommand(NewCmdFmt())
 
 	return cmd
 }
 
+// initConfig creates the AWS client and opens the output & imports files
+// of the commands exporting resources
 func initConfig() {
 	var err error
-	c, err = rootCommand.cfg.Client()
+	c, err = newClient(rootCommand.cfg)
 	handleError(err)
 
-	if len(output) == 0 {
-		w = os.Stdout
+	handleError(tfit.RenderOpts.SetTFVersion(tfVersion))
+
+	if format != formatHCL && format != formatJSON {
+		handleError(fmt.Errorf("Unknown output format %q, expected %q or %q", format, formatHCL, formatJSON))
+	}
+	if validate && format == formatJSON {
+		handleError(fmt.Errorf("--validate checks the generated HCL, it can not be used with --format %s", formatJSON))
+	}
+
+	// Nothing is written by a dry run, rendering alone validates the HCL
+	if validate {
+		w = ioutil.Discard
+		imports = ""
 	} else {
-		w, err = os.OpenFile(output, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+		w, err = openOutput()
 		handleError(err)
 	}
+
+	tfit.RenderOpts.Region = c.Region()
+	if tfit.RenderOpts.DataSources {
+		accountId, err := rootCommand.cfg.GetAccountIdWithContext(ctx)
+		handleError(err)
+		tfit.RenderOpts.AccountID = *accountId
+	}
+
+	if len(imports) > 0 {
+		iw, err = os.OpenFile(imports, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0755)
+		handleError(err)
+		_, err = fmt.Fprint(iw, "#!/bin/sh\n")
+		handleError(err)
+	}
+}
+
+// openOutput returns StdOut, or the --output file truncated
+func openOutput() (io.Writer, error) {
+	if len(output) == 0 || output == "-" {
+		return os.Stdout, nil
+	}
+
+	return os.OpenFile(output, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+}
+
+// newClient creates the AWSClient of cfg, logging to StdErr with --debug
+// and reporting its progress there with --progress
+func newClient(cfg tfit.Config) (*tfit.AWSClient, error) {
+	client, err := cfg.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	if debug {
+		client.Logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
+	if progress {
+		region := client.Region()
+		client.OnProgress = func(resourceType string, count int) {
+			fmt.Fprintf(os.Stderr, "%s %s: %d so far\n", region, resourceType, count)
+		}
+	}
+	client.TagFilter = tagFilter
+	client.IDs = ids
+	client.ExcludeIDs = excludeIDs
+	if createdAfter != "" {
+		client.CreatedAfter, err = time.Parse(time.RFC3339, createdAfter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --created-after: %v", err)
+		}
+	}
+	client.DecryptSecureParams = decryptSecureParams
+	client.Detailed = detailed
+	client.SkipASGInstances = skipASGInstances
+	client.ContinueOnError = continueOnError
+	client.MaxVPCRoutines = maxVPCRoutines
+	return client, nil
+}
+
+// newProvider returns the provider block matching cfg. Credentials are
+// never written, the provider finds them like tfit does
+func newProvider(cfg tfit.Config) tfit.Provider {
+	return tfit.Provider{
+		Region:     cfg.Region,
+		Profile:    cfg.Profile,
+		RoleARN:    cfg.RoleARN,
+		ExternalID: cfg.ExternalID,
+	}
+}
+
+// mfaToken asks the MFA token code of --mfa-serial. The prompt goes to
+// StdErr, StdOut may be the HCL output
+func mfaToken() (string, error) {
+	var code string
+	fmt.Fprintf(os.Stderr, "MFA token code for %s: ", rootCommand.cfg.MFASerial)
+	_, err := fmt.Scanln(&code)
+	return code, err
+}
+
+// resource is a collection of exported resources
+type resource interface {
+	WriteHCL(w io.Writer) error
+	WriteImports(w io.Writer) error
+}
+
+// writeResource writes the HCL, or JSON, of res to the output and, when
+// --with-imports is set, its terraform import commands to the imports file
+func writeResource(res resource) error {
+	if format == formatJSON {
+		if err := tfit.WriteJSON(w, res); err != nil {
+			return err
+		}
+	} else if err := writeHCL(res); err != nil {
+		return err
+	}
+
+	if iw == nil {
+		return nil
+	}
+	return res.WriteImports(iw)
+}
+
+// writeHCL writes the HCL of res, preceded by the terraform block with
+// --tf-version 0.13+, the provider block with --emit-provider and the data
+// sources with --data-sources, to the output
+func writeHCL(res resource) error {
+	if tfit.TFVersionAtLeast("0.13") {
+		if err := tfit.WriteTerraformBlock(w); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprint(w, "\n\n"); err != nil {
+			return err
+		}
+	}
+
+	if emitProvider {
+		if err := tfit.WriteProvider(w, newProvider(rootCommand.cfg)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprint(w, "\n\n"); err != nil {
+			return err
+		}
+	}
+
+	if tfit.RenderOpts.DataSources {
+		if err := tfit.WriteDataSources(w); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprint(w, "\n\n"); err != nil {
+			return err
+		}
+	}
+
+	return res.WriteHCL(w)
+}
+
+// handlePartialError is handleError, except for the *tfit.MultiError
+// returned along with the resources fetched with --continue-on-error,
+// whose errors are kept for the end of the command
+func handlePartialError(err error) {
+	if merr, ok := err.(*tfit.MultiError); ok {
+		partialErrors = append(partialErrors, merr.Errors...)
+		return
+	}
+	handleError(err)
 }
 
 func handleError(err error) {
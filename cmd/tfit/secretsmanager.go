@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdSecretsManager() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secretsmanager",
+		Short: "Secrets Manager Secrets (metadata only)",
+		Run: func(cmd *cobra.Command, args []string) {
+			secrets, err := c.GetSecretsWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(secrets))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdSES() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ses",
+		Short: "SES Domain & Email Identities",
+		Run: func(cmd *cobra.Command, args []string) {
+			identities, err := c.GetSESIdentitiesWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(identities))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdSFN() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sfn",
+		Short: "Step Functions State Machines",
+		Run: func(cmd *cobra.Command, args []string) {
+			machines, err := c.GetStateMachinesWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(machines))
+		},
+	}
+
+	return cmd
+}
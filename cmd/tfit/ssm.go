@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdSSM() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ssm",
+		Short: "SSM Parameters",
+		Run: func(cmd *cobra.Command, args []string) {
+			parameters, err := c.GetSSMParametersWithContext(ctx)
+			handleError(err)
+			handleError(writeResource(parameters))
+		},
+	}
+
+	return cmd
+}
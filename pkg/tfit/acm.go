@@ -0,0 +1,197 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+)
+
+//**************** ACM Certificate ****************
+type ACMCertificate struct {
+	ARN                     *string
+	Type                    *string
+	DomainName              *string
+	SubjectAlternativeNames []*string
+	ValidationMethod        *string
+	CertificateAuthorityARN *string
+	Tags                    *Tags
+
+	// DNS records proving the ownership of the domains,
+	// for the certificates validated by DNS
+	ValidationRecords []*acm.ResourceRecord
+}
+
+type ACMCertificates []*ACMCertificate
+
+func (a *ACMCertificate) set(src *acm.CertificateDetail) {
+	a.ARN = src.CertificateArn
+	a.Type = src.Type
+	a.DomainName = src.DomainName
+	a.CertificateAuthorityARN = src.CertificateAuthorityArn
+
+	// The domain name is reported as an alternative name as well
+	for _, v := range src.SubjectAlternativeNames {
+		if aws.StringValue(v) != aws.StringValue(src.DomainName) {
+			a.SubjectAlternativeNames = append(a.SubjectAlternativeNames, v)
+		}
+	}
+
+	for _, v := range src.DomainValidationOptions {
+		if v == nil {
+			continue
+		}
+		if a.ValidationMethod == nil {
+			a.ValidationMethod = v.ValidationMethod
+		}
+		if v.ResourceRecord != nil {
+			a.ValidationRecords = append(a.ValidationRecords, v.ResourceRecord)
+		}
+	}
+	// Certificates requested before DNS validation only report their emails
+	if a.ValidationMethod == nil {
+		a.ValidationMethod = aws.String(acm.ValidationMethodEmail)
+	}
+}
+
+func (a *ACMCertificate) getTags(ctx aws.Context, c *AWSClient) error {
+	output, err := c.acmconn.ListTagsForCertificateWithContext(ctx, &acm.ListTagsForCertificateInput{CertificateArn: a.ARN})
+	if err != nil {
+		return err
+	}
+
+	a.Tags = &Tags{}
+	for _, v := range output.Tags {
+		if v != nil && v.Key != nil {
+			(*a.Tags)[*v.Key] = v.Value
+		}
+	}
+
+	return nil
+}
+
+// GetACMCertificatesWithContext returns the certificates which are not expired
+func (c *AWSClient) GetACMCertificatesWithContext(ctx aws.Context) (*ACMCertificates, error) {
+	var res ACMCertificates
+
+	opt := &acm.ListCertificatesInput{}
+	for {
+		out, err := c.acmconn.ListCertificatesWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.CertificateSummaryList {
+			if v == nil || !c.wantID(v.CertificateArn, v.DomainName) {
+				continue
+			}
+
+			desc, err := c.acmconn.DescribeCertificateWithContext(ctx, &acm.DescribeCertificateInput{
+				CertificateArn: v.CertificateArn,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if desc.Certificate == nil || aws.StringValue(desc.Certificate.Status) == acm.CertificateStatusExpired {
+				continue
+			}
+
+			// Imported certificates have no creation time
+			created := desc.Certificate.CreatedAt
+			if created == nil {
+				created = desc.Certificate.ImportedAt
+			}
+			if !c.wantCreated(created) {
+				continue
+			}
+
+			tmp := &ACMCertificate{}
+			tmp.set(desc.Certificate)
+			if err := tmp.getTags(ctx, c); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_acm_certificate", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetACMCertificates calls GetACMCertificatesWithContext with a background context
+func (c *AWSClient) GetACMCertificates() (*ACMCertificates, error) {
+	return c.GetACMCertificatesWithContext(aws.BackgroundContext())
+}
+
+func (a *ACMCertificates) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":                joinStringSlice,
+		"StringValueSlice":          aws.StringValueSlice,
+		"StringValue":               aws.StringValue,
+		"makeTerraformResourceName": makeTerraformResourceName,
+		"arnName":                   arnName,
+	}
+
+	// ACM never returns the private key of an imported certificate, its
+	// key & body are placeholders to fill in
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_acm_certificate" .ARN }}
+    resource "aws_acm_certificate" "{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName .DomainName) (arnName .ARN)) }}" {
+      {{- if eq (StringValue .Type) "IMPORTED" }}
+      # WARNING: imported certificate, its private key & body can not be read, set them before applying
+      private_key = "REDACTED"
+      certificate_body = "REDACTED"
+      {{- else }}
+      domain_name = "{{ .DomainName }}"
+      {{- if .SubjectAlternativeNames }}
+      subject_alternative_names = [{{ StringValueSlice .SubjectAlternativeNames | joinstring "," }}]
+      {{- end }}
+      {{- if .CertificateAuthorityARN }}
+      certificate_authority_arn = "{{ .CertificateAuthorityARN }}"
+      {{- else }}
+      {{- range .ValidationRecords }}
+      # DNS validation record, see aws_acm_certificate_validation: {{ .Type }} {{ .Name }} {{ .Value }}
+      {{- end }}
+      validation_method = "{{ .ValidationMethod }}"
+      {{- end }}
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, a)
+}
+
+// WriteImports writes the terraform import commands of 'ACMCertificates'
+func (a *ACMCertificates) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformResourceName": makeTerraformResourceName,
+		"arnName":                   arnName,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_acm_certificate.{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName .DomainName) (arnName .ARN)) }} {{ .ARN }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, a)
+}
@@ -0,0 +1,354 @@
+package tfit
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+)
+
+//**************** API Gateway REST API ****************
+type RestAPI struct {
+	ID          *string
+	Name        *string
+	Description *string
+	Tags        *Tags
+
+	// ID of the resource "/", created along with the API
+	RootResourceID *string
+
+	// Sorted by path, the root resource first
+	Resources []*APIResource
+}
+
+type RestAPIs []*RestAPI
+
+type APIResource struct {
+	ID       *string
+	Path     *string
+	PathPart *string
+
+	// ID & path of the parent resource, nil for the root resource
+	ParentID   *string
+	ParentPath *string
+
+	// Sorted by HTTP method
+	Methods []*APIMethod
+}
+
+type APIMethod struct {
+	HTTPMethod        *string
+	AuthorizationType *string
+	AuthorizerID      *string
+	APIKeyRequired    *bool
+	Integration       *APIIntegration
+}
+
+type APIIntegration struct {
+	Type                  *string
+	IntegrationHTTPMethod *string
+	URI                   *string
+	ConnectionType        *string
+	ConnectionID          *string
+	Credentials           *string
+	PassthroughBehavior   *string
+	TimeoutInMillis       *int64
+}
+
+// getResources walks the resources of the API, their methods &
+// integrations embedded
+func (r *RestAPI) getResources(ctx aws.Context, c *AWSClient) error {
+	var resources []*apigateway.Resource
+
+	opt := &apigateway.GetResourcesInput{
+		RestApiId: r.ID,
+		Embed:     []*string{aws.String("methods")},
+	}
+	for {
+		out, err := c.apigatewayconn.GetResourcesWithContext(ctx, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.Items {
+			if v != nil {
+				resources = append(resources, v)
+			}
+		}
+
+		if out.Position == nil {
+			break
+		}
+		opt.Position = out.Position
+	}
+
+	paths := make(map[string]*string)
+	for _, v := range resources {
+		paths[aws.StringValue(v.Id)] = v.Path
+	}
+
+	for _, v := range resources {
+		tmp := &APIResource{
+			ID:       v.Id,
+			Path:     v.Path,
+			PathPart: v.PathPart,
+		}
+		if v.ParentId != nil {
+			tmp.ParentID = v.ParentId
+			tmp.ParentPath = paths[*v.ParentId]
+		} else {
+			r.RootResourceID = v.Id
+		}
+
+		for _, m := range v.ResourceMethods {
+			if m == nil {
+				continue
+			}
+			method := &APIMethod{
+				HTTPMethod:        m.HttpMethod,
+				AuthorizationType: m.AuthorizationType,
+				AuthorizerID:      m.AuthorizerId,
+				APIKeyRequired:    m.ApiKeyRequired,
+			}
+			if i := m.MethodIntegration; i != nil {
+				method.Integration = &APIIntegration{
+					Type:                  i.Type,
+					IntegrationHTTPMethod: i.HttpMethod,
+					URI:                   i.Uri,
+					ConnectionType:        i.ConnectionType,
+					ConnectionID:          i.ConnectionId,
+					Credentials:           i.Credentials,
+					PassthroughBehavior:   i.PassthroughBehavior,
+					TimeoutInMillis:       i.TimeoutInMillis,
+				}
+			}
+			tmp.Methods = append(tmp.Methods, method)
+		}
+		sort.Slice(tmp.Methods, func(i, j int) bool {
+			return aws.StringValue(tmp.Methods[i].HTTPMethod) < aws.StringValue(tmp.Methods[j].HTTPMethod)
+		})
+
+		r.Resources = append(r.Resources, tmp)
+	}
+
+	// The root resource "/" sorts first, and every parent before its children
+	sort.Slice(r.Resources, func(i, j int) bool {
+		return aws.StringValue(r.Resources[i].Path) < aws.StringValue(r.Resources[j].Path)
+	})
+
+	return nil
+}
+
+// getTags fetches the tags of the API, GetRestApis leaves them out
+func (r *RestAPI) getTags(ctx aws.Context, c *AWSClient) error {
+	arn := fmt.Sprintf("arn:%s:apigateway:%s::/restapis/%s", c.partition(), c.region, aws.StringValue(r.ID))
+	out, err := c.apigatewayconn.GetTagsWithContext(ctx, &apigateway.GetTagsInput{ResourceArn: aws.String(arn)})
+	if err != nil {
+		return err
+	}
+
+	r.Tags = &Tags{}
+	for k, v := range out.Tags {
+		(*r.Tags)[k] = v
+	}
+
+	return nil
+}
+
+func (c *AWSClient) GetRestAPIsWithContext(ctx aws.Context) (*RestAPIs, error) {
+	var res RestAPIs
+
+	opt := &apigateway.GetRestApisInput{}
+	for {
+		out, err := c.apigatewayconn.GetRestApisWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Items {
+			if v == nil || !c.wantID(v.Id, v.Name) {
+				continue
+			}
+
+			tmp := &RestAPI{
+				ID:          v.Id,
+				Name:        v.Name,
+				Description: v.Description,
+			}
+			if err := tmp.getTags(ctx, c); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+
+			if err := tmp.getResources(ctx, c); err != nil {
+				return nil, err
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_api_gateway_rest_api", len(res))
+
+		if out.Position == nil {
+			break
+		}
+		opt.Position = out.Position
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return aws.StringValue(res[i].ID) < aws.StringValue(res[j].ID)
+	})
+
+	return &res, nil
+}
+
+// GetRestAPIs calls GetRestAPIsWithContext with a background context
+func (c *AWSClient) GetRestAPIs() (*RestAPIs, error) {
+	return c.GetRestAPIsWithContext(aws.BackgroundContext())
+}
+
+func (r *RestAPIs) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"BoolValue":                 aws.BoolValue,
+		"StringValue":               aws.StringValue,
+		"makeTerraformResourceName": makeTerraformResourceName,
+		"pathName":                  apiPathName,
+	}
+
+	// The root resource is created along with the API, its methods & child
+	// resources hang off root_resource_id. Other resources are referenced
+	// by their label, which is derived from their path. Labels are built
+	// from the raw API name, sanitizeName prefixing them once
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{- $name := printf "%s_%s" (makeTerraformResourceName .Name) (StringValue .ID) }}
+    {{- $api := sanitizeName $name }}
+    {{- $root := StringValue .RootResourceID }}
+    {{ resourceID "aws_api_gateway_rest_api" .ID }}
+    resource "aws_api_gateway_rest_api" "{{ $api }}" {
+      name = "{{ .Name }}"
+      {{- if .Description }}
+      description = {{ hclString .Description }}
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- range .Resources }}
+    {{- $label := sanitizeName (printf "%s_%s" $name (pathName .Path)) }}
+    {{- $resourceID := printf "${aws_api_gateway_resource.%s.id}" $label }}
+    {{- if .ParentID }}
+
+    resource "aws_api_gateway_resource" "{{ $label }}" {
+      rest_api_id = "${aws_api_gateway_rest_api.{{ $api }}.id}"
+      {{- if eq (StringValue .ParentID) $root }}
+      parent_id = "${aws_api_gateway_rest_api.{{ $api }}.root_resource_id}"
+      {{- else }}
+      parent_id = "${aws_api_gateway_resource.{{ sanitizeName (printf "%s_%s" $name (pathName .ParentPath)) }}.id}"
+      {{- end }}
+      path_part = "{{ .PathPart }}"
+    }
+    {{- else }}
+    {{- $resourceID = printf "${aws_api_gateway_rest_api.%s.root_resource_id}" $api }}
+    {{- end }}
+    {{- range .Methods }}
+    {{- $method := printf "%s_%s" $label (StringValue .HTTPMethod) }}
+
+    resource "aws_api_gateway_method" "{{ $method }}" {
+      rest_api_id = "${aws_api_gateway_rest_api.{{ $api }}.id}"
+      resource_id = "{{ $resourceID }}"
+      http_method = "{{ .HTTPMethod }}"
+      authorization = "{{ .AuthorizationType }}"
+      {{- if .AuthorizerID }}
+      authorizer_id = "{{ .AuthorizerID }}"
+      {{- end }}
+      {{- if BoolValue .APIKeyRequired }}
+      api_key_required = true
+      {{- end }}
+    }
+    {{- with .Integration }}
+
+    resource "aws_api_gateway_integration" "{{ $method }}" {
+      rest_api_id = "${aws_api_gateway_rest_api.{{ $api }}.id}"
+      resource_id = "{{ $resourceID }}"
+      http_method = "${aws_api_gateway_method.{{ $method }}.http_method}"
+      type = "{{ .Type }}"
+      {{- if .IntegrationHTTPMethod }}
+      integration_http_method = "{{ .IntegrationHTTPMethod }}"
+      {{- end }}
+      {{- if .URI }}
+      uri = {{ hclString .URI }}
+      {{- end }}
+      {{- if .ConnectionType }}
+      connection_type = "{{ .ConnectionType }}"
+      {{- end }}
+      {{- if .ConnectionID }}
+      connection_id = "{{ .ConnectionID }}"
+      {{- end }}
+      {{- if .Credentials }}
+      credentials = "{{ .Credentials }}"
+      {{- end }}
+      {{- if .PassthroughBehavior }}
+      passthrough_behavior = "{{ .PassthroughBehavior }}"
+      {{- end }}
+      {{- if .TimeoutInMillis }}
+      timeout_milliseconds = {{ .TimeoutInMillis }}
+      {{- end }}
+    }
+    {{- end }}
+    {{- end }}
+    {{- end }}
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, r)
+}
+
+// WriteImports writes the terraform import commands of 'RestAPIs'
+func (r *RestAPIs) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"StringValue":               aws.StringValue,
+		"makeTerraformResourceName": makeTerraformResourceName,
+		"pathName":                  apiPathName,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+{{ $name := printf "%s_%s" (makeTerraformResourceName .Name) (StringValue .ID) -}}
+{{ $apiID := .ID -}}
+terraform import aws_api_gateway_rest_api.{{ sanitizeName $name }} {{ .ID }}
+{{ range .Resources -}}
+{{ $label := sanitizeName (printf "%s_%s" $name (pathName .Path)) -}}
+{{ if .ParentID -}}
+terraform import aws_api_gateway_resource.{{ $label }} {{ $apiID }}/{{ .ID }}
+{{ end -}}
+{{ $resourceID := .ID -}}
+{{ range .Methods -}}
+terraform import aws_api_gateway_method.{{ $label }}_{{ .HTTPMethod }} {{ $apiID }}/{{ $resourceID }}/{{ .HTTPMethod }}
+{{ if .Integration -}}
+terraform import aws_api_gateway_integration.{{ $label }}_{{ .HTTPMethod }} {{ $apiID }}/{{ $resourceID }}/{{ .HTTPMethod }}
+{{ end }}{{ end }}{{ end }}{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, r)
+}
+
+// apiPathName returns the path of an API resource as part of a resource
+// label: "root" for "/", e.g. "users-{id}" for "/users/{id}"
+func apiPathName(path *string) string {
+	trimmed := strings.Trim(aws.StringValue(path), "/")
+	if len(trimmed) == 0 {
+		return "root"
+	}
+
+	return makeTerraformResourceName(aws.String(trimmed))
+}
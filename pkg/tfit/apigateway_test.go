@@ -0,0 +1,79 @@
+package tfit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestRestAPIsWriteHCL(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{LabelPrefix: "prod_"}
+
+	get := func(uri string) []*APIMethod {
+		return []*APIMethod{{
+			HTTPMethod:        aws.String("GET"),
+			AuthorizationType: aws.String("NONE"),
+			Integration: &APIIntegration{
+				Type:                  aws.String("HTTP"),
+				IntegrationHTTPMethod: aws.String("GET"),
+				URI:                   aws.String(uri),
+			},
+		}}
+	}
+	apis := &RestAPIs{{
+		ID:             aws.String("abc"),
+		Name:           aws.String("shop"),
+		RootResourceID: aws.String("r0"),
+		Resources: []*APIResource{
+			{ID: aws.String("r0"), Path: aws.String("/")},
+			// A resource named "root" is not the root resource
+			{ID: aws.String("r1"), Path: aws.String("/root"), PathPart: aws.String("root"), ParentID: aws.String("r0"), ParentPath: aws.String("/")},
+			{
+				ID:         aws.String("r2"),
+				Path:       aws.String("/root/items"),
+				PathPart:   aws.String("items"),
+				ParentID:   aws.String("r1"),
+				ParentPath: aws.String("/root"),
+				Methods:    get(`http://${stageVariables.host}/items?q="x"`),
+			},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := apis.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	out := strings.Join(strings.Fields(buf.String()), " ")
+
+	for _, want := range []string{
+		`resource "aws_api_gateway_rest_api" "prod_shop_abc"`,
+		`resource "aws_api_gateway_resource" "prod_shop_abc_root" { rest_api_id = "${aws_api_gateway_rest_api.prod_shop_abc.id}" parent_id = "${aws_api_gateway_rest_api.prod_shop_abc.root_resource_id}"`,
+		`resource "aws_api_gateway_resource" "prod_shop_abc_root-items" { rest_api_id = "${aws_api_gateway_rest_api.prod_shop_abc.id}" parent_id = "${aws_api_gateway_resource.prod_shop_abc_root.id}"`,
+		`resource "aws_api_gateway_method" "prod_shop_abc_root-items_GET"`,
+		`uri = "http://$${stageVariables.host}/items?q=\"x\""`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want %s in:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(out, "prod_prod_") {
+		t.Errorf("label prefixed twice in:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := apis.WriteImports(&buf); err != nil {
+		t.Fatalf("WriteImports: %v", err)
+	}
+	for _, want := range []string{
+		"terraform import aws_api_gateway_rest_api.prod_shop_abc abc",
+		"terraform import aws_api_gateway_resource.prod_shop_abc_root abc/r1",
+		"terraform import aws_api_gateway_integration.prod_shop_abc_root-items_GET abc/r2/GET",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("want %s in:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package tfit
+
+import (
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
+)
+
+//**************** Application Auto Scaling Target ****************
+type AppAutoScalingTarget struct {
+	ServiceNamespace  *string
+	ResourceID        *string
+	ScalableDimension *string
+	MinCapacity       *int64
+	MaxCapacity       *int64
+	RoleARN           *string
+
+	// Scaling policies of the target
+	Policies []*applicationautoscaling.ScalingPolicy
+}
+
+type AppAutoScalingTargets []*AppAutoScalingTarget
+
+// appAutoScalingName returns the Terraform resource name of the scalable
+// dimension of a resource, e.g. "Orders_ReadCapacityUnits" for the read
+// capacity of the table/Orders DynamoDB table
+func appAutoScalingName(resourceID, dimension *string) string {
+	name := strings.TrimPrefix(aws.StringValue(resourceID), "table/")
+	tokens := strings.Split(aws.StringValue(dimension), ":")
+	return makeTerraformResourceName(aws.String(name)) + "_" + tokens[len(tokens)-1]
+}
+
+// GetDynamoDBScalableTargetsWithContext returns the scalable targets of
+// the DynamoDB tables & indexes with their scaling policies. Tables
+// without auto scaling have none
+func (c *AWSClient) GetDynamoDBScalableTargetsWithContext(ctx aws.Context) (*AppAutoScalingTargets, error) {
+	// Scalable targets carry no tags, TagFilter excludes all of them
+	if c.untaggable() {
+		return &AppAutoScalingTargets{}, nil
+	}
+
+	namespace := aws.String(applicationautoscaling.ServiceNamespaceDynamodb)
+
+	res := AppAutoScalingTargets{}
+	targets := make(map[string]*AppAutoScalingTarget)
+	opt := &applicationautoscaling.DescribeScalableTargetsInput{ServiceNamespace: namespace}
+	for {
+		out, err := c.appautoscalingconn.DescribeScalableTargetsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.ScalableTargets {
+			if v == nil || !c.wantID(v.ResourceId) {
+				continue
+			}
+
+			tmp := &AppAutoScalingTarget{
+				ServiceNamespace:  v.ServiceNamespace,
+				ResourceID:        v.ResourceId,
+				ScalableDimension: v.ScalableDimension,
+				MinCapacity:       v.MinCapacity,
+				MaxCapacity:       v.MaxCapacity,
+				RoleARN:           v.RoleARN,
+			}
+			targets[aws.StringValue(v.ResourceId)+"|"+aws.StringValue(v.ScalableDimension)] = tmp
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_appautoscaling_target", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	if len(res) == 0 {
+		return &res, nil
+	}
+
+	policyOpt := &applicationautoscaling.DescribeScalingPoliciesInput{ServiceNamespace: namespace}
+	for {
+		out, err := c.appautoscalingconn.DescribeScalingPoliciesWithContext(ctx, policyOpt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.ScalingPolicies {
+			if v == nil {
+				continue
+			}
+			if target, ok := targets[aws.StringValue(v.ResourceId)+"|"+aws.StringValue(v.ScalableDimension)]; ok {
+				target.Policies = append(target.Policies, v)
+			}
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		policyOpt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetDynamoDBScalableTargets calls GetDynamoDBScalableTargetsWithContext with a background context
+func (c *AWSClient) GetDynamoDBScalableTargets() (*AppAutoScalingTargets, error) {
+	return c.GetDynamoDBScalableTargetsWithContext(aws.BackgroundContext())
+}
+
+func (a *AppAutoScalingTargets) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"BoolValue":                 aws.BoolValue,
+		"appAutoScalingName":        appAutoScalingName,
+		"makeTerraformResourceName": makeTerraformResourceName,
+	}
+
+	// The policies reference their target, created first
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{- $name := appAutoScalingName .ResourceID .ScalableDimension }}
+    {{- $label := sanitizeName $name }}
+    {{ resourceID "aws_appautoscaling_target" .ServiceNamespace .ResourceID .ScalableDimension }}
+    resource "aws_appautoscaling_target" "{{ $label }}" {
+      service_namespace = "{{ .ServiceNamespace }}"
+      resource_id = "{{ .ResourceID }}"
+      scalable_dimension = "{{ .ScalableDimension }}"
+      min_capacity = {{ .MinCapacity }}
+      max_capacity = {{ .MaxCapacity }}
+      {{- if .RoleARN }}
+      role_arn = "{{ .RoleARN }}"
+      {{- end }}
+    }
+    {{- range .Policies }}
+
+    resource "aws_appautoscaling_policy" "{{ printf "%s_%s" $name (makeTerraformResourceName .PolicyName) | sanitizeName }}" {
+      name = "{{ .PolicyName }}"
+      policy_type = "{{ .PolicyType }}"
+      service_namespace = "${aws_appautoscaling_target.{{ $label }}.service_namespace}"
+      resource_id = "${aws_appautoscaling_target.{{ $label }}.resource_id}"
+      scalable_dimension = "${aws_appautoscaling_target.{{ $label }}.scalable_dimension}"
+      {{- with .TargetTrackingScalingPolicyConfiguration }}
+
+      target_tracking_scaling_policy_configuration {
+        target_value = {{ .TargetValue }}
+        {{- if BoolValue .DisableScaleIn }}
+        disable_scale_in = true
+        {{- end }}
+        {{- if .ScaleInCooldown }}
+        scale_in_cooldown = {{ .ScaleInCooldown }}
+        {{- end }}
+        {{- if .ScaleOutCooldown }}
+        scale_out_cooldown = {{ .ScaleOutCooldown }}
+        {{- end }}
+        {{- with .PredefinedMetricSpecification }}
+
+        predefined_metric_specification {
+          predefined_metric_type = "{{ .PredefinedMetricType }}"
+          {{- if .ResourceLabel }}
+          resource_label = "{{ .ResourceLabel }}"
+          {{- end }}
+        }
+        {{- end }}
+        {{- with .CustomizedMetricSpecification }}
+
+        customized_metric_specification {
+          metric_name = "{{ .MetricName }}"
+          namespace = "{{ .Namespace }}"
+          statistic = "{{ .Statistic }}"
+          {{- if .Unit }}
+          unit = "{{ .Unit }}"
+          {{- end }}
+          {{- range .Dimensions }}
+          dimensions {
+            name = "{{ .Name }}"
+            value = "{{ .Value }}"
+          }
+          {{- end }}
+        }
+        {{- end }}
+      }
+      {{- end }}
+      {{- with .StepScalingPolicyConfiguration }}
+
+      step_scaling_policy_configuration {
+        {{- if .AdjustmentType }}
+        adjustment_type = "{{ .AdjustmentType }}"
+        {{- end }}
+        {{- if .Cooldown }}
+        cooldown = {{ .Cooldown }}
+        {{- end }}
+        {{- if .MetricAggregationType }}
+        metric_aggregation_type = "{{ .MetricAggregationType }}"
+        {{- end }}
+        {{- if .MinAdjustmentMagnitude }}
+        min_adjustment_magnitude = {{ .MinAdjustmentMagnitude }}
+        {{- end }}
+        {{- range .StepAdjustments }}
+        step_adjustment {
+          scaling_adjustment = {{ .ScalingAdjustment }}
+          {{- if .MetricIntervalLowerBound }}
+          metric_interval_lower_bound = {{ .MetricIntervalLowerBound }}
+          {{- end }}
+          {{- if .MetricIntervalUpperBound }}
+          metric_interval_upper_bound = {{ .MetricIntervalUpperBound }}
+          {{- end }}
+        }
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, a)
+}
+
+// WriteImports writes the terraform import commands of 'AppAutoScalingTargets'
+// and of their policies
+func (a *AppAutoScalingTargets) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"StringValue":               aws.StringValue,
+		"appAutoScalingName":        appAutoScalingName,
+		"makeTerraformResourceName": makeTerraformResourceName,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+{{ $name := appAutoScalingName .ResourceID .ScalableDimension -}}
+{{ $id := printf "%s/%s/%s" (StringValue .ServiceNamespace) (StringValue .ResourceID) (StringValue .ScalableDimension) -}}
+terraform import aws_appautoscaling_target.{{ sanitizeName $name }} {{ $id }}
+{{ range .Policies -}}
+terraform import aws_appautoscaling_policy.{{ printf "%s_%s" $name (makeTerraformResourceName .PolicyName) | sanitizeName }} {{ $id }}/{{ .PolicyName }}
+{{ end -}}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, a)
+}
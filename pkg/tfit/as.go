@@ -54,17 +54,23 @@ func (g *Group) parseVPCZoneIdentifier(src *string) {
 }
 
 func (g *Group) setTags(src []*autoscaling.TagDescription) {
-	g.Tags = make([]*TagDescription, len(src))
+	g.Tags = make([]*TagDescription, 0, len(src))
 
-	for i, v := range src {
-		g.Tags[i] = &TagDescription{Key: v.Key, Value: v.Value, PropagateAtLaunch: v.PropagateAtLaunch}
+	for _, v := range src {
+		if v == nil {
+			continue
+		}
+		g.Tags = append(g.Tags, &TagDescription{Key: v.Key, Value: v.Value, PropagateAtLaunch: v.PropagateAtLaunch})
 	}
 }
 
 func (g *Group) setEnabledMetrics(src []*autoscaling.EnabledMetric) {
-	g.EnabledMetrics = make([]*string, len(src))
-	for i, v := range src {
-		g.EnabledMetrics[i] = v.Metric
+	g.EnabledMetrics = make([]*string, 0, len(src))
+	for _, v := range src {
+		if v == nil {
+			continue
+		}
+		g.EnabledMetrics = append(g.EnabledMetrics, v.Metric)
 	}
 }
 
@@ -96,24 +102,32 @@ func (g *Group) set(src *autoscaling.Group) {
 
 // GetAutoScalingGroups craps a list of autoscaling group
 // and placing it into a slice of 'AutoScalingGroups'
-func (c *AWSClient) GetAutoScalingGroups() (*AutoScalingGroups, error) {
+func (c *AWSClient) GetAutoScalingGroupsWithContext(ctx aws.Context) (*AutoScalingGroups, error) {
 	var res AutoScalingGroups
 	options := &autoscaling.DescribeAutoScalingGroupsInput{
 		MaxRecords: aws.Int64(100),
 	}
 
 	for {
-		groups, err := c.asconn.DescribeAutoScalingGroups(options)
+		groups, err := c.asconn.DescribeAutoScalingGroupsWithContext(ctx, options)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, v := range groups.AutoScalingGroups {
+			if v == nil || !c.wantID(v.AutoScalingGroupName) {
+				continue
+			}
 			tmp := &Group{}
 			tmp.set(v)
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
 			res = append(res, tmp)
 		}
 
+		c.progress("aws_autoscaling_group", len(res))
+
 		if aws.StringValue(groups.NextToken) != "" {
 			options.NextToken = groups.NextToken
 		} else {
@@ -124,6 +138,11 @@ func (c *AWSClient) GetAutoScalingGroups() (*AutoScalingGroups, error) {
 	return &res, nil
 }
 
+// GetAutoScalingGroups calls GetAutoScalingGroupsWithContext with a background context
+func (c *AWSClient) GetAutoScalingGroups() (*AutoScalingGroups, error) {
+	return c.GetAutoScalingGroupsWithContext(aws.BackgroundContext())
+}
+
 // WriteHCL render terraform configs from AutoScalingGroups
 // and pretty print int into io.Writer
 func (src *AutoScalingGroups) WriteHCL(w io.Writer) error {
@@ -135,7 +154,8 @@ func (src *AutoScalingGroups) WriteHCL(w io.Writer) error {
 	tmpl := `
     {{- if .}}
     {{- range .}}
-    resource "aws_autoscaling_group" "{{ .Name }}" {
+    {{ resourceID "aws_autoscaling_group" .Name }}
+    resource "aws_autoscaling_group" "{{ sanitizeName .Name }}" {
       name = "{{ .Name }}"
       min_size = {{ .MinSize }}
       max_size = {{ .MaxSize }}
@@ -163,12 +183,13 @@ func (src *AutoScalingGroups) WriteHCL(w io.Writer) error {
       {{- if .ServiceLinkedRoleARN }}
       service_linked_role_arn = "{{ .ServiceLinkedRoleARN }}"
       {{- end }}
-      {{- if .Tags }}
+      {{- $tags := asgTags .Tags }}
+      {{- if $tags }}
       tags = [
-        {{ range .Tags }}
+        {{ range $tags }}
         {
-          key = "{{ .Key }}"
-          value = "{{ .Value }}"
+          key = {{ hclString .Key }}
+          value = {{ hclString .Value }}
           propagate_at_launch = {{ .PropagateAtLaunch }}
         },
         {{ end }}
@@ -201,10 +222,25 @@ func (src *AutoScalingGroups) WriteHCL(w io.Writer) error {
 	return renderHCL(w, tmpl, funcMap, src)
 }
 
+// WriteImports writes the terraform import commands of 'AutoScalingGroups'
+func (src *AutoScalingGroups) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_autoscaling_group.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, src)
+}
+
 //**************** Launch Configuration ****************
 type LaunchConfigurations []*autoscaling.LaunchConfiguration
 
-func (c *AWSClient) GetLaunchConfigurations() (*LaunchConfigurations, error) {
+func (c *AWSClient) GetLaunchConfigurationsWithContext(ctx aws.Context) (*LaunchConfigurations, error) {
+	// Launch configurations have no tags, TagFilter excludes all of them
+	if c.untaggable() {
+		return &LaunchConfigurations{}, nil
+	}
+
 	var res LaunchConfigurations
 
 	options := &autoscaling.DescribeLaunchConfigurationsInput{
@@ -212,12 +248,18 @@ func (c *AWSClient) GetLaunchConfigurations() (*LaunchConfigurations, error) {
 	}
 
 	for {
-		launchconfigs, err := c.asconn.DescribeLaunchConfigurations(options)
+		launchconfigs, err := c.asconn.DescribeLaunchConfigurationsWithContext(ctx, options)
 		if err != nil {
 			return nil, err
 		}
 
-		res = append(res, launchconfigs.LaunchConfigurations...)
+		for _, v := range launchconfigs.LaunchConfigurations {
+			if v != nil && c.wantID(v.LaunchConfigurationName) {
+				res = append(res, v)
+			}
+		}
+
+		c.progress("aws_launch_configuration", len(res))
 
 		if aws.StringValue(launchconfigs.NextToken) != "" {
 			options.NextToken = launchconfigs.NextToken
@@ -229,16 +271,23 @@ func (c *AWSClient) GetLaunchConfigurations() (*LaunchConfigurations, error) {
 	return &res, nil
 }
 
+// GetLaunchConfigurations calls GetLaunchConfigurationsWithContext with a background context
+func (c *AWSClient) GetLaunchConfigurations() (*LaunchConfigurations, error) {
+	return c.GetLaunchConfigurationsWithContext(aws.BackgroundContext())
+}
+
 func (src *LaunchConfigurations) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"joinstring":       joinStringSlice,
+		"StringValue":      aws.StringValue,
 		"StringValueSlice": aws.StringValueSlice,
 	}
 
 	tmpl := `
   {{- if . }}
     {{- range .}}
-    resource "aws_launch_configuration" "{{ .LaunchConfigurationName }}" {
+    {{ resourceID "aws_launch_configuration" .LaunchConfigurationName }}
+    resource "aws_launch_configuration" "{{ sanitizeName .LaunchConfigurationName }}" {
       name = "{{ .LaunchConfigurationName }}"
       image_id = "{{ .ImageId }}"
       instance_type = "{{ .InstanceType }}"
@@ -258,15 +307,15 @@ func (src *LaunchConfigurations) WriteHCL(w io.Writer) error {
       {{- $classicSecGroup := StringValueSlice .ClassicLinkVPCSecurityGroups}}
       vpc_classic_link_security_groups = [{{ $classicSecGroup | joinstring "," }}]
       {{- end}}
-      {{- $l := len .UserData }}
-      {{- $notempty := gt $l 0}}
-      {{- if and .UserData $notempty }}
+      {{- if ne (StringValue .UserData) "" }}
       user_data = "{{ .UserData }}"
       {{- end}}
       {{- if .InstanceMonitoring }}
       enable_monitoring = {{ .InstanceMonitoring.Enabled }}
       {{- end}}
+      {{- if .EbsOptimized }}
       ebs_optimized = {{ .EbsOptimized }}
+      {{- end}}
       {{- if .PlacementTenancy}}
       placement_tenancy = "{{ .PlacementTenancy }}"
       {{- end}}
@@ -283,6 +332,7 @@ func (src *LaunchConfigurations) WriteHCL(w io.Writer) error {
           }
           {{else if .NoDevice }}
           root_block_device {
+            {{- if .Ebs }}
             {{- if .Ebs.VolumeType}}
             volume_type = "{{ .Ebs.VolumeType }}"
             {{end}}
@@ -295,10 +345,12 @@ func (src *LaunchConfigurations) WriteHCL(w io.Writer) error {
             {{- if .Ebs.DeleteOnTermination}}
             delete_on_termination = {{ .Ebs.DeleteOnTermination }}
             {{end}}
+            {{- end }}
           }
           {{else }}
           ebs_block_device {
             device_name = "{{ .DeviceName }}"
+            {{- if .Ebs }}
             {{- if .Ebs.SnapshotId }}
             snapshot_id = "{{ .Ebs.SnapshotId }}"
             {{- end}}
@@ -317,6 +369,7 @@ func (src *LaunchConfigurations) WriteHCL(w io.Writer) error {
             {{- if .Ebs.Encrypted }}
             encrypted = {{ .Ebs.Encrypted }}
             {{- end}}
+            {{- end }}
           }
           {{end}}
         {{end}}
@@ -327,3 +380,13 @@ func (src *LaunchConfigurations) WriteHCL(w io.Writer) error {
   `
 	return renderHCL(w, tmpl, funcMap, src)
 }
+
+// WriteImports writes the terraform import commands of 'LaunchConfigurations'
+func (src *LaunchConfigurations) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_launch_configuration.{{ sanitizeName .LaunchConfigurationName }} {{ .LaunchConfigurationName }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, src)
+}
@@ -0,0 +1,372 @@
+package tfit
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+)
+
+// beanstalkTags returns the tags of an Elastic Beanstalk resource, leaving
+// out the "elasticbeanstalk:" ones managed by Elastic Beanstalk itself
+func (c *AWSClient) beanstalkTags(ctx aws.Context, arn *string) (*Tags, error) {
+	output, err := c.beanstalkconn.ListTagsForResourceWithContext(ctx, &elasticbeanstalk.ListTagsForResourceInput{ResourceArn: arn})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Tags{}
+	for _, v := range output.ResourceTags {
+		if v != nil && v.Key != nil && !strings.HasPrefix(*v.Key, "elasticbeanstalk:") {
+			(*res)[*v.Key] = v.Value
+		}
+	}
+
+	return res, nil
+}
+
+//**************** Elastic Beanstalk Application ****************
+type BeanstalkApp struct {
+	Name        *string
+	ARN         *string
+	Description *string
+
+	// Application version lifecycle, set when a rule is enabled
+	ServiceRole        *string
+	MaxCount           *int64
+	MaxAgeInDays       *int64
+	DeleteSourceFromS3 *bool
+
+	Tags *Tags
+}
+
+type BeanstalkApps []*BeanstalkApp
+
+func (a *BeanstalkApp) set(src *elasticbeanstalk.ApplicationDescription) {
+	a.Name = src.ApplicationName
+	a.ARN = src.ApplicationArn
+	a.Description = src.Description
+
+	if src.ResourceLifecycleConfig == nil || src.ResourceLifecycleConfig.VersionLifecycleConfig == nil {
+		return
+	}
+
+	lifecycle := src.ResourceLifecycleConfig.VersionLifecycleConfig
+	if rule := lifecycle.MaxCountRule; rule != nil && aws.BoolValue(rule.Enabled) {
+		a.ServiceRole = src.ResourceLifecycleConfig.ServiceRole
+		a.MaxCount = rule.MaxCount
+		a.DeleteSourceFromS3 = rule.DeleteSourceFromS3
+	} else if rule := lifecycle.MaxAgeRule; rule != nil && aws.BoolValue(rule.Enabled) {
+		a.ServiceRole = src.ResourceLifecycleConfig.ServiceRole
+		a.MaxAgeInDays = rule.MaxAgeInDays
+		a.DeleteSourceFromS3 = rule.DeleteSourceFromS3
+	}
+}
+
+func (c *AWSClient) GetBeanstalkAppsWithContext(ctx aws.Context) (*BeanstalkApps, error) {
+	var res BeanstalkApps
+
+	out, err := c.beanstalkconn.DescribeApplicationsWithContext(ctx, &elasticbeanstalk.DescribeApplicationsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range out.Applications {
+		if v == nil || !c.wantID(v.ApplicationName, v.ApplicationArn) {
+			continue
+		}
+
+		tmp := &BeanstalkApp{}
+		tmp.set(v)
+		if tmp.Tags, err = c.beanstalkTags(ctx, v.ApplicationArn); err != nil {
+			return nil, err
+		}
+		if !c.matchTags(tmp.Tags) {
+			continue
+		}
+
+		res = append(res, tmp)
+	}
+
+	return &res, nil
+}
+
+// GetBeanstalkApps calls GetBeanstalkAppsWithContext with a background context
+func (c *AWSClient) GetBeanstalkApps() (*BeanstalkApps, error) {
+	return c.GetBeanstalkAppsWithContext(aws.BackgroundContext())
+}
+
+func (a *BeanstalkApps) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"BoolValue":                 aws.BoolValue,
+		"makeTerraformResourceName": makeTerraformResourceName,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_elastic_beanstalk_application" .Name }}
+    resource "aws_elastic_beanstalk_application" "{{ .Name | makeTerraformResourceName | sanitizeName }}" {
+      name = {{ hclString .Name }}
+      {{- if .Description }}
+      description = {{ hclString .Description }}
+      {{- end }}
+      {{- if .ServiceRole }}
+
+      appversion_lifecycle {
+        service_role = "{{ .ServiceRole }}"
+        {{- if .MaxCount }}
+        max_count = {{ .MaxCount }}
+        {{- end }}
+        {{- if .MaxAgeInDays }}
+        max_age_in_days = {{ .MaxAgeInDays }}
+        {{- end }}
+        delete_source_from_s3 = {{ BoolValue .DeleteSourceFromS3 }}
+      }
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, a)
+}
+
+// WriteImports writes the terraform import commands of 'BeanstalkApps'
+func (a *BeanstalkApps) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformResourceName": makeTerraformResourceName,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_elastic_beanstalk_application.{{ .Name | makeTerraformResourceName | sanitizeName }} '{{ .Name }}'
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, a)
+}
+
+//**************** Elastic Beanstalk Environment ****************
+type BeanstalkEnv struct {
+	ID                *string
+	Name              *string
+	ARN               *string
+	ApplicationName   *string
+	Description       *string
+	Tier              *string
+	SolutionStackName *string
+	PlatformARN       *string
+	VersionLabel      *string
+	Settings          []*elasticbeanstalk.ConfigurationOptionSetting
+	Tags              *Tags
+
+	// Some settings, see secretSetting, were written "REDACTED"
+	Redacted bool
+
+	// Set by ResolveReferences when the application is exported as well
+	ApplicationExported bool
+}
+
+type BeanstalkEnvs []*BeanstalkEnv
+
+func (e *BeanstalkEnv) set(src *elasticbeanstalk.EnvironmentDescription) {
+	e.ID = src.EnvironmentId
+	e.Name = src.EnvironmentName
+	e.ARN = src.EnvironmentArn
+	e.ApplicationName = src.ApplicationName
+	e.Description = src.Description
+	e.SolutionStackName = src.SolutionStackName
+	e.PlatformARN = src.PlatformArn
+	e.VersionLabel = src.VersionLabel
+
+	if src.Tier != nil {
+		e.Tier = src.Tier.Name
+	}
+}
+
+// secretSetting reports whether the value of the option setting may be a
+// secret: the environment variables of the application and the password
+// of the database
+func secretSetting(v *elasticbeanstalk.ConfigurationOptionSetting) bool {
+	switch aws.StringValue(v.Namespace) {
+	case "aws:elasticbeanstalk:application:environment":
+		return true
+	case "aws:rds:dbinstance":
+		return aws.StringValue(v.OptionName) == "DBPassword"
+	}
+
+	return false
+}
+
+// getSettings gets the option settings of the environment. The values of
+// the CloudFormation template parameters are internal to Elastic Beanstalk,
+// the secret ones are redacted
+func (e *BeanstalkEnv) getSettings(ctx aws.Context, c *AWSClient) error {
+	out, err := c.beanstalkconn.DescribeConfigurationSettingsWithContext(ctx, &elasticbeanstalk.DescribeConfigurationSettingsInput{
+		ApplicationName: e.ApplicationName,
+		EnvironmentName: e.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, config := range out.ConfigurationSettings {
+		if config == nil {
+			continue
+		}
+		for _, v := range config.OptionSettings {
+			if v == nil || v.Value == nil || strings.HasPrefix(aws.StringValue(v.Namespace), "aws:cloudformation:") {
+				continue
+			}
+			if secretSetting(v) {
+				redacted := *v
+				redacted.Value = aws.String("REDACTED")
+				v = &redacted
+				e.Redacted = true
+			}
+			e.Settings = append(e.Settings, v)
+		}
+	}
+
+	sort.Slice(e.Settings, func(i, j int) bool {
+		a, b := e.Settings[i], e.Settings[j]
+		if aws.StringValue(a.Namespace) != aws.StringValue(b.Namespace) {
+			return aws.StringValue(a.Namespace) < aws.StringValue(b.Namespace)
+		}
+		if aws.StringValue(a.OptionName) != aws.StringValue(b.OptionName) {
+			return aws.StringValue(a.OptionName) < aws.StringValue(b.OptionName)
+		}
+		return aws.StringValue(a.ResourceName) < aws.StringValue(b.ResourceName)
+	})
+
+	return nil
+}
+
+// GetBeanstalkEnvsWithContext returns the environments which are not
+// terminated, with their option settings
+func (c *AWSClient) GetBeanstalkEnvsWithContext(ctx aws.Context) (*BeanstalkEnvs, error) {
+	var res BeanstalkEnvs
+
+	opt := &elasticbeanstalk.DescribeEnvironmentsInput{IncludeDeleted: aws.Bool(false)}
+	for {
+		out, err := c.beanstalkconn.DescribeEnvironmentsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Environments {
+			if v == nil || aws.StringValue(v.Status) == elasticbeanstalk.EnvironmentStatusTerminated || !c.wantID(v.EnvironmentId, v.EnvironmentName, v.EnvironmentArn) {
+				continue
+			}
+
+			tmp := &BeanstalkEnv{}
+			tmp.set(v)
+			if tmp.Tags, err = c.beanstalkTags(ctx, v.EnvironmentArn); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+			if err := tmp.getSettings(ctx, c); err != nil {
+				return nil, err
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_elastic_beanstalk_environment", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetBeanstalkEnvs calls GetBeanstalkEnvsWithContext with a background context
+func (c *AWSClient) GetBeanstalkEnvs() (*BeanstalkEnvs, error) {
+	return c.GetBeanstalkEnvsWithContext(aws.BackgroundContext())
+}
+
+func (e *BeanstalkEnvs) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformResourceName": makeTerraformResourceName,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_elastic_beanstalk_environment" .ID }}
+    resource "aws_elastic_beanstalk_environment" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      {{- if .ApplicationExported }}
+      application = "${aws_elastic_beanstalk_application.{{ .ApplicationName | makeTerraformResourceName | sanitizeName }}.name}"
+      {{- else }}
+      application = {{ hclString .ApplicationName }}
+      {{- end }}
+      {{- if .Description }}
+      description = {{ hclString .Description }}
+      {{- end }}
+      {{- if .Tier }}
+      tier = "{{ .Tier }}"
+      {{- end }}
+      {{- if .SolutionStackName }}
+      solution_stack_name = "{{ .SolutionStackName }}"
+      {{- else if .PlatformARN }}
+      platform_arn = "{{ .PlatformARN }}"
+      {{- end }}
+      {{- if .VersionLabel }}
+      version_label = {{ hclString .VersionLabel }}
+      {{- end }}
+      {{- range .Settings }}
+
+      setting {
+        namespace = "{{ .Namespace }}"
+        name = "{{ .OptionName }}"
+        value = {{ hclString .Value }}
+        {{- if .ResourceName }}
+        resource = "{{ .ResourceName }}"
+        {{- end }}
+      }
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+      {{- if .Redacted }}
+
+      # WARNING: environment variables & database password redacted, set them before creating the environment
+      lifecycle {
+        ignore_changes = ["setting"]
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, e)
+}
+
+// WriteImports writes the terraform import commands of 'BeanstalkEnvs'
+func (e *BeanstalkEnvs) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_elastic_beanstalk_environment.{{ sanitizeName .Name }} {{ .ID }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, e)
+}
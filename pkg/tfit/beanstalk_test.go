@@ -0,0 +1,64 @@
+package tfit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+)
+
+func TestBeanstalkEnvSecretSettings(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{}
+
+	setting := func(namespace, name, value string) *elasticbeanstalk.ConfigurationOptionSetting {
+		return &elasticbeanstalk.ConfigurationOptionSetting{
+			Namespace:  aws.String(namespace),
+			OptionName: aws.String(name),
+			Value:      aws.String(value),
+		}
+	}
+	c := &AWSClient{}
+	c.beanstalkconn = elasticbeanstalk.New(stubSession())
+	stubSend(&c.beanstalkconn.Handlers, func(r *request.Request) {
+		out := r.Data.(*elasticbeanstalk.DescribeConfigurationSettingsOutput)
+		out.ConfigurationSettings = []*elasticbeanstalk.ConfigurationSettingsDescription{{
+			OptionSettings: []*elasticbeanstalk.ConfigurationOptionSetting{
+				setting("aws:elasticbeanstalk:application:environment", "API_KEY", "s3cr3t"),
+				setting("aws:rds:dbinstance", "DBPassword", "hunter2"),
+				setting("aws:rds:dbinstance", "DBUser", "admin"),
+				setting("aws:autoscaling:asg", "MaxSize", "4"),
+			},
+		}}
+	})
+
+	env := &BeanstalkEnv{Name: aws.String("shop-web"), ApplicationName: aws.String("shop")}
+	if err := env.getSettings(aws.BackgroundContext(), c); err != nil {
+		t.Fatalf("getSettings: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&BeanstalkEnvs{env}).WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	out := strings.Join(strings.Fields(buf.String()), " ")
+	for _, want := range []string{
+		`name = "API_KEY" value = "REDACTED"`,
+		`name = "DBPassword" value = "REDACTED"`,
+		`name = "DBUser" value = "admin"`,
+		`name = "MaxSize" value = "4"`,
+		`lifecycle { ignore_changes = ["setting"] }`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want %s in:\n%s", want, buf.String())
+		}
+	}
+	for _, secret := range []string{"s3cr3t", "hunter2"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("secret %s written in:\n%s", secret, buf.String())
+		}
+	}
+}
@@ -0,0 +1,520 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+)
+
+//**************** CloudFront Distribution ****************
+type Distribution struct {
+	ID                *string
+	ARN               *string
+	Comment           *string
+	DefaultRootObject *string
+	Enabled           *bool
+	IPv6Enabled       *bool
+	HTTPVersion       *string
+	PriceClass        *string
+	WebACLID          *string
+	Aliases           []*string
+	Tags              *Tags
+
+	Origins              []*DistributionOrigin
+	DefaultCacheBehavior *DistributionCacheBehavior
+	CacheBehaviors       []*DistributionCacheBehavior
+	CustomErrorResponses []*cloudfront.CustomErrorResponse
+
+	// nil when logging is disabled
+	Logging *cloudfront.LoggingConfig
+
+	ViewerCertificate *cloudfront.ViewerCertificate
+
+	GeoRestrictionType      *string
+	GeoRestrictionLocations []*string
+}
+
+type Distributions []*Distribution
+
+type DistributionOrigin struct {
+	ID            *string
+	DomainName    *string
+	OriginPath    *string
+	CustomHeaders []*cloudfront.OriginCustomHeader
+
+	// S3 origins only
+	OriginAccessIdentity *string
+
+	// Custom origins only
+	Custom                 bool
+	HTTPPort               *int64
+	HTTPSPort              *int64
+	OriginProtocolPolicy   *string
+	OriginSSLProtocols     []*string
+	OriginKeepaliveTimeout *int64
+	OriginReadTimeout      *int64
+}
+
+// DistributionCacheBehavior is either the default cache behavior, without
+// PathPattern, or an ordered one
+type DistributionCacheBehavior struct {
+	PathPattern            *string
+	TargetOriginID         *string
+	ViewerProtocolPolicy   *string
+	AllowedMethods         []*string
+	CachedMethods          []*string
+	Compress               *bool
+	SmoothStreaming        *bool
+	FieldLevelEncryptionID *string
+	MinTTL                 *int64
+	DefaultTTL             *int64
+	MaxTTL                 *int64
+	TrustedSigners         []*string
+
+	QueryString          *bool
+	QueryStringCacheKeys []*string
+	Headers              []*string
+	CookiesForward       *string
+	CookiesWhitelist     []*string
+
+	LambdaFunctionAssociations []*cloudfront.LambdaFunctionAssociation
+}
+
+func (d *Distribution) set(src *cloudfront.DistributionConfig) {
+	d.Comment = src.Comment
+	d.DefaultRootObject = src.DefaultRootObject
+	d.Enabled = src.Enabled
+	d.IPv6Enabled = src.IsIPV6Enabled
+	d.HTTPVersion = src.HttpVersion
+	d.PriceClass = src.PriceClass
+	d.ViewerCertificate = src.ViewerCertificate
+
+	if len(aws.StringValue(src.WebACLId)) > 0 {
+		d.WebACLID = src.WebACLId
+	}
+	if src.Aliases != nil {
+		d.Aliases = src.Aliases.Items
+		sortStrings(d.Aliases)
+	}
+	if src.Logging != nil && aws.BoolValue(src.Logging.Enabled) {
+		d.Logging = src.Logging
+	}
+	if src.CustomErrorResponses != nil {
+		d.CustomErrorResponses = src.CustomErrorResponses.Items
+	}
+	if src.Restrictions != nil && src.Restrictions.GeoRestriction != nil {
+		d.GeoRestrictionType = src.Restrictions.GeoRestriction.RestrictionType
+		d.GeoRestrictionLocations = src.Restrictions.GeoRestriction.Items
+		sortStrings(d.GeoRestrictionLocations)
+	}
+
+	if src.Origins != nil {
+		for _, v := range src.Origins.Items {
+			if v != nil {
+				tmp := &DistributionOrigin{}
+				tmp.set(v)
+				d.Origins = append(d.Origins, tmp)
+			}
+		}
+	}
+
+	// The default cache behavior is a cache behavior without path pattern
+	if v := src.DefaultCacheBehavior; v != nil {
+		d.DefaultCacheBehavior = &DistributionCacheBehavior{}
+		d.DefaultCacheBehavior.set(&cloudfront.CacheBehavior{
+			AllowedMethods:             v.AllowedMethods,
+			Compress:                   v.Compress,
+			DefaultTTL:                 v.DefaultTTL,
+			FieldLevelEncryptionId:     v.FieldLevelEncryptionId,
+			ForwardedValues:            v.ForwardedValues,
+			LambdaFunctionAssociations: v.LambdaFunctionAssociations,
+			MaxTTL:                     v.MaxTTL,
+			MinTTL:                     v.MinTTL,
+			SmoothStreaming:            v.SmoothStreaming,
+			TargetOriginId:             v.TargetOriginId,
+			TrustedSigners:             v.TrustedSigners,
+			ViewerProtocolPolicy:       v.ViewerProtocolPolicy,
+		})
+	}
+
+	// Ordered cache behaviors are kept in the order they are evaluated
+	if src.CacheBehaviors != nil {
+		for _, v := range src.CacheBehaviors.Items {
+			if v != nil {
+				tmp := &DistributionCacheBehavior{}
+				tmp.set(v)
+				d.CacheBehaviors = append(d.CacheBehaviors, tmp)
+			}
+		}
+	}
+}
+
+func (o *DistributionOrigin) set(src *cloudfront.Origin) {
+	o.ID = src.Id
+	o.DomainName = src.DomainName
+	if len(aws.StringValue(src.OriginPath)) > 0 {
+		o.OriginPath = src.OriginPath
+	}
+	if src.CustomHeaders != nil {
+		o.CustomHeaders = src.CustomHeaders.Items
+	}
+
+	if src.S3OriginConfig != nil && len(aws.StringValue(src.S3OriginConfig.OriginAccessIdentity)) > 0 {
+		o.OriginAccessIdentity = src.S3OriginConfig.OriginAccessIdentity
+	}
+
+	if c := src.CustomOriginConfig; c != nil {
+		o.Custom = true
+		o.HTTPPort = c.HTTPPort
+		o.HTTPSPort = c.HTTPSPort
+		o.OriginProtocolPolicy = c.OriginProtocolPolicy
+		o.OriginKeepaliveTimeout = c.OriginKeepaliveTimeout
+		o.OriginReadTimeout = c.OriginReadTimeout
+		if c.OriginSslProtocols != nil {
+			o.OriginSSLProtocols = c.OriginSslProtocols.Items
+			sortStrings(o.OriginSSLProtocols)
+		}
+	}
+}
+
+func (b *DistributionCacheBehavior) set(src *cloudfront.CacheBehavior) {
+	b.PathPattern = src.PathPattern
+	b.TargetOriginID = src.TargetOriginId
+	b.ViewerProtocolPolicy = src.ViewerProtocolPolicy
+	b.Compress = src.Compress
+	b.SmoothStreaming = src.SmoothStreaming
+	b.MinTTL = src.MinTTL
+	b.DefaultTTL = src.DefaultTTL
+	b.MaxTTL = src.MaxTTL
+
+	if len(aws.StringValue(src.FieldLevelEncryptionId)) > 0 {
+		b.FieldLevelEncryptionID = src.FieldLevelEncryptionId
+	}
+	if m := src.AllowedMethods; m != nil {
+		b.AllowedMethods = m.Items
+		sortStrings(b.AllowedMethods)
+		if m.CachedMethods != nil {
+			b.CachedMethods = m.CachedMethods.Items
+			sortStrings(b.CachedMethods)
+		}
+	}
+	if s := src.TrustedSigners; s != nil && aws.BoolValue(s.Enabled) {
+		b.TrustedSigners = s.Items
+	}
+	if l := src.LambdaFunctionAssociations; l != nil {
+		b.LambdaFunctionAssociations = l.Items
+	}
+
+	if f := src.ForwardedValues; f != nil {
+		b.QueryString = f.QueryString
+		if f.QueryStringCacheKeys != nil {
+			b.QueryStringCacheKeys = f.QueryStringCacheKeys.Items
+		}
+		if f.Headers != nil {
+			b.Headers = f.Headers.Items
+		}
+		if f.Cookies != nil {
+			b.CookiesForward = f.Cookies.Forward
+			if f.Cookies.WhitelistedNames != nil {
+				b.CookiesWhitelist = f.Cookies.WhitelistedNames.Items
+			}
+		}
+	}
+}
+
+func (d *Distribution) getTags(ctx aws.Context, c *AWSClient) error {
+	output, err := c.cloudfrontconn.ListTagsForResourceWithContext(ctx, &cloudfront.ListTagsForResourceInput{Resource: d.ARN})
+	if err != nil {
+		return err
+	}
+
+	d.Tags = &Tags{}
+	if output.Tags != nil {
+		for _, v := range output.Tags.Items {
+			if v != nil && v.Key != nil {
+				(*d.Tags)[*v.Key] = v.Value
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetDistributionsWithContext returns the distributions, their settings
+// read by cloudfront:GetDistributionConfig
+func (c *AWSClient) GetDistributionsWithContext(ctx aws.Context) (*Distributions, error) {
+	var res Distributions
+
+	opt := &cloudfront.ListDistributionsInput{}
+	for {
+		out, err := c.cloudfrontconn.ListDistributionsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		if out.DistributionList == nil {
+			break
+		}
+
+		for _, v := range out.DistributionList.Items {
+			if v == nil || !c.wantID(v.Id, v.ARN, v.DomainName) {
+				continue
+			}
+
+			tmp := &Distribution{ID: v.Id, ARN: v.ARN}
+			if err := tmp.getTags(ctx, c); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+
+			cfg, err := c.cloudfrontconn.GetDistributionConfigWithContext(ctx, &cloudfront.GetDistributionConfigInput{Id: v.Id})
+			if err != nil {
+				return nil, err
+			}
+			if cfg.DistributionConfig == nil {
+				continue
+			}
+			tmp.set(cfg.DistributionConfig)
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_cloudfront_distribution", len(res))
+
+		if !aws.BoolValue(out.DistributionList.IsTruncated) {
+			break
+		}
+		opt.Marker = out.DistributionList.NextMarker
+	}
+
+	return &res, nil
+}
+
+// GetDistributions calls GetDistributionsWithContext with a background context
+func (c *AWSClient) GetDistributions() (*Distributions, error) {
+	return c.GetDistributionsWithContext(aws.BackgroundContext())
+}
+
+func (d *Distributions) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+		"StringValue":      aws.StringValue,
+		"BoolValue":        aws.BoolValue,
+	}
+
+	// default_cache_behavior & ordered_cache_behavior share their arguments,
+	// ordered ones add path_pattern
+	tmpl := `
+	{{ define "cache_behavior" }}
+        {{- if .PathPattern }}
+        path_pattern = "{{ .PathPattern }}"
+        {{- end }}
+        target_origin_id = "{{ .TargetOriginID }}"
+        viewer_protocol_policy = "{{ .ViewerProtocolPolicy }}"
+        allowed_methods = [{{ StringValueSlice .AllowedMethods | joinstring "," }}]
+        cached_methods = [{{ StringValueSlice .CachedMethods | joinstring "," }}]
+        {{- if BoolValue .Compress }}
+        compress = true
+        {{- end }}
+        {{- if BoolValue .SmoothStreaming }}
+        smooth_streaming = true
+        {{- end }}
+        {{- if .FieldLevelEncryptionID }}
+        field_level_encryption_id = "{{ .FieldLevelEncryptionID }}"
+        {{- end }}
+        {{- if .MinTTL }}
+        min_ttl = {{ .MinTTL }}
+        {{- end }}
+        {{- if .DefaultTTL }}
+        default_ttl = {{ .DefaultTTL }}
+        {{- end }}
+        {{- if .MaxTTL }}
+        max_ttl = {{ .MaxTTL }}
+        {{- end }}
+        {{- if .TrustedSigners }}
+        trusted_signers = [{{ StringValueSlice .TrustedSigners | joinstring "," }}]
+        {{- end }}
+        forwarded_values {
+          query_string = {{ BoolValue .QueryString }}
+          {{- if .QueryStringCacheKeys }}
+          query_string_cache_keys = [{{ StringValueSlice .QueryStringCacheKeys | joinstring "," }}]
+          {{- end }}
+          {{- if .Headers }}
+          headers = [{{ StringValueSlice .Headers | joinstring "," }}]
+          {{- end }}
+          cookies {
+            forward = "{{ StringValue .CookiesForward }}"
+            {{- if .CookiesWhitelist }}
+            whitelisted_names = [{{ StringValueSlice .CookiesWhitelist | joinstring "," }}]
+            {{- end }}
+          }
+        }
+        {{- range .LambdaFunctionAssociations }}
+        lambda_function_association {
+          event_type = "{{ .EventType }}"
+          lambda_arn = "{{ .LambdaFunctionARN }}"
+          {{- if BoolValue .IncludeBody }}
+          include_body = true
+          {{- end }}
+        }
+        {{- end }}
+	{{- end }}
+
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_cloudfront_distribution" .ID }}
+    resource "aws_cloudfront_distribution" "{{ sanitizeName .ID }}" {
+      enabled = {{ BoolValue .Enabled }}
+      {{- if BoolValue .IPv6Enabled }}
+      is_ipv6_enabled = true
+      {{- end }}
+      {{- if .Comment }}
+      comment = {{ hclString .Comment }}
+      {{- end }}
+      {{- if .DefaultRootObject }}
+      default_root_object = "{{ .DefaultRootObject }}"
+      {{- end }}
+      {{- if .Aliases }}
+      aliases = [{{ StringValueSlice .Aliases | joinstring "," }}]
+      {{- end }}
+      {{- if .HTTPVersion }}
+      http_version = "{{ .HTTPVersion }}"
+      {{- end }}
+      {{- if .PriceClass }}
+      price_class = "{{ .PriceClass }}"
+      {{- end }}
+      {{- if .WebACLID }}
+      web_acl_id = "{{ .WebACLID }}"
+      {{- end }}
+      {{- range .Origins }}
+
+      origin {
+        origin_id = "{{ .ID }}"
+        domain_name = "{{ .DomainName }}"
+        {{- if .OriginPath }}
+        origin_path = "{{ .OriginPath }}"
+        {{- end }}
+        {{- range .CustomHeaders }}
+        custom_header {
+          name = "{{ .HeaderName }}"
+          value = {{ hclString .HeaderValue }}
+        }
+        {{- end }}
+        {{- if .OriginAccessIdentity }}
+        s3_origin_config {
+          origin_access_identity = "{{ .OriginAccessIdentity }}"
+        }
+        {{- end }}
+        {{- if .Custom }}
+        custom_origin_config {
+          http_port = {{ .HTTPPort }}
+          https_port = {{ .HTTPSPort }}
+          origin_protocol_policy = "{{ .OriginProtocolPolicy }}"
+          origin_ssl_protocols = [{{ StringValueSlice .OriginSSLProtocols | joinstring "," }}]
+          {{- if .OriginKeepaliveTimeout }}
+          origin_keepalive_timeout = {{ .OriginKeepaliveTimeout }}
+          {{- end }}
+          {{- if .OriginReadTimeout }}
+          origin_read_timeout = {{ .OriginReadTimeout }}
+          {{- end }}
+        }
+        {{- end }}
+      }
+      {{- end }}
+      {{- with .DefaultCacheBehavior }}
+
+      default_cache_behavior {
+        {{- template "cache_behavior" . }}
+      }
+      {{- end }}
+      {{- range .CacheBehaviors }}
+
+      ordered_cache_behavior {
+        {{- template "cache_behavior" . }}
+      }
+      {{- end }}
+      {{- range .CustomErrorResponses }}
+
+      custom_error_response {
+        error_code = {{ .ErrorCode }}
+        {{- if .ResponseCode }}
+        response_code = {{ .ResponseCode }}
+        {{- end }}
+        {{- if .ResponsePagePath }}
+        response_page_path = "{{ .ResponsePagePath }}"
+        {{- end }}
+        {{- if .ErrorCachingMinTTL }}
+        error_caching_min_ttl = {{ .ErrorCachingMinTTL }}
+        {{- end }}
+      }
+      {{- end }}
+      {{- with .Logging }}
+
+      logging_config {
+        bucket = "{{ .Bucket }}"
+        {{- if .Prefix }}
+        prefix = "{{ .Prefix }}"
+        {{- end }}
+        {{- if BoolValue .IncludeCookies }}
+        include_cookies = true
+        {{- end }}
+      }
+      {{- end }}
+
+      restrictions {
+        geo_restriction {
+          restriction_type = "{{ StringValue .GeoRestrictionType }}"
+          {{- if .GeoRestrictionLocations }}
+          locations = [{{ StringValueSlice .GeoRestrictionLocations | joinstring "," }}]
+          {{- end }}
+        }
+      }
+      {{- with .ViewerCertificate }}
+
+      viewer_certificate {
+        {{- if BoolValue .CloudFrontDefaultCertificate }}
+        cloudfront_default_certificate = true
+        {{- else }}
+        {{- if .ACMCertificateArn }}
+        acm_certificate_arn = "{{ .ACMCertificateArn }}"
+        {{- else if .IAMCertificateId }}
+        iam_certificate_id = "{{ .IAMCertificateId }}"
+        {{- end }}
+        {{- if .SSLSupportMethod }}
+        ssl_support_method = "{{ .SSLSupportMethod }}"
+        {{- end }}
+        {{- end }}
+        {{- if .MinimumProtocolVersion }}
+        minimum_protocol_version = "{{ .MinimumProtocolVersion }}"
+        {{- end }}
+      }
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, d)
+}
+
+// WriteImports writes the terraform import commands of 'Distributions'
+func (d *Distributions) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_cloudfront_distribution.{{ sanitizeName .ID }} {{ .ID }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, d)
+}
@@ -0,0 +1,195 @@
+package tfit
+
+import (
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+)
+
+//**************** CloudTrail Trail ****************
+type Trail struct {
+	Name                       *string
+	ARN                        *string
+	S3BucketName               *string
+	S3KeyPrefix                *string
+	IncludeGlobalServiceEvents *bool
+	IsMultiRegionTrail         *bool
+	IsOrganizationTrail        *bool
+	EnableLogging              *bool
+	LogFileValidationEnabled   *bool
+	CloudWatchLogsLogGroupARN  *string
+	CloudWatchLogsRoleARN      *string
+	SNSTopicName               *string
+	KMSKeyID                   *string
+	Tags                       *Tags
+
+	// Set by ResolveReferences when the bucket, or the key, is exported as well
+	BucketExported bool
+	KMSKeyExported *string
+}
+
+type Trails []*Trail
+
+func (t *Trail) set(src *cloudtrail.Trail) {
+	t.Name = src.Name
+	t.ARN = src.TrailARN
+	t.S3BucketName = src.S3BucketName
+	t.S3KeyPrefix = src.S3KeyPrefix
+	t.IncludeGlobalServiceEvents = src.IncludeGlobalServiceEvents
+	t.IsMultiRegionTrail = src.IsMultiRegionTrail
+	t.IsOrganizationTrail = src.IsOrganizationTrail
+	t.LogFileValidationEnabled = src.LogFileValidationEnabled
+	t.CloudWatchLogsLogGroupARN = src.CloudWatchLogsLogGroupArn
+	t.CloudWatchLogsRoleARN = src.CloudWatchLogsRoleArn
+	t.SNSTopicName = src.SnsTopicName
+	t.KMSKeyID = src.KmsKeyId
+}
+
+func (t *Trail) getStatus(ctx aws.Context, c *AWSClient) error {
+	output, err := c.cloudtrailconn.GetTrailStatusWithContext(ctx, &cloudtrail.GetTrailStatusInput{Name: t.ARN})
+	if err != nil {
+		return err
+	}
+
+	t.EnableLogging = output.IsLogging
+
+	return nil
+}
+
+func (t *Trail) getTags(ctx aws.Context, c *AWSClient) error {
+	t.Tags = &Tags{}
+
+	opt := &cloudtrail.ListTagsInput{ResourceIdList: []*string{t.ARN}}
+	for {
+		output, err := c.cloudtrailconn.ListTagsWithContext(ctx, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, res := range output.ResourceTagList {
+			if res == nil {
+				continue
+			}
+			for _, v := range res.TagsList {
+				if v != nil && v.Key != nil {
+					(*t.Tags)[*v.Key] = v.Value
+				}
+			}
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		opt.NextToken = output.NextToken
+	}
+
+	return nil
+}
+
+// GetTrailsWithContext returns the trails of the region. Multi-region
+// trails are only returned by their home region, they are shadow trails
+// in the other ones
+func (c *AWSClient) GetTrailsWithContext(ctx aws.Context) (*Trails, error) {
+	var res Trails
+
+	out, err := c.cloudtrailconn.DescribeTrailsWithContext(ctx, &cloudtrail.DescribeTrailsInput{IncludeShadowTrails: aws.Bool(false)})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range out.TrailList {
+		if v == nil || (v.HomeRegion != nil && *v.HomeRegion != c.region) || !c.wantID(v.Name, v.TrailARN) {
+			continue
+		}
+
+		tmp := &Trail{}
+		tmp.set(v)
+		if err := tmp.getTags(ctx, c); err != nil {
+			return nil, err
+		}
+		if !c.matchTags(tmp.Tags) {
+			continue
+		}
+		if err := tmp.getStatus(ctx, c); err != nil {
+			return nil, err
+		}
+
+		res = append(res, tmp)
+	}
+
+	return &res, nil
+}
+
+// GetTrails calls GetTrailsWithContext with a background context
+func (c *AWSClient) GetTrails() (*Trails, error) {
+	return c.GetTrailsWithContext(aws.BackgroundContext())
+}
+
+func (t *Trails) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"BoolValue": aws.BoolValue,
+		"replace":   strings.Replace,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_cloudtrail" .Name }}
+    resource "aws_cloudtrail" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      {{- if .BucketExported }}
+      s3_bucket_name = "${aws_s3_bucket.{{ replace .S3BucketName "." "_" -1 | globalName }}.id}"
+      {{- else }}
+      s3_bucket_name = "{{ .S3BucketName }}"
+      {{- end }}
+      {{- if .S3KeyPrefix }}
+      s3_key_prefix = "{{ .S3KeyPrefix }}"
+      {{- end }}
+      include_global_service_events = {{ BoolValue .IncludeGlobalServiceEvents }}
+      is_multi_region_trail = {{ BoolValue .IsMultiRegionTrail }}
+      {{- if BoolValue .IsOrganizationTrail }}
+      is_organization_trail = true
+      {{- end }}
+      enable_logging = {{ BoolValue .EnableLogging }}
+      enable_log_file_validation = {{ BoolValue .LogFileValidationEnabled }}
+      {{- if .CloudWatchLogsLogGroupARN }}
+      cloud_watch_logs_group_arn = "{{ .CloudWatchLogsLogGroupARN }}"
+      cloud_watch_logs_role_arn = "{{ .CloudWatchLogsRoleARN }}"
+      {{- end }}
+      {{- if .SNSTopicName }}
+      sns_topic_name = "{{ .SNSTopicName }}"
+      {{- end }}
+      {{- with .KMSKeyExported }}
+      kms_key_id = "${aws_kms_key.{{ sanitizeName . }}.arn}"
+      {{- else }}
+      {{- if .KMSKeyID }}
+      kms_key_id = "{{ .KMSKeyID }}"
+      {{- end }}
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, t)
+}
+
+// WriteImports writes the terraform import commands of 'Trails'
+func (t *Trails) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_cloudtrail.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, t)
+}
@@ -0,0 +1,195 @@
+package tfit
+
+import (
+	"io"
+	"strconv"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+//**************** CloudWatch Metric Alarm ****************
+type MetricAlarm struct {
+	AlarmName          *string
+	AlarmDescription   *string
+	ComparisonOperator *string
+	EvaluationPeriods  *int64
+	DatapointsToAlarm  *int64
+	MetricName         *string
+	Namespace          *string
+	Period             *int64
+	Statistic          *string
+	ExtendedStatistic  *string
+	Threshold          *float64
+	TreatMissingData   *string
+	Unit               *string
+	Dimensions         map[string]*string
+
+	ActionsEnabled          *bool
+	AlarmActions            []*string
+	OKActions               []*string
+	InsufficientDataActions []*string
+}
+
+type MetricAlarms []*MetricAlarm
+
+func (a *MetricAlarm) set(src *cloudwatch.MetricAlarm) {
+	a.AlarmName = src.AlarmName
+	a.AlarmDescription = src.AlarmDescription
+	a.ComparisonOperator = src.ComparisonOperator
+	a.EvaluationPeriods = src.EvaluationPeriods
+	a.DatapointsToAlarm = src.DatapointsToAlarm
+	a.MetricName = src.MetricName
+	a.Namespace = src.Namespace
+	a.Period = src.Period
+	a.Statistic = src.Statistic
+	a.ExtendedStatistic = src.ExtendedStatistic
+	a.Threshold = src.Threshold
+	a.TreatMissingData = src.TreatMissingData
+	a.Unit = src.Unit
+
+	a.Dimensions = make(map[string]*string)
+	for _, v := range src.Dimensions {
+		if v != nil && v.Name != nil {
+			a.Dimensions[*v.Name] = v.Value
+		}
+	}
+
+	a.ActionsEnabled = src.ActionsEnabled
+	a.AlarmActions = src.AlarmActions
+	a.OKActions = src.OKActions
+	a.InsufficientDataActions = src.InsufficientDataActions
+	sortStrings(a.AlarmActions)
+	sortStrings(a.OKActions)
+	sortStrings(a.InsufficientDataActions)
+}
+
+// GetMetricAlarmsWithContext returns the alarms watching a single metric.
+// Alarms on metric math expressions are skipped
+func (c *AWSClient) GetMetricAlarmsWithContext(ctx aws.Context) (*MetricAlarms, error) {
+	// The CloudWatch API of the vendored SDK cannot list the tags of
+	// alarms, TagFilter excludes all of them
+	if c.untaggable() {
+		return &MetricAlarms{}, nil
+	}
+
+	var res MetricAlarms
+
+	opt := &cloudwatch.DescribeAlarmsInput{}
+	for {
+		out, err := c.cwconn.DescribeAlarmsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.MetricAlarms {
+			if v == nil || !c.wantID(v.AlarmName, v.AlarmArn) {
+				continue
+			}
+
+			if len(v.Metrics) > 0 {
+				c.logf("Alarm %s: metric math alarms are not supported, skipped", safeString(v.AlarmName, ""))
+				continue
+			}
+
+			tmp := &MetricAlarm{}
+			tmp.set(v)
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_cloudwatch_metric_alarm", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetMetricAlarms calls GetMetricAlarmsWithContext with a background context
+func (c *AWSClient) GetMetricAlarms() (*MetricAlarms, error) {
+	return c.GetMetricAlarmsWithContext(aws.BackgroundContext())
+}
+
+func (a *MetricAlarms) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+		"BoolValue":        aws.BoolValue,
+		"float":            formatFloat,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_cloudwatch_metric_alarm" .AlarmName }}
+    resource "aws_cloudwatch_metric_alarm" "{{ sanitizeName .AlarmName }}" {
+      alarm_name = "{{ .AlarmName }}"
+      {{- if .AlarmDescription }}
+      alarm_description = {{ hclString .AlarmDescription }}
+      {{- end }}
+      comparison_operator = "{{ .ComparisonOperator }}"
+      evaluation_periods = {{ .EvaluationPeriods }}
+      {{- if .DatapointsToAlarm }}
+      datapoints_to_alarm = {{ .DatapointsToAlarm }}
+      {{- end }}
+      metric_name = "{{ .MetricName }}"
+      namespace = "{{ .Namespace }}"
+      period = {{ .Period }}
+      {{- if .Statistic }}
+      statistic = "{{ .Statistic }}"
+      {{- end }}
+      {{- if .ExtendedStatistic }}
+      extended_statistic = "{{ .ExtendedStatistic }}"
+      {{- end }}
+      threshold = {{ float .Threshold }}
+      {{- if .TreatMissingData }}
+      treat_missing_data = "{{ .TreatMissingData }}"
+      {{- end }}
+      {{- if .Unit }}
+      unit = "{{ .Unit }}"
+      {{- end }}
+      {{- if .ActionsEnabled }}
+      actions_enabled = {{ BoolValue .ActionsEnabled }}
+      {{- end }}
+      {{- if .AlarmActions }}
+      alarm_actions = [{{ StringValueSlice .AlarmActions | joinstring "," }}]
+      {{- end }}
+      {{- if .OKActions }}
+      ok_actions = [{{ StringValueSlice .OKActions | joinstring "," }}]
+      {{- end }}
+      {{- if .InsufficientDataActions }}
+      insufficient_data_actions = [{{ StringValueSlice .InsufficientDataActions | joinstring "," }}]
+      {{- end }}
+      {{- if .Dimensions }}
+      dimensions {
+        {{- range $k, $v := .Dimensions }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, a)
+}
+
+// WriteImports writes the terraform import commands of 'MetricAlarms'
+func (a *MetricAlarms) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_cloudwatch_metric_alarm.{{ sanitizeName .AlarmName }} {{ .AlarmName }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, a)
+}
+
+// formatFloat renders src in decimal notation, e.g. 1000000 rather than
+// the 1e+06 printed by the templates
+func formatFloat(src *float64) string {
+	return strconv.FormatFloat(aws.Float64Value(src), 'f', -1, 64)
+}
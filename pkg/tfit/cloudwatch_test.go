@@ -0,0 +1,35 @@
+package tfit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestMetricAlarmsWriteHCLDescription(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{}
+
+	alarms := &MetricAlarms{{
+		AlarmName:          aws.String("cpu"),
+		AlarmDescription:   aws.String(`CPU of "web" above 80% for ${period}`),
+		ComparisonOperator: aws.String("GreaterThanThreshold"),
+		EvaluationPeriods:  aws.Int64(1),
+		MetricName:         aws.String("CPUUtilization"),
+		Namespace:          aws.String("AWS/EC2"),
+		Period:             aws.Int64(60),
+		Statistic:          aws.String("Average"),
+		Threshold:          aws.Float64(80),
+	}}
+
+	var buf bytes.Buffer
+	if err := alarms.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	want := `alarm_description = "CPU of \"web\" above 80% for $${period}"`
+	if out := strings.Join(strings.Fields(buf.String()), " "); !strings.Contains(out, want) {
+		t.Errorf("want %s in:\n%s", want, buf.String())
+	}
+}
@@ -0,0 +1,262 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+)
+
+//**************** CodeBuild Project ****************
+type CodeBuildProject struct {
+	Name             *string
+	ARN              *string
+	Description      *string
+	ServiceRole      *string
+	TimeoutInMinutes *int64
+	EncryptionKey    *string
+	Source           *codebuild.ProjectSource
+	Environment      *codebuild.ProjectEnvironment
+	Artifacts        *codebuild.ProjectArtifacts
+	Tags             *Tags
+
+	// The environment has PLAINTEXT variables, their values are redacted
+	Redacted bool
+
+	// Set by ResolveReferences when the service role is exported as well
+	ServiceRoleExported *string
+}
+
+type CodeBuildProjects []*CodeBuildProject
+
+func (p *CodeBuildProject) set(src *codebuild.Project) {
+	p.Name = src.Name
+	p.ARN = src.Arn
+	p.Description = src.Description
+	p.ServiceRole = src.ServiceRole
+	p.TimeoutInMinutes = src.TimeoutInMinutes
+	p.EncryptionKey = src.EncryptionKey
+	p.Source = src.Source
+	p.Environment = src.Environment
+	p.Artifacts = src.Artifacts
+
+	if p.Environment != nil {
+		for _, v := range p.Environment.EnvironmentVariables {
+			if v != nil && plaintextVariable(v) {
+				p.Redacted = true
+			}
+		}
+	}
+
+	p.Tags = &Tags{}
+	for _, v := range src.Tags {
+		if v != nil && v.Key != nil {
+			(*p.Tags)[*v.Key] = v.Value
+		}
+	}
+}
+
+// plaintextVariable reports whether the value of v is the value of the
+// variable, which may be a secret, rather than the name of a parameter or
+// secret holding it
+func plaintextVariable(v *codebuild.EnvironmentVariable) bool {
+	return v.Type == nil || aws.StringValue(v.Type) == codebuild.EnvironmentVariableTypePlaintext
+}
+
+// getCodeBuildProjects gets the details of projects, at most 100 of them
+func (c *AWSClient) getCodeBuildProjects(ctx aws.Context, names []*string) (CodeBuildProjects, error) {
+	var res CodeBuildProjects
+
+	out, err := c.codebuildconn.BatchGetProjectsWithContext(ctx, &codebuild.BatchGetProjectsInput{Names: names})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range out.Projects {
+		if v == nil || !c.wantID(v.Name, v.Arn) {
+			continue
+		}
+
+		tmp := &CodeBuildProject{}
+		tmp.set(v)
+		if !c.matchTags(tmp.Tags) {
+			continue
+		}
+
+		res = append(res, tmp)
+	}
+
+	return res, nil
+}
+
+// GetCodeBuildProjectsWithContext returns the projects with their source,
+// build environment & artifacts
+func (c *AWSClient) GetCodeBuildProjectsWithContext(ctx aws.Context) (*CodeBuildProjects, error) {
+	var res CodeBuildProjects
+
+	// ListProjects returns up to 100 names, as many as BatchGetProjects accepts
+	opt := &codebuild.ListProjectsInput{}
+	for {
+		out, err := c.codebuildconn.ListProjectsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(out.Projects) > 0 {
+			projects, err := c.getCodeBuildProjects(ctx, out.Projects)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, projects...)
+		}
+
+		c.progress("aws_codebuild_project", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetCodeBuildProjects calls GetCodeBuildProjectsWithContext with a background context
+func (c *AWSClient) GetCodeBuildProjects() (*CodeBuildProjects, error) {
+	return c.GetCodeBuildProjectsWithContext(aws.BackgroundContext())
+}
+
+func (p *CodeBuildProjects) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"BoolValue":                 aws.BoolValue,
+		"StringValue":               aws.StringValue,
+		"makeTerraformResourceName": makeTerraformResourceName,
+		"plaintext":                 plaintextVariable,
+	}
+
+	// The values of the PLAINTEXT environment variables are placeholders,
+	// the other types (PARAMETER_STORE, SECRETS_MANAGER) only reference
+	// the parameter or secret holding the value
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_codebuild_project" .Name }}
+    resource "aws_codebuild_project" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      {{- if .Description }}
+      description = {{ hclString .Description }}
+      {{- end }}
+      {{- with .ServiceRoleExported }}
+      service_role = "${aws_iam_role.{{ . | makeTerraformResourceName | globalName }}.arn}"
+      {{- else }}
+      service_role = "{{ .ServiceRole }}"
+      {{- end }}
+      {{- if .TimeoutInMinutes }}
+      build_timeout = {{ .TimeoutInMinutes }}
+      {{- end }}
+      {{- if .EncryptionKey }}
+      encryption_key = "{{ .EncryptionKey }}"
+      {{- end }}
+      {{- with .Source }}
+
+      source {
+        type = "{{ .Type }}"
+        {{- if .Location }}
+        location = {{ hclString .Location }}
+        {{- end }}
+        {{- if .Buildspec }}
+        buildspec = {{ hclString .Buildspec }}
+        {{- end }}
+        {{- if .GitCloneDepth }}
+        git_clone_depth = {{ .GitCloneDepth }}
+        {{- end }}
+        {{- if BoolValue .InsecureSsl }}
+        insecure_ssl = true
+        {{- end }}
+        {{- if BoolValue .ReportBuildStatus }}
+        report_build_status = true
+        {{- end }}
+      }
+      {{- end }}
+      {{- with .Environment }}
+
+      environment {
+        compute_type = "{{ .ComputeType }}"
+        image = "{{ .Image }}"
+        type = "{{ .Type }}"
+        {{- if BoolValue .PrivilegedMode }}
+        privileged_mode = true
+        {{- end }}
+        {{- if .Certificate }}
+        certificate = "{{ .Certificate }}"
+        {{- end }}
+        {{- range .EnvironmentVariables }}
+
+        environment_variable {
+          name = "{{ .Name }}"
+          {{- if plaintext . }}
+          value = "REDACTED"
+          {{- else }}
+          value = {{ hclString .Value }}
+          type = "{{ .Type }}"
+          {{- end }}
+        }
+        {{- end }}
+      }
+      {{- end }}
+      {{- with .Artifacts }}
+
+      artifacts {
+        type = "{{ .Type }}"
+        {{- if .Location }}
+        location = "{{ .Location }}"
+        {{- end }}
+        {{- if .Name }}
+        name = "{{ .Name }}"
+        {{- end }}
+        {{- if .NamespaceType }}
+        namespace_type = "{{ .NamespaceType }}"
+        {{- end }}
+        {{- if .Packaging }}
+        packaging = "{{ .Packaging }}"
+        {{- end }}
+        {{- if .Path }}
+        path = "{{ .Path }}"
+        {{- end }}
+        {{- if BoolValue .EncryptionDisabled }}
+        encryption_disabled = true
+        {{- end }}
+      }
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+      {{- if .Redacted }}
+
+      # WARNING: PLAINTEXT environment variables redacted, set them before creating the project
+      lifecycle {
+        ignore_changes = ["environment"]
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, p)
+}
+
+// WriteImports writes the terraform import commands of 'CodeBuildProjects'
+func (p *CodeBuildProjects) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_codebuild_project.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, p)
+}
@@ -0,0 +1,87 @@
+package tfit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+)
+
+func TestCodeBuildProjectEnvironmentVariables(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{}
+
+	variable := func(name, value, typ string) *codebuild.EnvironmentVariable {
+		v := &codebuild.EnvironmentVariable{Name: aws.String(name), Value: aws.String(value)}
+		if typ != "" {
+			v.Type = aws.String(typ)
+		}
+		return v
+	}
+	tests := []struct {
+		name      string
+		variables []*codebuild.EnvironmentVariable
+		want      []string
+		notWant   []string
+	}{
+		{
+			name: "plaintext values redacted",
+			variables: []*codebuild.EnvironmentVariable{
+				variable("API_KEY", "s3cr3t", "PLAINTEXT"),
+				variable("TOKEN", "hunter2", ""),
+			},
+			want: []string{
+				`name = "API_KEY" value = "REDACTED" }`,
+				`name = "TOKEN" value = "REDACTED" }`,
+				`lifecycle { ignore_changes = ["environment"] }`,
+			},
+			notWant: []string{"s3cr3t", "hunter2"},
+		},
+		{
+			name: "references kept",
+			variables: []*codebuild.EnvironmentVariable{
+				variable("DB_PASSWORD", "/shop/db/password", "PARAMETER_STORE"),
+				variable("API_KEY", "shop/api-key", "SECRETS_MANAGER"),
+			},
+			want: []string{
+				`name = "DB_PASSWORD" value = "/shop/db/password" type = "PARAMETER_STORE" }`,
+				`name = "API_KEY" value = "shop/api-key" type = "SECRETS_MANAGER" }`,
+			},
+			notWant: []string{"REDACTED", "lifecycle"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &CodeBuildProject{}
+			p.set(&codebuild.Project{
+				Name:        aws.String("shop"),
+				ServiceRole: aws.String("arn:aws:iam::123456789012:role/codebuild"),
+				Environment: &codebuild.ProjectEnvironment{
+					ComputeType:          aws.String("BUILD_GENERAL1_SMALL"),
+					Image:                aws.String("aws/codebuild/standard:1.0"),
+					Type:                 aws.String("LINUX_CONTAINER"),
+					EnvironmentVariables: tt.variables,
+				},
+			})
+
+			var buf bytes.Buffer
+			if err := (&CodeBuildProjects{p}).WriteHCL(&buf); err != nil {
+				t.Fatalf("WriteHCL: %v", err)
+			}
+			out := strings.Join(strings.Fields(buf.String()), " ")
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("want %s in:\n%s", want, buf.String())
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("%s written in:\n%s", s, buf.String())
+				}
+			}
+		})
+	}
+}
@@ -2,16 +2,51 @@ package tfit
 
 import (
 	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/codebuild"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/glacier"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/redshift"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/sts"
 )
 
 type Config struct {
@@ -21,33 +56,444 @@ type Config struct {
 	Profile   string
 	Token     string
 	Region    string
+
+	// RoleARN is the role assumed, with the credentials above, before
+	// calling the APIs. ExternalID is passed along when set
+	RoleARN    string
+	ExternalID string
+
+	// MFASerial is the MFA device required to assume RoleARN, if any.
+	// TokenProvider returns its current token code
+	MFASerial     string
+	TokenProvider func() (string, error)
+
+	// MaxRetries is the number of times a throttled or failed request is
+	// retried, with an exponential backoff. 0 disables the retries,
+	// NewConfig sets it to DefaultMaxRetries. Client errors, e.g. an
+	// access denied, are never retried
+	MaxRetries int
+
+	// Endpoint, when set, is the URL every service is called at instead
+	// of the AWS one, e.g. a LocalStack instance. S3 buckets are then
+	// addressed by path
+	Endpoint string
+}
+
+// NewConfig returns a Config with the defaults of the AWS CLI: the shared
+// credentials file & the region of the environment. Credentials are left
+// to the environment or the shared credentials file
+func NewConfig() *Config {
+	c := &Config{
+		CredsFile:  os.Getenv("AWS_SHARED_CREDENTIALS_FILE"),
+		Region:     os.Getenv("AWS_REGION"),
+		MaxRetries: DefaultMaxRetries,
+	}
+
+	if len(c.CredsFile) == 0 {
+		c.CredsFile = defaults.SharedCredentialsFilename()
+	}
+	if len(c.Region) == 0 {
+		c.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+
+	return c
+}
+
+// awsConfig returns the settings of the sessions of c, signed with creds
+func (c *Config) awsConfig(creds *credentials.Credentials) *aws.Config {
+	cfg := request.WithRetryer(&aws.Config{Credentials: creds}, newRetryer(c.MaxRetries))
+	if len(c.Endpoint) == 0 {
+		return cfg
+	}
+
+	cfg.S3ForcePathStyle = aws.Bool(true)
+	cfg.EndpointResolver = endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		// Global services have no region to sign their calls with
+		if len(region) == 0 {
+			region = endpoints.UsEast1RegionID
+		}
+		return endpoints.ResolvedEndpoint{URL: c.Endpoint, SigningRegion: region}, nil
+	})
+
+	return cfg
+}
+
+// Logger is satisfied by *log.Logger
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// ec2API is the part of *ec2.EC2 used by AWSClient, to be replaced
+// by a fake in unit tests
+type ec2API interface {
+	DescribeCustomerGatewaysWithContext(aws.Context, *ec2.DescribeCustomerGatewaysInput, ...request.Option) (*ec2.DescribeCustomerGatewaysOutput, error)
+	DescribeDhcpOptionsWithContext(aws.Context, *ec2.DescribeDhcpOptionsInput, ...request.Option) (*ec2.DescribeDhcpOptionsOutput, error)
+	DescribeInstanceAttributeWithContext(aws.Context, *ec2.DescribeInstanceAttributeInput, ...request.Option) (*ec2.DescribeInstanceAttributeOutput, error)
+	DescribeInstancesWithContext(aws.Context, *ec2.DescribeInstancesInput, ...request.Option) (*ec2.DescribeInstancesOutput, error)
+	DescribeNetworkAclsWithContext(aws.Context, *ec2.DescribeNetworkAclsInput, ...request.Option) (*ec2.DescribeNetworkAclsOutput, error)
+	DescribeNetworkInterfacesWithContext(aws.Context, *ec2.DescribeNetworkInterfacesInput, ...request.Option) (*ec2.DescribeNetworkInterfacesOutput, error)
+	DescribePlacementGroupsWithContext(aws.Context, *ec2.DescribePlacementGroupsInput, ...request.Option) (*ec2.DescribePlacementGroupsOutput, error)
+	DescribeRegionsWithContext(aws.Context, *ec2.DescribeRegionsInput, ...request.Option) (*ec2.DescribeRegionsOutput, error)
+	DescribeRouteTablesWithContext(aws.Context, *ec2.DescribeRouteTablesInput, ...request.Option) (*ec2.DescribeRouteTablesOutput, error)
+	DescribeSecurityGroupsWithContext(aws.Context, *ec2.DescribeSecurityGroupsInput, ...request.Option) (*ec2.DescribeSecurityGroupsOutput, error)
+	DescribeSpotInstanceRequestsWithContext(aws.Context, *ec2.DescribeSpotInstanceRequestsInput, ...request.Option) (*ec2.DescribeSpotInstanceRequestsOutput, error)
+	DescribeSubnetsWithContext(aws.Context, *ec2.DescribeSubnetsInput, ...request.Option) (*ec2.DescribeSubnetsOutput, error)
+	DescribeTagsWithContext(aws.Context, *ec2.DescribeTagsInput, ...request.Option) (*ec2.DescribeTagsOutput, error)
+	DescribeTransitGatewayVpcAttachmentsWithContext(aws.Context, *ec2.DescribeTransitGatewayVpcAttachmentsInput, ...request.Option) (*ec2.DescribeTransitGatewayVpcAttachmentsOutput, error)
+	DescribeTransitGatewaysWithContext(aws.Context, *ec2.DescribeTransitGatewaysInput, ...request.Option) (*ec2.DescribeTransitGatewaysOutput, error)
+	DescribeVolumesWithContext(aws.Context, *ec2.DescribeVolumesInput, ...request.Option) (*ec2.DescribeVolumesOutput, error)
+	DescribeVpcAttributeWithContext(aws.Context, *ec2.DescribeVpcAttributeInput, ...request.Option) (*ec2.DescribeVpcAttributeOutput, error)
+	DescribeVpcClassicLinkDnsSupportWithContext(aws.Context, *ec2.DescribeVpcClassicLinkDnsSupportInput, ...request.Option) (*ec2.DescribeVpcClassicLinkDnsSupportOutput, error)
+	DescribeVpcClassicLinkWithContext(aws.Context, *ec2.DescribeVpcClassicLinkInput, ...request.Option) (*ec2.DescribeVpcClassicLinkOutput, error)
+	DescribeVpcEndpointsWithContext(aws.Context, *ec2.DescribeVpcEndpointsInput, ...request.Option) (*ec2.DescribeVpcEndpointsOutput, error)
+	DescribeVpcPeeringConnectionsWithContext(aws.Context, *ec2.DescribeVpcPeeringConnectionsInput, ...request.Option) (*ec2.DescribeVpcPeeringConnectionsOutput, error)
+	DescribeVpcsWithContext(aws.Context, *ec2.DescribeVpcsInput, ...request.Option) (*ec2.DescribeVpcsOutput, error)
+	DescribeVpnConnectionsWithContext(aws.Context, *ec2.DescribeVpnConnectionsInput, ...request.Option) (*ec2.DescribeVpnConnectionsOutput, error)
+	DescribeVpnGatewaysWithContext(aws.Context, *ec2.DescribeVpnGatewaysInput, ...request.Option) (*ec2.DescribeVpnGatewaysOutput, error)
 }
 
 type AWSClient struct {
-	r53conn *route53.Route53
-	ec2conn *ec2.EC2
-	iamconn *iam.IAM
-	asconn  *autoscaling.AutoScaling
-	s3conn  *s3.S3
-	elbconn *elb.ELB
+	// Logger receives debug messages such as pagination progress.
+	// Client() sets it to a logger discarding everything
+	Logger Logger
+
+	// OnProgress, if set, is called after each page of results with the
+	// Terraform type of the resources being collected and how many of them
+	// were kept so far
+	OnProgress func(resourceType string, count int)
+
+	// TagFilter restricts the collected resources to the ones carrying
+	// every one of these tags. EC2 resources are filtered by the API,
+	// the others once fetched. Resources which cannot be tagged, e.g.
+	// IAM groups or placement groups, are excluded
+	TagFilter map[string]string
+
+	// IDs restricts the collected resources to the ones with one of these
+	// IDs, ExcludeIDs skips the ones listed. Instances are filtered by the
+	// API, the others once fetched
+	IDs        []string
+	ExcludeIDs []string
+
+	// CreatedAfter, unless zero, restricts the collected resources to the
+	// ones created after it. It applies to the EC2 instances, VPC
+	// endpoints, spot requests, transit gateways (with their VPC
+	// attachments), S3 buckets, IAM users, groups, roles, policies &
+	// instance profiles, RDS & Redshift clusters, KMS keys, log groups,
+	// ElastiCache clusters & replication groups, ACM certificates and EFS
+	// file systems. The APIs give no creation time for the other
+	// resources, they are always kept
+	CreatedAfter time.Time
+
+	// DecryptSecureParams makes SSM SecureString parameters be fetched
+	// decrypted. Their values are left out otherwise
+	DecryptSecureParams bool
+
+	// SkipASGInstances leaves out the instances launched by an auto scaling
+	// group, which manages them already
+	SkipASGInstances bool
+
+	// ContinueOnError makes the resources which fail to be fetched be
+	// skipped, their errors being returned in a *MultiError along with the
+	// other resources, instead of failing the whole collection. Only
+	// instances & VPCs support it, the API calls listing them still fail
+	// the collection
+	ContinueOnError bool
+
+	// Detailed makes the attributes costing an API call per resource be
+	// fetched, e.g. the termination protection of instances
+	Detailed bool
+
+	// MaxVPCRoutines is the number of VPCs whose attributes GetVPCs looks
+	// up concurrently, DefaultVPCRoutines when not set
+	MaxVPCRoutines int
+
+	// region the regional services are bound to
+	region string
+
+	// sess creates the S3 connections of the buckets of other regions,
+	// see s3connFor
+	sess     *session.Session
+	s3conns  map[string]*s3.S3
+	s3connMu sync.Mutex
+
+	// accountID is looked up on first use, see accountIDWithContext
+	accountID   string
+	accountIDMu sync.Mutex
+
+	r53conn  *route53.Route53
+	ec2conn  ec2API
+	iamconn  *iam.IAM
+	asconn   *autoscaling.AutoScaling
+	s3conn   *s3.S3
+	elbconn  *elb.ELB
+	kmsconn  *kms.KMS
+	logsconn *cloudwatchlogs.CloudWatchLogs
+	cwconn   *cloudwatch.CloudWatch
+	ecrconn  *ecr.ECR
+	ssmconn  *ssm.SSM
+	efsconn  *efs.EFS
+	acmconn  *acm.ACM
+	ecsconn  *ecs.ECS
+
+	secretsconn    *secretsmanager.SecretsManager
+	apigatewayconn *apigateway.APIGateway
+	redshiftconn   *redshift.Redshift
+
+	elasticacheconn *elasticache.ElastiCache
+	cloudfrontconn  *cloudfront.CloudFront
+	beanstalkconn   *elasticbeanstalk.ElasticBeanstalk
+	kinesisconn     *kinesis.Kinesis
+	cloudtrailconn  *cloudtrail.CloudTrail
+	sesconn         *ses.SES
+	sfnconn         *sfn.SFN
+	glacierconn     *glacier.Glacier
+	codebuildconn   *codebuild.CodeBuild
+	rdsconn         *rds.RDS
+	orgconn         *organizations.Organizations
+	stsconn         *sts.STS
+
+	appautoscalingconn *applicationautoscaling.ApplicationAutoScaling
 }
 
+// Client calls NewAWSClient with c
 func (c *Config) Client() (*AWSClient, error) {
+	return NewAWSClient(c)
+}
+
+// NewAWSClient builds the AWS session of c and the connections of every
+// service. Global services ignore c.Region
+func NewAWSClient(c *Config) (*AWSClient, error) {
 	var client AWSClient
-	creds := GetCredentials(c)
+	client.Logger = log.New(ioutil.Discard, "", 0)
+	creds, err := GetCredentials(c)
+	if err != nil {
+		return nil, err
+	}
 
-	sess, err := session.NewSession(&aws.Config{Credentials: creds})
+	sess, err := session.NewSession(c.awsConfig(creds))
 	if err != nil {
 		return nil, fmt.Errorf("Error creating AWS session: %s", err)
 	}
 
 	client.r53conn = route53.New(sess)
 	client.iamconn = iam.New(sess)
+	client.cloudfrontconn = cloudfront.New(sess)
+	client.orgconn = organizations.New(sess)
 	client.s3conn = s3.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.sess = sess
 
+	client.region = c.Region
 	client.ec2conn = ec2.New(sess, aws.NewConfig().WithRegion(c.Region))
 	client.asconn = autoscaling.New(sess, aws.NewConfig().WithRegion(c.Region))
 	client.elbconn = elb.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.kmsconn = kms.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.logsconn = cloudwatchlogs.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.cwconn = cloudwatch.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.ecrconn = ecr.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.ssmconn = ssm.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.secretsconn = secretsmanager.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.efsconn = efs.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.acmconn = acm.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.apigatewayconn = apigateway.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.redshiftconn = redshift.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.ecsconn = ecs.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.elasticacheconn = elasticache.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.appautoscalingconn = applicationautoscaling.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.beanstalkconn = elasticbeanstalk.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.kinesisconn = kinesis.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.cloudtrailconn = cloudtrail.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.sesconn = ses.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.sfnconn = sfn.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.glacierconn = glacier.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.codebuildconn = codebuild.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.rdsconn = rds.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.stsconn = sts.New(sess, aws.NewConfig().WithRegion(c.Region))
 
 	return &client, nil
 }
+
+// DefaultMaxRetries is the number of retries of the requests, see
+// Config.MaxRetries
+const DefaultMaxRetries = 5
+
+// maxRetryDelay caps the backoff between two retries, the SDK one
+// reaching minutes once a request has been throttled a few times
+const maxRetryDelay = 20 * time.Second
+
+// retryer is client.DefaultRetryer, which retries throttled requests,
+// 5xx responses, connection resets & expired credentials, with its delay
+// capped at maxRetryDelay
+type retryer struct {
+	client.DefaultRetryer
+}
+
+func newRetryer(maxRetries int) retryer {
+	return retryer{client.DefaultRetryer{NumMaxRetries: maxRetries}}
+}
+
+// RetryRules returns the delay of client.DefaultRetryer, capped at
+// maxRetryDelay
+func (r retryer) RetryRules(req *request.Request) time.Duration {
+	if delay := r.DefaultRetryer.RetryRules(req); delay < maxRetryDelay {
+		return delay
+	}
+
+	return maxRetryDelay
+}
+
+// Region returns the region the regional services are bound to
+func (c *AWSClient) Region() string {
+	return c.region
+}
+
+// partition returns the partition of the region the regional services
+// are bound to, e.g. "aws-cn"
+func (c *AWSClient) partition() string {
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), c.region); ok {
+		return p.ID()
+	}
+	return endpoints.AwsPartitionID
+}
+
+// accountIDWithContext returns the ID of the account of the credentials,
+// needed by the APIs taking ARNs the resources are not described with
+func (c *AWSClient) accountIDWithContext(ctx aws.Context) (string, error) {
+	c.accountIDMu.Lock()
+	defer c.accountIDMu.Unlock()
+	if c.accountID != "" {
+		return c.accountID, nil
+	}
+
+	out, err := c.stsconn.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("Error calling GetCallerIdentity: %s", err)
+	}
+	c.accountID = aws.StringValue(out.Account)
+
+	return c.accountID, nil
+}
+
+// s3connFor returns the S3 connection of region, where the calls about
+// the buckets of that region have to be made. It is created on first use
+func (c *AWSClient) s3connFor(region *string) *s3.S3 {
+	if region == nil || *region == c.region || c.sess == nil {
+		return c.s3conn
+	}
+
+	c.s3connMu.Lock()
+	defer c.s3connMu.Unlock()
+	if c.s3conns == nil {
+		c.s3conns = make(map[string]*s3.S3)
+	}
+	if _, ok := c.s3conns[*region]; !ok {
+		c.s3conns[*region] = s3.New(c.sess, aws.NewConfig().WithRegion(*region))
+	}
+	return c.s3conns[*region]
+}
+
+// logf writes a debug message to c.Logger, if any
+func (c *AWSClient) logf(format string, v ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, v...)
+	}
+}
+
+// progress reports the count of resourceType collected so far to c.OnProgress, if any
+func (c *AWSClient) progress(resourceType string, count int) {
+	if c.OnProgress != nil {
+		c.OnProgress(resourceType, count)
+	}
+}
+
+// GetRegionsWithContext returns the name of every region enabled for the account
+func (c *AWSClient) GetRegionsWithContext(ctx aws.Context) ([]string, error) {
+	output, err := c.ec2conn.DescribeRegionsWithContext(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, v := range output.Regions {
+		if v != nil && v.RegionName != nil {
+			res = append(res, *v.RegionName)
+		}
+	}
+
+	return res, nil
+}
+
+// ec2TagFilters returns TagFilter as EC2 describe filters
+func (c *AWSClient) ec2TagFilters() []*ec2.Filter {
+	var res []*ec2.Filter
+	for k, v := range c.TagFilter {
+		res = append(res, &ec2.Filter{
+			Name:   aws.String("tag:" + k),
+			Values: []*string{aws.String(v)},
+		})
+	}
+
+	return res
+}
+
+// matchTags reports whether tags, in any form known by flattenTags,
+// carry every tag of TagFilter
+func (c *AWSClient) matchTags(tags interface{}) bool {
+	if len(c.TagFilter) == 0 {
+		return true
+	}
+
+	flat := flattenTags(tags)
+	for k, v := range c.TagFilter {
+		if value, ok := flat[k]; !ok || value != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// untaggable reports whether the resources which cannot be tagged have
+// to be skipped
+func (c *AWSClient) untaggable() bool {
+	return len(c.TagFilter) > 0
+}
+
+// wantID reports whether a resource known by any of ids passes the IDs
+// and ExcludeIDs filters
+func (c *AWSClient) wantID(ids ...*string) bool {
+	for _, id := range ids {
+		if id != nil && containsString(c.ExcludeIDs, *id) {
+			return false
+		}
+	}
+
+	if len(c.IDs) == 0 {
+		return true
+	}
+
+	for _, id := range ids {
+		if id != nil && containsString(c.IDs, *id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wantCreated reports whether a resource created at t passes the
+// CreatedAfter filter. Resources without a creation time pass it
+func (c *AWSClient) wantCreated(t *time.Time) bool {
+	return c.CreatedAfter.IsZero() || t == nil || t.After(c.CreatedAfter)
+}
+
+// instanceIDs returns the instance IDs found in IDs
+func (c *AWSClient) instanceIDs() []*string {
+	var res []*string
+	for _, id := range c.IDs {
+		if strings.HasPrefix(id, "i-") {
+			res = append(res, aws.String(id))
+		}
+	}
+
+	return res
+}
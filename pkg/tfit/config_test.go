@@ -0,0 +1,106 @@
+package tfit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestRetryer(t *testing.T) {
+	if n := (&Config{MaxRetries: 0}).awsConfig(nil).Retryer.(request.Retryer).MaxRetries(); n != 0 {
+		t.Errorf("MaxRetries 0: the requests are retried %d times", n)
+	}
+	if n := NewConfig().awsConfig(nil).Retryer.(request.Retryer).MaxRetries(); n != DefaultMaxRetries {
+		t.Errorf("NewConfig: the requests are retried %d times, want %d", n, DefaultMaxRetries)
+	}
+
+	tests := []struct {
+		name   string
+		status int
+		err    error
+		want   bool
+	}{
+		{name: "throttled", status: 400, err: awserr.New("Throttling", "Rate exceeded", nil), want: true},
+		{name: "S3 slow down", status: 503, err: awserr.New("SlowDown", "Reduce your request rate", nil), want: true},
+		{name: "server error", status: 500, err: awserr.New("InternalError", "", nil), want: true},
+		{name: "connection reset", status: 0, err: awserr.New("RequestError", "send request failed", errors.New("read: connection reset by peer")), want: true},
+		{name: "expired credentials", status: 400, err: awserr.New("ExpiredToken", "The security token included in the request is expired", nil), want: true},
+		{name: "access denied", status: 403, err: awserr.New("AccessDenied", "", nil), want: false},
+		{name: "validation", status: 400, err: awserr.New("ValidationError", "", nil), want: false},
+	}
+
+	r := newRetryer(DefaultMaxRetries)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &request.Request{
+				HTTPResponse: &http.Response{StatusCode: tt.status, Header: http.Header{}},
+				Error:        tt.err,
+				RetryCount:   30,
+			}
+			if got := r.ShouldRetry(req); got != tt.want {
+				t.Errorf("ShouldRetry = %v, want %v", got, tt.want)
+			}
+			if delay := r.RetryRules(req); delay > maxRetryDelay {
+				t.Errorf("RetryRules = %v, want at most %v", delay, maxRetryDelay)
+			}
+		})
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The credential scope of the signature names the region &
+		// service: AKID/date/region/service/aws4_request
+		scope := strings.Split(strings.SplitN(r.Header.Get("Authorization"), "Credential=", 2)[1], "/")
+		mu.Lock()
+		requests = append(requests, fmt.Sprintf("%s %s %s/%s", r.Method, r.URL.Path, scope[2], scope[3]))
+		mu.Unlock()
+
+		switch scope[3] {
+		case "s3":
+			fmt.Fprint(w, `<ListBucketResult><Name>logs</Name><IsTruncated>false</IsTruncated></ListBucketResult>`)
+		case "iam":
+			fmt.Fprint(w, `<ListRolesResponse><ListRolesResult><IsTruncated>false</IsTruncated></ListRolesResult></ListRolesResponse>`)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewAWSClient(&Config{
+		AccessKey: "AKID",
+		SecretKey: "SECRET",
+		Region:    "eu-west-1",
+		Endpoint:  srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewAWSClient: %v", err)
+	}
+
+	if _, err := c.s3conn.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String("logs")}); err != nil {
+		t.Fatalf("ListObjectsV2: %v", err)
+	}
+	if _, err := c.iamconn.ListRoles(&iam.ListRolesInput{}); err != nil {
+		t.Fatalf("ListRoles: %v", err)
+	}
+
+	// Buckets are addressed by path, global services are signed for
+	// us-east-1
+	want := []string{"GET /logs eu-west-1/s3", "POST / us-east-1/iam"}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests %q, want %q", requests, want)
+	}
+}
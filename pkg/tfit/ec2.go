@@ -1,10 +1,18 @@
 package tfit
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"sort"
 	"strings"
 	"text/template"
+	"time"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -19,16 +27,58 @@ type Instance struct {
 	InstanceType       *string
 	KeyName            *string
 	Monitoring         *bool
+	PlacementGroup     *string
+	AvailabilityZone   *string
+	Tenancy            *string
+	Affinity           *string
 	SecurityGroups     []*string
+	SecurityGroupIDs   []*string
 	SourceDestCheck    *bool
 	SubnetID           *string
 	VpcID              *string
-	Tags               map[*string]*string
+	LaunchTime         *time.Time
+	Tags               *Tags
+
+	// Set for the instances launched by a spot request
+	SpotInstanceRequestID *string
+
+	// describe-instance-attribute, only one of them is set
+	UserData       *string
+	UserDataBase64 *string
+	// The decompressed UserDataBase64 when it is gzip compressed, only
+	// rendered as a comment
+	UserDataGunzipped *string
+
+	// describe-instance-attribute, only fetched by detailed clients
+	DisableAPITermination             *bool
+	InstanceInitiatedShutdownBehavior *string
+
+	RootBlockDevice *BlockDevice
+	EBSBlockDevices []*BlockDevice
+
+	// Set by ResolveReferences when the profile, respectively the
+	// placement group, is exported as well
+	IamInstanceProfileExported bool
+	PlacementGroupExported     bool
 }
 
 // A group of Instance
 type Instances []*Instance
 
+// BlockDevice is an EBS volume attached to an instance
+type BlockDevice struct {
+	DeviceName          *string
+	VolumeID            *string
+	DeleteOnTermination *bool
+
+	// describe-volumes
+	VolumeSize *int64
+	VolumeType *string
+	Iops       *int64
+	Encrypted  *bool
+	SnapshotID *string
+}
+
 func (i *Instance) set(src *ec2.Instance) error {
 	i.EbsOptimized = src.EbsOptimized
 
@@ -41,16 +91,42 @@ func (i *Instance) set(src *ec2.Instance) error {
 	i.InstanceID = src.InstanceId
 	i.InstanceType = src.InstanceType
 	i.KeyName = src.KeyName
-	if strings.Compare(aws.StringValue(src.Monitoring.State), "disabled") == 0 {
-		i.Monitoring = aws.Bool(false)
-	} else {
+	i.LaunchTime = src.LaunchTime
+	// Missing monitoring block is treated as "disabled"
+	i.Monitoring = aws.Bool(false)
+	if src.Monitoring != nil && safeString(src.Monitoring.State, "disabled") != "disabled" {
 		i.Monitoring = aws.Bool(true)
 	}
 
-	// Build []*string from []*ec2.GroupIdentifier
+	// Build []*string from []*ec2.GroupIdentifier, sorted so that the output
+	// does not depend on the API order.
+	// Names are only used by EC2-Classic instances, VPC instances need IDs
 	if src.SecurityGroups != nil {
 		for _, sg := range src.SecurityGroups {
+			if sg == nil {
+				continue
+			}
 			i.SecurityGroups = append(i.SecurityGroups, sg.GroupName)
+			i.SecurityGroupIDs = append(i.SecurityGroupIDs, sg.GroupId)
+		}
+	}
+	sortStrings(i.SecurityGroups)
+	sortStrings(i.SecurityGroupIDs)
+
+	i.SpotInstanceRequestID = src.SpotInstanceRequestId
+
+	if p := src.Placement; p != nil {
+		if len(aws.StringValue(p.GroupName)) > 0 {
+			i.PlacementGroup = p.GroupName
+		}
+		i.AvailabilityZone = p.AvailabilityZone
+		// "default" is the implicit tenancy, it is left out
+		if t := aws.StringValue(p.Tenancy); len(t) > 0 && t != ec2.TenancyDefault {
+			i.Tenancy = p.Tenancy
+		}
+		// Only set for instances of dedicated hosts
+		if len(aws.StringValue(p.Affinity)) > 0 {
+			i.Affinity = p.Affinity
 		}
 	}
 
@@ -58,26 +134,219 @@ func (i *Instance) set(src *ec2.Instance) error {
 	i.SubnetID = src.SubnetId
 	i.VpcID = src.VpcId
 
-	// Build map[*]*string from []*ec2.Tag
-	if src.Tags != nil {
-		i.Tags = make(map[*string]*string)
-		for _, t := range src.Tags {
-			i.Tags[t.Key] = t.Value
+	i.Tags = &Tags{}
+	i.Tags.setTags(src.Tags)
+
+	// Only EBS volumes are listed by describe-instances, the root one is
+	// told apart by its device name. Instance store devices are not
+	// reported at all
+	for _, v := range src.BlockDeviceMappings {
+		if v == nil || v.Ebs == nil {
+			continue
+		}
+
+		tmp := &BlockDevice{
+			DeviceName:          v.DeviceName,
+			VolumeID:            v.Ebs.VolumeId,
+			DeleteOnTermination: v.Ebs.DeleteOnTermination,
+		}
+		if aws.StringValue(v.DeviceName) == aws.StringValue(src.RootDeviceName) {
+			i.RootBlockDevice = tmp
+		} else {
+			i.EBSBlockDevices = append(i.EBSBlockDevices, tmp)
+		}
+	}
+	sort.Slice(i.EBSBlockDevices, func(x, y int) bool {
+		return aws.StringValue(i.EBSBlockDevices[x].DeviceName) < aws.StringValue(i.EBSBlockDevices[y].DeviceName)
+	})
+
+	return nil
+}
+
+// blockDevices returns every block device of i
+func (i *Instance) blockDevices() []*BlockDevice {
+	res := i.EBSBlockDevices
+	if i.RootBlockDevice != nil {
+		res = append([]*BlockDevice{i.RootBlockDevice}, res...)
+	}
+
+	return res
+}
+
+// setVolumes fills the block devices of instances with the details
+// of their volumes, which describe-instances does not return
+func (c *AWSClient) setVolumes(ctx aws.Context, instances *Instances) error {
+	devices := map[string][]*BlockDevice{}
+	var ids []*string
+	for _, i := range *instances {
+		for _, d := range i.blockDevices() {
+			if d.VolumeID == nil {
+				continue
+			}
+			if _, ok := devices[*d.VolumeID]; !ok {
+				ids = append(ids, d.VolumeID)
+			}
+			devices[*d.VolumeID] = append(devices[*d.VolumeID], d)
+		}
+	}
+
+	// A filter, unlike VolumeIds, does not fail on a volume deleted
+	// meanwhile. It accepts up to 200 values
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > 200 {
+			n = 200
+		}
+
+		opt := &ec2.DescribeVolumesInput{
+			Filters: []*ec2.Filter{{Name: aws.String("volume-id"), Values: ids[:n]}},
+		}
+		ids = ids[n:]
+
+		for {
+			out, err := c.ec2conn.DescribeVolumesWithContext(ctx, opt)
+			if err != nil {
+				return err
+			}
+
+			for _, v := range out.Volumes {
+				if v == nil {
+					continue
+				}
+				for _, d := range devices[aws.StringValue(v.VolumeId)] {
+					d.VolumeSize = v.Size
+					d.VolumeType = v.VolumeType
+					d.Iops = v.Iops
+					d.Encrypted = v.Encrypted
+					d.SnapshotID = v.SnapshotId
+				}
+			}
+
+			if out.NextToken == nil {
+				break
+			}
+			opt.NextToken = out.NextToken
+		}
+	}
+
+	return nil
+}
+
+// setShutdownAttributes fetches the termination protection & the shutdown
+// behavior of i, one describe-instance-attribute call each
+func (c *AWSClient) setShutdownAttributes(ctx aws.Context, i *Instance) error {
+	out, err := c.ec2conn.DescribeInstanceAttributeWithContext(ctx, &ec2.DescribeInstanceAttributeInput{
+		Attribute:  aws.String(ec2.InstanceAttributeNameDisableApiTermination),
+		InstanceId: i.InstanceID,
+	})
+	if err != nil {
+		return err
+	}
+	if out.DisableApiTermination != nil {
+		i.DisableAPITermination = out.DisableApiTermination.Value
+	}
+
+	out, err = c.ec2conn.DescribeInstanceAttributeWithContext(ctx, &ec2.DescribeInstanceAttributeInput{
+		Attribute:  aws.String(ec2.InstanceAttributeNameInstanceInitiatedShutdownBehavior),
+		InstanceId: i.InstanceID,
+	})
+	if err != nil {
+		return err
+	}
+	if out.InstanceInitiatedShutdownBehavior != nil {
+		i.InstanceInitiatedShutdownBehavior = out.InstanceInitiatedShutdownBehavior.Value
+	}
+
+	return nil
+}
+
+// gunzipUserData returns the decompressed data when it starts with the
+// gzip magic bytes, as cloud-init accepts it, and nil otherwise or when it
+// is not a valid gzip stream
+func gunzipUserData(data []byte) []byte {
+	if !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		return nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+
+	res, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil
+	}
+	return res
+}
+
+// setUserData fetches the user data of i, which describe-instances does
+// not return. It is kept base64 encoded when it can not be rendered as a
+// heredoc: not UTF-8 or without trailing newline, which a heredoc adds.
+// Gzip compressed data is kept as is too, the decompressed data would
+// differ from the stored one and force the replacement of the instance
+func (c *AWSClient) setUserData(ctx aws.Context, i *Instance) error {
+	out, err := c.ec2conn.DescribeInstanceAttributeWithContext(ctx, &ec2.DescribeInstanceAttributeInput{
+		Attribute:  aws.String(ec2.InstanceAttributeNameUserData),
+		InstanceId: i.InstanceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if out.UserData == nil || len(aws.StringValue(out.UserData.Value)) == 0 {
+		return nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(aws.StringValue(out.UserData.Value))
+	if err != nil {
+		return err
+	}
+
+	if plain := gunzipUserData(data); plain != nil {
+		i.UserDataBase64 = out.UserData.Value
+		if utf8.Valid(plain) {
+			i.UserDataGunzipped = aws.String(string(plain))
 		}
+		return nil
+	}
+
+	if utf8.Valid(data) && bytes.HasSuffix(data, []byte("\n")) {
+		i.UserData = aws.String(string(data))
+	} else {
+		i.UserDataBase64 = out.UserData.Value
 	}
 
 	return nil
 }
 
+// isASGInstance reports whether the instance was launched by an auto
+// scaling group, which tags it with its name
+func isASGInstance(src *ec2.Instance) bool {
+	for _, v := range src.Tags {
+		if v != nil && aws.StringValue(v.Key) == "aws:autoscaling:groupName" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (i *Instances) set(src []*ec2.Instance) {
 	if src == nil {
 		return
 	}
 
 	for _, v := range src {
+		if v == nil {
+			continue
+		}
+
 		// Check if instance's state is 'terminated'
 		// https://docs.aws.amazon.com/sdk-for-go/api/service/ec2/#InstanceState
-		if aws.Int64Value(v.State.Code) == 48 {
+		// Instances without state are kept rather than guessed as terminated
+		if v.State != nil && safeInt64(v.State.Code, -1) == 48 {
 			continue
 		}
 
@@ -88,31 +357,87 @@ func (i *Instances) set(src []*ec2.Instance) {
 }
 
 // DescribeAllInstances ...
-func (c *AWSClient) GetInstances() (*Instances, error) {
+func (c *AWSClient) GetInstancesWithContext(ctx aws.Context) (*Instances, error) {
 	ec2conn := c.ec2conn
 	instances := &Instances{}
 
-	opt := &ec2.DescribeInstancesInput{}
+	opt := &ec2.DescribeInstancesInput{Filters: c.ec2TagFilters()}
+	if len(c.IDs) > 0 {
+		// None of the requested resources is an instance
+		ids := c.instanceIDs()
+		if len(ids) == 0 {
+			return instances, nil
+		}
+
+		// Unlike InstanceIds, which fails the whole call with
+		// InvalidInstanceID.NotFound, the filter ignores the IDs of
+		// instances terminated since
+		opt.Filters = append(opt.Filters, &ec2.Filter{Name: aws.String("instance-id"), Values: ids})
+	}
+
 	for {
-		out, err := ec2conn.DescribeInstances(opt)
+		out, err := ec2conn.DescribeInstancesWithContext(ctx, opt)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, rsv := range out.Reservations {
-			instances.set(rsv.Instances)
+			if rsv == nil {
+				continue
+			}
+			var wanted []*ec2.Instance
+			for _, v := range rsv.Instances {
+				if v == nil || !c.wantID(v.InstanceId) || !c.wantCreated(v.LaunchTime) || (c.SkipASGInstances && isASGInstance(v)) {
+					continue
+				}
+				wanted = append(wanted, v)
+			}
+			instances.set(wanted)
 		}
 
+		c.progress("aws_instance", len(*instances))
+
 		if out.NextToken != nil {
 			opt.NextToken = out.NextToken
-			fmt.Println(aws.StringValue(opt.NextToken))
+			c.logf("DescribeInstances: %d instances so far, fetching next page", len(*instances))
 		} else {
-			//fmt.Println("Breaking.......")
 			break
 		}
 	}
 
-	return instances, nil
+	errs := &MultiError{}
+	fetched := Instances{}
+	for _, v := range *instances {
+		err := c.setUserData(ctx, v)
+		if err == nil && c.Detailed {
+			err = c.setShutdownAttributes(ctx, v)
+		}
+		if err != nil {
+			if !c.ContinueOnError || ctx.Err() != nil {
+				return nil, err
+			}
+			errs.add(v.InstanceID, err)
+			continue
+		}
+		fetched = append(fetched, v)
+	}
+	*instances = fetched
+
+	// The volumes are described by batches, a failure leaves the sizes &
+	// types of the block devices out but keeps the instances
+	if err := c.setVolumes(ctx, instances); err != nil {
+		if !c.ContinueOnError || ctx.Err() != nil {
+			return nil, err
+		}
+		errs.Errors = append(errs.Errors, fmt.Errorf("volumes: %v", err))
+	}
+
+	return instances, errs.errorOrNil()
+}
+
+// GetInstances calls GetInstancesWithContext with a background context
+func (c *AWSClient) GetInstances() (*Instances, error) {
+	return c.GetInstancesWithContext(aws.BackgroundContext())
 }
 
 // Render will render terraform format from 'Instances'
@@ -120,18 +445,31 @@ func (i *Instances) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"joinstring":       joinStringSlice,
 		"StringValueSlice": aws.StringValueSlice,
+		"heredoc":          heredoc,
+		"commentLines":     commentLines,
+		"StringValue":      aws.StringValue,
+		"Int64Value":       aws.Int64Value,
+		"BoolValue":        aws.BoolValue,
+
+		"makeTerraformResourceName": makeTerraformResourceName,
 	}
 
 	tmpl := `
 	{{ if . }}
 		{{ range . }}
-	resource "aws_instance" "{{ .InstanceID }}_instance" {
+	{{ resourceID "aws_instance" .InstanceID }}
+	resource "aws_instance" "{{ sanitizeName .InstanceID }}_instance" {
 		ami = "{{ .ImageID }}"
 		instance_type = "{{ .InstanceType }}"
+		{{- if .AvailabilityZone }}
+		availability_zone = "{{ .AvailabilityZone }}"
+		{{- end }}
 		{{- if .EbsOptimized }}
 		ebs_optimized = {{ .EbsOptimized }}
 		{{- end }}
-		{{- if .IamInstanceProfile }}
+		{{- if .IamInstanceProfileExported }}
+		iam_instance_profile = "${aws_iam_instance_profile.{{ .IamInstanceProfile | makeTerraformResourceName | globalName }}.name}"
+		{{- else if .IamInstanceProfile }}
 		iam_instance_profile = "{{ .IamInstanceProfile }}"
 		{{- end }}
 		{{- if .KeyName}}
@@ -140,23 +478,95 @@ func (i *Instances) WriteHCL(w io.Writer) error {
 		{{- if .Monitoring }}
 		monitoring = {{.Monitoring}}
 		{{- end}}
+		{{- if .PlacementGroupExported }}
+		placement_group = "${aws_placement_group.{{ sanitizeName .PlacementGroup }}.id}"
+		{{- else if .PlacementGroup }}
+		placement_group = "{{ .PlacementGroup }}"
+		{{- end}}
+		{{- if .Tenancy }}
+		tenancy = "{{ .Tenancy }}"
+		{{- end }}
 		{{- if .SourceDestCheck }}
 		source_dest_check = {{ .SourceDestCheck }}
     {{- end}}
+    {{- if BoolValue .DisableAPITermination }}
+    disable_api_termination = true
+    {{- end }}
+    {{- if and .InstanceInitiatedShutdownBehavior (ne (StringValue .InstanceInitiatedShutdownBehavior) "stop") }}
+    instance_initiated_shutdown_behavior = "{{ .InstanceInitiatedShutdownBehavior }}"
+    {{- end }}
     {{- if .SubnetID}}
     subnet_id = "{{ .SubnetID }}"
     {{- end}}
-    {{- if .SecurityGroups }}
-    {{- $secgroup := StringValueSlice .SecurityGroups }}
+    {{- if .VpcID }}
+    {{- if .SecurityGroupIDs }}
+    {{- $secgroup := StringValueSlice .SecurityGroupIDs }}
     vpc_security_group_ids = [{{ $secgroup | joinstring "," }}]
     {{- end}}
-    {{if .Tags}}
+    {{- else if .SecurityGroups }}
+    {{- $secgroup := StringValueSlice .SecurityGroups }}
+    security_groups = [{{ $secgroup | joinstring "," }}]
+    {{- end}}
+    {{- with .RootBlockDevice }}
+    root_block_device {
+      {{- if .VolumeSize }}
+      volume_size = {{ Int64Value .VolumeSize }}
+      {{- end }}
+      {{- if .VolumeType }}
+      volume_type = "{{ StringValue .VolumeType }}"
+      {{- end }}
+      {{- if eq (StringValue .VolumeType) "io1" }}
+      iops = {{ Int64Value .Iops }}
+      {{- end }}
+      {{- if .Encrypted }}
+      encrypted = {{ BoolValue .Encrypted }}
+      {{- end }}
+      {{- if .DeleteOnTermination }}
+      delete_on_termination = {{ BoolValue .DeleteOnTermination }}
+      {{- end }}
+    }
+    {{- end }}
+    {{- range .EBSBlockDevices }}
+    ebs_block_device {
+      device_name = "{{ StringValue .DeviceName }}"
+      {{- if .SnapshotID }}
+      snapshot_id = "{{ StringValue .SnapshotID }}"
+      {{- end }}
+      {{- if .VolumeSize }}
+      volume_size = {{ Int64Value .VolumeSize }}
+      {{- end }}
+      {{- if .VolumeType }}
+      volume_type = "{{ StringValue .VolumeType }}"
+      {{- end }}
+      {{- if eq (StringValue .VolumeType) "io1" }}
+      iops = {{ Int64Value .Iops }}
+      {{- end }}
+      {{- if .Encrypted }}
+      encrypted = {{ BoolValue .Encrypted }}
+      {{- end }}
+      {{- if .DeleteOnTermination }}
+      delete_on_termination = {{ BoolValue .DeleteOnTermination }}
+      {{- end }}
+    }
+    {{- end }}
+    {{- if .UserData }}
+    user_data = {{ heredoc .UserData }}
+    {{- else if .UserDataBase64 }}
+    {{- with .UserDataGunzipped }}
+
+    # The user data is gzip compressed, decompressed:
+    {{ commentLines . }}
+    {{- end }}
+    user_data_base64 = "{{ .UserDataBase64 }}"
+    {{- end}}
+    {{- $tags := tags .Tags }}
+    {{- if $tags }}
     tags {
-      {{range $k, $v := .Tags}}
-        "{{ $k }}" = "{{$v}}"
+      {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
       {{- end}}
     }
-    {{end}}
+    {{- end}}
 	}
 		{{- end}}
 	{{- end}}
@@ -165,6 +575,16 @@ func (i *Instances) WriteHCL(w io.Writer) error {
 
 }
 
+// WriteImports writes the terraform import commands of 'Instances'
+func (i *Instances) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_instance.{{ sanitizeName .InstanceID }}_instance {{ .InstanceID }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, i)
+}
+
 //**************** VPC ****************
 type VPC struct {
 	// describe-vpcs
@@ -187,30 +607,34 @@ type VPC struct {
 
 type VPCs []*VPC
 
-func (c *AWSClient) setVPCAttribute(vpc *VPC, classicLink *ec2.DescribeVpcClassicLinkOutput, classicLinkDnsSupport *ec2.DescribeVpcClassicLinkDnsSupportOutput) error {
+func (c *AWSClient) setVPCAttribute(ctx aws.Context, vpc *VPC, classicLink *ec2.DescribeVpcClassicLinkOutput, classicLinkDnsSupport *ec2.DescribeVpcClassicLinkDnsSupportOutput) error {
 	opt := &ec2.DescribeVpcAttributeInput{
 		VpcId: vpc.VPCId,
 	}
 
 	// EnableDnsHostnames
 	opt = opt.SetAttribute("enableDnsHostnames")
-	output, err := c.ec2conn.DescribeVpcAttribute(opt)
+	output, err := c.ec2conn.DescribeVpcAttributeWithContext(ctx, opt)
 	if err != nil {
 		return err
 	}
-	vpc.EnableDnsHostnames = output.EnableDnsHostnames.Value
+	if output.EnableDnsHostnames != nil {
+		vpc.EnableDnsHostnames = output.EnableDnsHostnames.Value
+	}
 
 	// EnableDnsSupport
 	opt = opt.SetAttribute("enableDnsSupport")
-	output, err = c.ec2conn.DescribeVpcAttribute(opt)
+	output, err = c.ec2conn.DescribeVpcAttributeWithContext(ctx, opt)
 	if err != nil {
 		return err
 	}
-	vpc.EnableDnsSupport = output.EnableDnsSupport.Value
+	if output.EnableDnsSupport != nil {
+		vpc.EnableDnsSupport = output.EnableDnsSupport.Value
+	}
 
 	// EnableClassicLink
 	for k, _ := range classicLink.Vpcs {
-		if aws.StringValue(classicLink.Vpcs[k].VpcId) == aws.StringValue(vpc.VPCId) {
+		if classicLink.Vpcs[k] != nil && aws.StringValue(classicLink.Vpcs[k].VpcId) == aws.StringValue(vpc.VPCId) {
 			vpc.EnableClassicLink = classicLink.Vpcs[k].ClassicLinkEnabled
 			break
 		}
@@ -218,7 +642,7 @@ func (c *AWSClient) setVPCAttribute(vpc *VPC, classicLink *ec2.DescribeVpcClassi
 
 	//EnableClassicLinkDnsSupport
 	for _, v := range classicLinkDnsSupport.Vpcs {
-		if aws.StringValue(v.VpcId) == aws.StringValue(vpc.VPCId) {
+		if v != nil && aws.StringValue(v.VpcId) == aws.StringValue(vpc.VPCId) {
 			vpc.EnableClassicLinkDnsSupport = v.ClassicLinkDnsSupported
 			break
 		}
@@ -227,25 +651,61 @@ func (c *AWSClient) setVPCAttribute(vpc *VPC, classicLink *ec2.DescribeVpcClassi
 	return nil
 }
 
-func (c *AWSClient) GetVPCs() (*VPCs, error) {
+// describeVpcClassicLinkDnsSupport returns the pages of
+// ec2:DescribeVpcClassicLinkDnsSupport as a single output
+func (c *AWSClient) describeVpcClassicLinkDnsSupport(ctx aws.Context) (*ec2.DescribeVpcClassicLinkDnsSupportOutput, error) {
+	res := &ec2.DescribeVpcClassicLinkDnsSupportOutput{}
+
+	opt := &ec2.DescribeVpcClassicLinkDnsSupportInput{}
+	for {
+		out, err := c.ec2conn.DescribeVpcClassicLinkDnsSupportWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Vpcs = append(res.Vpcs, out.Vpcs...)
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return res, nil
+}
+
+// DefaultVPCRoutines is the number of VPCs whose attributes are looked up
+// at once when AWSClient.MaxVPCRoutines is not set
+const DefaultVPCRoutines = 5
+
+// GetVPCsWithContext describes the VPCs, looking their attributes up
+// with up to c.MaxVPCRoutines concurrent workers. The first failure cancels
+// the remaining lookups, unless c.ContinueOnError skips the VPCs failing
+func (c *AWSClient) GetVPCsWithContext(ctx aws.Context) (*VPCs, error) {
 	res := VPCs{}
 
-	basicInfo, err := c.ec2conn.DescribeVpcs(&ec2.DescribeVpcsInput{})
+	basicInfo, err := c.ec2conn.DescribeVpcsWithContext(ctx, &ec2.DescribeVpcsInput{Filters: c.ec2TagFilters()})
 	if err != nil {
 		return nil, err
 	}
 
-	classicLink, err := c.ec2conn.DescribeVpcClassicLink(&ec2.DescribeVpcClassicLinkInput{})
+	classicLink, err := c.ec2conn.DescribeVpcClassicLinkWithContext(ctx, &ec2.DescribeVpcClassicLinkInput{})
 	if err != nil {
 		return nil, err
 	}
 
-	classicLinkDnsSupport, err := c.ec2conn.DescribeVpcClassicLinkDnsSupport(&ec2.DescribeVpcClassicLinkDnsSupportInput{})
+	// DescribeVpcs & DescribeVpcClassicLink return every VPC at once,
+	// only the ClassicLink DNS support is paginated
+	classicLinkDnsSupport, err := c.describeVpcClassicLinkDnsSupport(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, v := range basicInfo.Vpcs {
+		if v == nil || !c.wantID(v.VpcId) {
+			continue
+		}
+
 		vpc := VPC{
 			CIDRBlock:       v.CidrBlock,
 			InstanceTenancy: v.InstanceTenancy,
@@ -258,15 +718,61 @@ func (c *AWSClient) GetVPCs() (*VPCs, error) {
 		if len(v.Ipv6CidrBlockAssociationSet) > 0 {
 			vpc.AssignGeneratedIPv6CIDRBlock = aws.Bool(true)
 		}
-		err = c.setVPCAttribute(&vpc, classicLink, classicLinkDnsSupport)
-		if err != nil {
-			return nil, err
-		}
 
 		res = append(res, &vpc)
 	}
 
-	return &res, nil
+	maxRoutines := c.MaxVPCRoutines
+	if maxRoutines < 1 {
+		maxRoutines = DefaultVPCRoutines
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch := make(chan *chanItem, len(res))
+	lock := make(chan struct{}, maxRoutines)
+	for _, vpc := range res {
+		lock <- struct{}{}
+		go func(vpc *VPC) {
+			defer func() { <-lock }()
+
+			// The error is sent before cancelling so that it is received
+			// before the ones caused by the cancellation
+			err := c.setVPCAttribute(ctx, vpc, classicLink, classicLinkDnsSupport)
+			ch <- &chanItem{obj: vpc, err: err}
+			if err != nil && !c.ContinueOnError {
+				cancel()
+			}
+		}(vpc)
+	}
+
+	errs := &MultiError{}
+	fetched := VPCs{}
+	for range res {
+		receiver := <-ch
+		vpc := receiver.obj.(*VPC)
+		if receiver.err != nil {
+			if !c.ContinueOnError || ctx.Err() != nil {
+				return nil, receiver.err
+			}
+			errs.add(vpc.VPCId, receiver.err)
+			continue
+		}
+		fetched = append(fetched, vpc)
+	}
+	res = fetched
+
+	sort.Slice(res, func(i, j int) bool {
+		return aws.StringValue(res[i].VPCId) < aws.StringValue(res[j].VPCId)
+	})
+
+	return &res, errs.errorOrNil()
+}
+
+// GetVPCs calls GetVPCsWithContext with a background context
+func (c *AWSClient) GetVPCs() (*VPCs, error) {
+	return c.GetVPCsWithContext(aws.BackgroundContext())
 }
 
 func (vpcs *VPCs) WriteHCL(w io.Writer) error {
@@ -275,15 +781,17 @@ func (vpcs *VPCs) WriteHCL(w io.Writer) error {
 	tmpl := `
 	{{ if . }}
 		{{- range . }}
-	resource "aws_vpc" "{{ index .Tags "Name" }}" {
+	{{ resourceID "aws_vpc" .VPCId }}
+	resource "aws_vpc" "{{ sanitizeName (index .Tags "Name") .VPCId }}" {
     cidr_block = "{{ .CIDRBlock }}"
     {{- if .InstanceTenancy }}
     instance_tenancy = "{{ .InstanceTenancy}}"
     {{- end}}
-    {{- if .Tags }}
+    {{- $tags := tags .Tags }}
+    {{- if $tags }}
     tags {
-      {{range $k, $v := .Tags}}
-        "{{ $k }}" = "{{$v }}"
+      {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
       {{- end}}
     }
     {{- end }}
@@ -310,6 +818,16 @@ func (vpcs *VPCs) WriteHCL(w io.Writer) error {
 
 }
 
+// WriteImports writes the terraform import commands of 'VPCs'
+func (vpcs *VPCs) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_vpc.{{ sanitizeName (index .Tags "Name") .VPCId }} {{ .VPCId }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, vpcs)
+}
+
 //**************** Subnet ****************
 // https://docs.aws.amazon.com/cli/latest/reference/ec2/describe-subnets.html
 type Subnet struct {
@@ -335,19 +853,23 @@ func (s *Subnet) setSubnet(src *ec2.Subnet) {
 	s.AvailabilityZone = src.AvailabilityZone
 	s.SubnetId = src.SubnetId
 	s.AssignIpv6AddressOnCreation = src.AssignIpv6AddressOnCreation
-	if len(src.Ipv6CidrBlockAssociationSet) > 0 {
+	if len(src.Ipv6CidrBlockAssociationSet) > 0 && src.Ipv6CidrBlockAssociationSet[0] != nil {
 		s.IPv6CIDRBlock = src.Ipv6CidrBlockAssociationSet[0].Ipv6CidrBlock
 	}
 }
 
-func (c *AWSClient) GetSubnets() (*Subnets, error) {
-	data, err := c.ec2conn.DescribeSubnets(&ec2.DescribeSubnetsInput{})
+func (c *AWSClient) GetSubnetsWithContext(ctx aws.Context) (*Subnets, error) {
+	data, err := c.ec2conn.DescribeSubnetsWithContext(ctx, &ec2.DescribeSubnetsInput{Filters: c.ec2TagFilters()})
 	if err != nil {
 		return nil, err
 	}
 
 	var output Subnets
 	for _, v := range data.Subnets {
+		if !c.wantID(v.SubnetId) {
+			continue
+		}
+
 		tmp := &Subnet{}
 		tmp.setSubnet(v)
 		output = append(output, tmp)
@@ -356,13 +878,19 @@ func (c *AWSClient) GetSubnets() (*Subnets, error) {
 	return &output, nil
 }
 
+// GetSubnets calls GetSubnetsWithContext with a background context
+func (c *AWSClient) GetSubnets() (*Subnets, error) {
+	return c.GetSubnetsWithContext(aws.BackgroundContext())
+}
+
 func (s *Subnets) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{}
 
 	tmpl := `
 	{{ if . }}
 		{{- range . }}
-	resource "aws_subnet" "{{ .SubnetId}}" {
+	{{ resourceID "aws_subnet" .SubnetId }}
+	resource "aws_subnet" "{{ sanitizeName .SubnetId }}" {
     vpc_id = "{{ .VPCId}}"
 
     {{- if .AvailabilityZone}}
@@ -385,10 +913,11 @@ func (s *Subnets) WriteHCL(w io.Writer) error {
     assign_ipv6_address_on_creation = {{ .AssignIpv6AddressOnCreation}}
     {{- end}}
 
-    {{- if .Tags}}
+    {{- $tags := tags .Tags }}
+    {{- if $tags }}
     tags {
-      {{- range $k, $v := .Tags}}
-      "{{ $k }}" = "{{ $v }}"
+      {{- range $k, $v := $tags }}
+      {{ hclString $k }} = {{ hclString $v }}
       {{- end}}
     }
     {{- end}}
@@ -400,6 +929,16 @@ func (s *Subnets) WriteHCL(w io.Writer) error {
 	return renderHCL(w, tmpl, funcMap, s)
 }
 
+// WriteImports writes the terraform import commands of 'Subnets'
+func (s *Subnets) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_subnet.{{ sanitizeName .SubnetId }} {{ .SubnetId }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, s)
+}
+
 //**************** Security Group ****************
 type SecurityGroup struct {
 	Name        *string
@@ -432,60 +971,88 @@ func (sg *SecurityGroup) setSecurityGroup(src *ec2.SecurityGroup, AccountId *str
 	sg.VPCId = src.VpcId
 
 	for _, v := range src.IpPermissions {
+		if v == nil {
+			continue
+		}
 		var tmp SecurityGroupRule
 		tmp.setRule(v, AccountId)
 		sg.Ingresses = append(sg.Ingresses, &tmp)
 	}
 
 	for _, v := range src.IpPermissionsEgress {
+		if v == nil {
+			continue
+		}
 		var tmp SecurityGroupRule
 		tmp.setRule(v, AccountId)
 		sg.Egresses = append(sg.Egresses, &tmp)
 	}
 }
 
+// setRule copies an ec2.IpPermission. Groups owned by another account than
+// AccountId (e.g. groups of a peered VPC) are referenced as
+// "<account id>/<group id>", the form Terraform expects for them
 func (r *SecurityGroupRule) setRule(src *ec2.IpPermission, AccountId *string) {
 	r.FromPort = src.FromPort
 	r.ToPort = src.ToPort
 	r.IpProtocol = src.IpProtocol
 	for _, v := range src.PrefixListIds {
+		if v == nil {
+			continue
+		}
 		r.PrefixListIds = append(r.PrefixListIds, v.PrefixListId)
 	}
 
 	for _, v := range src.IpRanges {
+		if v == nil {
+			continue
+		}
 		r.CIDRBlocks = append(r.CIDRBlocks, v.CidrIp)
 	}
 
 	for _, v := range src.Ipv6Ranges {
+		if v == nil {
+			continue
+		}
 		r.IPv6CIDRBlock = append(r.IPv6CIDRBlock, v.CidrIpv6)
 	}
 
 	for _, v := range src.UserIdGroupPairs {
-		if strings.Compare(*v.UserId, *AccountId) == 0 {
+		if v == nil {
+			continue
+		}
+		userId := safeString(v.UserId, "")
+		if userId == "" || strings.Compare(userId, safeString(AccountId, "")) == 0 {
 			r.SourceSecurityGroups = append(r.SourceSecurityGroups, v.GroupId)
 		} else {
-			srcSecGroup := fmt.Sprintf("%s/%s", *v.UserId, *v.GroupId)
+			srcSecGroup := fmt.Sprintf("%s/%s", userId, safeString(v.GroupId, ""))
 			r.SourceSecurityGroups = append(r.SourceSecurityGroups, &srcSecGroup)
 		}
 	}
 }
 
-func (c *AWSClient) GetSecurityGroups(AccountId *string) (*SecurityGroups, error) {
-	opt := ec2.DescribeSecurityGroupsInput{}
+func (c *AWSClient) GetSecurityGroupsWithContext(ctx aws.Context, AccountId *string) (*SecurityGroups, error) {
+	opt := ec2.DescribeSecurityGroupsInput{Filters: c.ec2TagFilters()}
 	var output SecurityGroups
 
 	for {
-		data, err := c.ec2conn.DescribeSecurityGroups(&opt)
+		data, err := c.ec2conn.DescribeSecurityGroupsWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, v := range data.SecurityGroups {
+			if !c.wantID(v.GroupId) {
+				continue
+			}
+
 			tmp := SecurityGroup{}
 			tmp.setSecurityGroup(v, AccountId)
 			output = append([]*SecurityGroup(output), &tmp)
 		}
 
+		c.progress("aws_security_group", len(output))
+
 		if data.NextToken != nil {
 			opt.NextToken = data.NextToken
 		} else {
@@ -496,6 +1063,11 @@ func (c *AWSClient) GetSecurityGroups(AccountId *string) (*SecurityGroups, error
 	return &output, nil
 }
 
+// GetSecurityGroups calls GetSecurityGroupsWithContext with a background context
+func (c *AWSClient) GetSecurityGroups(AccountId *string) (*SecurityGroups, error) {
+	return c.GetSecurityGroupsWithContext(aws.BackgroundContext(), AccountId)
+}
+
 func (sg *SecurityGroups) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"makeTerraformResourceName": makeTerraformResourceName,
@@ -506,21 +1078,23 @@ func (sg *SecurityGroups) WriteHCL(w io.Writer) error {
 	tmpl := `
 	{{ if . }}
 		{{- range . }}
-	resource "aws_security_group" "{{ makeTerraformResourceName .Name }}" {
+	{{ resourceID "aws_security_group" .GroupId }}
+	resource "aws_security_group" "{{ sanitizeName (makeTerraformResourceName .Name) .GroupId }}" {
     name = "{{ .Name }}"
 
     {{- if .Description }}
-    description = "{{ .Description }}"
+    description = {{ hclString .Description }}
     {{- end}}
 
     {{- if .VPCId}}
     vpc_id = "{{ .VPCId }}"
     {{- end}}
 
-    {{- if .Tags }}
+    {{- $tags := tags .Tags }}
+    {{- if $tags }}
     tags {
-      {{- range $k, $v := .Tags }}
-      "{{ $k }}" = "{{ $v }}"
+      {{- range $k, $v := $tags }}
+      {{ hclString $k }} = {{ hclString $v }}
       {{- end }}
     }
     {{- end }}
@@ -610,6 +1184,18 @@ func (sg *SecurityGroups) WriteHCL(w io.Writer) error {
 	return renderHCL(w, tmpl, funcMap, sg)
 }
 
+// WriteImports writes the terraform import commands of 'SecurityGroups'
+func (sg *SecurityGroups) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformResourceName": makeTerraformResourceName,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_security_group.{{ sanitizeName (makeTerraformResourceName .Name) .GroupId }} {{ .GroupId }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, sg)
+}
+
 //**************** BEGIN Route Table ****************
 
 type Route struct {
@@ -648,6 +1234,9 @@ type RouteTable struct {
 
 func (r *RouteTable) setRoutes(src []*ec2.Route) *RouteTable {
 	for _, v := range src {
+		if v == nil {
+			continue
+		}
 		tmp := Route{}
 		r.Routes = append(r.Routes, tmp.setRoute(v))
 	}
@@ -657,6 +1246,9 @@ func (r *RouteTable) setRoutes(src []*ec2.Route) *RouteTable {
 
 func (r *RouteTable) setPropagatingVgws(src []*ec2.PropagatingVgw) *RouteTable {
 	for _, prgw := range src {
+		if prgw == nil {
+			continue
+		}
 		r.PropagatingVgws = append(r.PropagatingVgws, prgw.GatewayId)
 	}
 
@@ -665,6 +1257,9 @@ func (r *RouteTable) setPropagatingVgws(src []*ec2.PropagatingVgw) *RouteTable {
 
 func (r *RouteTable) setTags(src []*ec2.Tag) *RouteTable {
 	for _, v := range src {
+		if v == nil {
+			continue
+		}
 		tmp := ResourceTag{
 			Key:   v.Key,
 			Value: v.Value,
@@ -688,20 +1283,26 @@ func (r *RouteTable) setRouteTable(src *ec2.RouteTable) *RouteTable {
 
 type RouteTables []*RouteTable
 
-func (c *AWSClient) GetRouteTables() (*RouteTables, error) {
-	opt := ec2.DescribeRouteTablesInput{}
+func (c *AWSClient) GetRouteTablesWithContext(ctx aws.Context) (*RouteTables, error) {
+	opt := ec2.DescribeRouteTablesInput{Filters: c.ec2TagFilters()}
 	res := RouteTables{}
 	for {
-		output, err := c.ec2conn.DescribeRouteTables(&opt)
+		output, err := c.ec2conn.DescribeRouteTablesWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, rtb := range output.RouteTables {
+			if !c.wantID(rtb.RouteTableId) {
+				continue
+			}
+
 			rtbTemp := &RouteTable{}
 			res = append(res, rtbTemp.setRouteTable(rtb))
 		}
 
+		c.progress("aws_route_table", len(res))
+
 		if output.NextToken == nil {
 			break
 		} else {
@@ -712,6 +1313,11 @@ func (c *AWSClient) GetRouteTables() (*RouteTables, error) {
 	return &res, nil
 }
 
+// GetRouteTables calls GetRouteTablesWithContext with a background context
+func (c *AWSClient) GetRouteTables() (*RouteTables, error) {
+	return c.GetRouteTablesWithContext(aws.BackgroundContext())
+}
+
 func (rtb *RouteTables) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"makeTerraformList": makeTerraformList,
@@ -720,4 +1326,1493 @@ func (rtb *RouteTables) WriteHCL(w io.Writer) error {
 	return renderHCL(w, EC2_ROUTE_TABLE, funcMap, rtb)
 }
 
+// WriteImports writes the terraform import commands of 'RouteTables'
+func (rtb *RouteTables) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_route_table.{{ sanitizeName .Id }} {{ .Id }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, rtb)
+}
+
 //**************** END Route Table ****************
+
+//**************** BEGIN Network ACL ****************
+
+type NetworkACLEntry struct {
+	RuleNumber    *int64
+	Protocol      *string
+	RuleAction    *string
+	CIDRBlock     *string
+	IPv6CIDRBlock *string
+	FromPort      *int64
+	ToPort        *int64
+	ICMPType      *int64
+	ICMPCode      *int64
+}
+
+type NetworkACLAssociation struct {
+	Id       *string
+	SubnetId *string
+}
+
+type NetworkACL struct {
+	Id           *string
+	VpcId        *string
+	IsDefault    *bool
+	Tags         *Tags
+	Ingresses    []*NetworkACLEntry
+	Egresses     []*NetworkACLEntry
+	Associations []*NetworkACLAssociation
+}
+
+type NetworkACLs []*NetworkACL
+
+func (e *NetworkACLEntry) setEntry(src *ec2.NetworkAclEntry) {
+	e.RuleNumber = src.RuleNumber
+	e.Protocol = src.Protocol
+	e.RuleAction = src.RuleAction
+	e.CIDRBlock = src.CidrBlock
+	e.IPv6CIDRBlock = src.Ipv6CidrBlock
+
+	if src.PortRange != nil {
+		e.FromPort = src.PortRange.From
+		e.ToPort = src.PortRange.To
+	}
+
+	if src.IcmpTypeCode != nil {
+		e.ICMPType = src.IcmpTypeCode.Type
+		e.ICMPCode = src.IcmpTypeCode.Code
+	}
+}
+
+func (n *NetworkACL) setNetworkACL(src *ec2.NetworkAcl) {
+	n.Id = src.NetworkAclId
+	n.VpcId = src.VpcId
+	n.IsDefault = src.IsDefault
+	n.Tags = &Tags{}
+	n.Tags.setTags(src.Tags)
+
+	for _, v := range src.Entries {
+		if v == nil {
+			continue
+		}
+
+		// The default rule (32767) of every ACL is managed implicitly by Terraform
+		if aws.Int64Value(v.RuleNumber) == 32767 {
+			continue
+		}
+
+		tmp := &NetworkACLEntry{}
+		tmp.setEntry(v)
+		if safeBool(v.Egress, false) {
+			n.Egresses = append(n.Egresses, tmp)
+		} else {
+			n.Ingresses = append(n.Ingresses, tmp)
+		}
+	}
+
+	for _, v := range src.Associations {
+		if v == nil {
+			continue
+		}
+		n.Associations = append(n.Associations, &NetworkACLAssociation{
+			Id:       v.NetworkAclAssociationId,
+			SubnetId: v.SubnetId,
+		})
+	}
+}
+
+// GetNetworkACLs returns every Network ACL in the region.
+// DescribeNetworkAcls is not paginated, all ACLs come back in a single call
+func (c *AWSClient) GetNetworkACLsWithContext(ctx aws.Context) (*NetworkACLs, error) {
+	output, err := c.ec2conn.DescribeNetworkAclsWithContext(ctx, &ec2.DescribeNetworkAclsInput{Filters: c.ec2TagFilters()})
+	if err != nil {
+		return nil, err
+	}
+
+	res := NetworkACLs{}
+	for _, v := range output.NetworkAcls {
+		if !c.wantID(v.NetworkAclId) {
+			continue
+		}
+
+		tmp := &NetworkACL{}
+		tmp.setNetworkACL(v)
+		res = append(res, tmp)
+	}
+
+	return &res, nil
+}
+
+// GetNetworkACLs calls GetNetworkACLsWithContext with a background context
+func (c *AWSClient) GetNetworkACLs() (*NetworkACLs, error) {
+	return c.GetNetworkACLsWithContext(aws.BackgroundContext())
+}
+
+func (n *NetworkACLs) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	return renderHCL(w, EC2_NETWORK_ACL, funcMap, n)
+}
+
+// WriteImports writes the terraform import commands of 'NetworkACLs'
+func (n *NetworkACLs) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_network_acl.{{ sanitizeName .Id }} {{ .Id }}
+{{ range .Associations -}}
+terraform import aws_network_acl_association.{{ sanitizeName .Id }} {{ .Id }}
+{{ end }}{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, n)
+}
+
+//**************** END Network ACL ****************
+
+//**************** BEGIN VPC Peering Connection ****************
+
+type VPCPeering struct {
+	Id          *string
+	VpcId       *string
+	OwnerId     *string
+	Region      *string
+	PeerVpcId   *string
+	PeerOwnerId *string
+	PeerRegion  *string
+	AutoAccept  *bool
+	Tags        *Tags
+}
+
+type VPCPeerings []*VPCPeering
+
+func (p *VPCPeering) setVPCPeering(src *ec2.VpcPeeringConnection) {
+	p.Id = src.VpcPeeringConnectionId
+	p.Tags = &Tags{}
+	p.Tags.setTags(src.Tags)
+
+	if src.RequesterVpcInfo != nil {
+		p.VpcId = src.RequesterVpcInfo.VpcId
+		p.OwnerId = src.RequesterVpcInfo.OwnerId
+		p.Region = src.RequesterVpcInfo.Region
+	}
+
+	if src.AccepterVpcInfo != nil {
+		p.PeerVpcId = src.AccepterVpcInfo.VpcId
+
+		// peer_owner_id & peer_region are only meaningful when they differ from the requester
+		if safeString(src.AccepterVpcInfo.OwnerId, "") != safeString(p.OwnerId, "") {
+			p.PeerOwnerId = src.AccepterVpcInfo.OwnerId
+		}
+
+		if safeString(src.AccepterVpcInfo.Region, "") != safeString(p.Region, "") {
+			p.PeerRegion = src.AccepterVpcInfo.Region
+		}
+	}
+
+	// Same account & same region peerings can be accepted by Terraform itself
+	if p.PeerOwnerId == nil && p.PeerRegion == nil {
+		p.AutoAccept = aws.Bool(true)
+	}
+}
+
+// GetVPCPeerings returns VPC Peering Connections, ignoring the ones
+// which are no longer usable (deleted, rejected, failed or expired)
+func (c *AWSClient) GetVPCPeeringsWithContext(ctx aws.Context) (*VPCPeerings, error) {
+	output, err := c.ec2conn.DescribeVpcPeeringConnectionsWithContext(ctx, &ec2.DescribeVpcPeeringConnectionsInput{Filters: c.ec2TagFilters()})
+	if err != nil {
+		return nil, err
+	}
+
+	res := VPCPeerings{}
+	for _, v := range output.VpcPeeringConnections {
+		if v == nil || !c.wantID(v.VpcPeeringConnectionId) {
+			continue
+		}
+
+		if v.Status != nil {
+			switch safeString(v.Status.Code, "") {
+			case ec2.VpcPeeringConnectionStateReasonCodeDeleted,
+				ec2.VpcPeeringConnectionStateReasonCodeRejected,
+				ec2.VpcPeeringConnectionStateReasonCodeFailed,
+				ec2.VpcPeeringConnectionStateReasonCodeExpired:
+				continue
+			}
+		}
+
+		tmp := &VPCPeering{}
+		tmp.setVPCPeering(v)
+		res = append(res, tmp)
+	}
+
+	return &res, nil
+}
+
+// GetVPCPeerings calls GetVPCPeeringsWithContext with a background context
+func (c *AWSClient) GetVPCPeerings() (*VPCPeerings, error) {
+	return c.GetVPCPeeringsWithContext(aws.BackgroundContext())
+}
+
+func (p *VPCPeerings) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	return renderHCL(w, EC2_VPC_PEERING_CONNECTION, funcMap, p)
+}
+
+// WriteImports writes the terraform import commands of 'VPCPeerings'
+func (p *VPCPeerings) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_vpc_peering_connection.{{ sanitizeName .Id }} {{ .Id }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, p)
+}
+
+//**************** END VPC Peering Connection ****************
+
+//**************** BEGIN VPC Endpoint ****************
+
+type VPCEndpoint struct {
+	Id                *string
+	VpcId             *string
+	ServiceName       *string
+	VpcEndpointType   *string
+	RouteTableIds     []*string
+	SubnetIds         []*string
+	SecurityGroupIds  []*string
+	PrivateDnsEnabled *bool
+	Policy            *string
+	CreationTime      *time.Time
+	Tags              *Tags
+}
+
+type VPCEndpoints []*VPCEndpoint
+
+// getTags gets the tags of the endpoint, DescribeVpcEndpoints does not
+// return them
+func (e *VPCEndpoint) getTags(ctx aws.Context, c *AWSClient) error {
+	e.Tags = &Tags{}
+	opt := &ec2.DescribeTagsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("resource-id"), Values: []*string{e.Id}}},
+	}
+	for {
+		output, err := c.ec2conn.DescribeTagsWithContext(ctx, opt)
+		if err != nil {
+			return err
+		}
+		for _, v := range output.Tags {
+			if v == nil || v.Key == nil {
+				continue
+			}
+			(*e.Tags)[*v.Key] = v.Value
+		}
+
+		if aws.StringValue(output.NextToken) == "" {
+			break
+		}
+		opt.NextToken = output.NextToken
+	}
+
+	return nil
+}
+
+func (e *VPCEndpoint) setVPCEndpoint(src *ec2.VpcEndpoint) {
+	e.Id = src.VpcEndpointId
+	e.VpcId = src.VpcId
+	e.ServiceName = src.ServiceName
+	e.VpcEndpointType = src.VpcEndpointType
+	e.RouteTableIds = src.RouteTableIds
+	e.SubnetIds = src.SubnetIds
+	e.PrivateDnsEnabled = src.PrivateDnsEnabled
+	e.Policy = src.PolicyDocument
+	e.CreationTime = src.CreationTimestamp
+
+	for _, v := range src.Groups {
+		if v == nil {
+			continue
+		}
+		e.SecurityGroupIds = append(e.SecurityGroupIds, v.GroupId)
+	}
+}
+
+func (c *AWSClient) GetVPCEndpointsWithContext(ctx aws.Context) (*VPCEndpoints, error) {
+	opt := ec2.DescribeVpcEndpointsInput{}
+	res := VPCEndpoints{}
+	for {
+		output, err := c.ec2conn.DescribeVpcEndpointsWithContext(ctx, &opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range output.VpcEndpoints {
+			if v == nil || !c.wantID(v.VpcEndpointId) || !c.wantCreated(v.CreationTimestamp) {
+				continue
+			}
+
+			// Ignore endpoints which are gone or going away
+			// The API returns the state in lower case, unlike the ec2.State* enum
+			switch strings.ToLower(safeString(v.State, "")) {
+			case "deleting", "deleted", "failed", "rejected", "expired":
+				continue
+			}
+
+			tmp := &VPCEndpoint{}
+			tmp.setVPCEndpoint(v)
+			if err := tmp.getTags(ctx, c); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_vpc_endpoint", len(res))
+
+		if aws.StringValue(output.NextToken) != "" {
+			opt.NextToken = output.NextToken
+		} else {
+			break
+		}
+	}
+
+	return &res, nil
+}
+
+// GetVPCEndpoints calls GetVPCEndpointsWithContext with a background context
+func (c *AWSClient) GetVPCEndpoints() (*VPCEndpoints, error) {
+	return c.GetVPCEndpointsWithContext(aws.BackgroundContext())
+}
+
+func (e *VPCEndpoints) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformList": makeTerraformList,
+	}
+
+	return renderHCL(w, EC2_VPC_ENDPOINT, funcMap, e)
+}
+
+// WriteImports writes the terraform import commands of 'VPCEndpoints'
+func (e *VPCEndpoints) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_vpc_endpoint.{{ sanitizeName .Id }} {{ .Id }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, e)
+}
+
+//**************** END VPC Endpoint ****************
+
+//**************** BEGIN DHCP Options Set ****************
+
+type DHCPOptions struct {
+	Id                 *string
+	DomainName         *string
+	DomainNameServers  []*string
+	NTPServers         []*string
+	NetbiosNameServers []*string
+	NetbiosNodeType    *string
+	Tags               *Tags
+
+	// VPCs associated with the options set
+	VpcIds []*string
+}
+
+type DHCPOptionsList []*DHCPOptions
+
+func (d *DHCPOptions) setDHCPOptions(src *ec2.DhcpOptions) {
+	d.Id = src.DhcpOptionsId
+	d.Tags = &Tags{}
+	d.Tags.setTags(src.Tags)
+
+	for _, cfg := range src.DhcpConfigurations {
+		if cfg == nil {
+			continue
+		}
+
+		var values []*string
+		for _, v := range cfg.Values {
+			if v != nil && v.Value != nil {
+				values = append(values, v.Value)
+			}
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+
+		switch safeString(cfg.Key, "") {
+		case "domain-name":
+			d.DomainName = values[0]
+		case "domain-name-servers":
+			d.DomainNameServers = values
+		case "ntp-servers":
+			d.NTPServers = values
+		case "netbios-name-servers":
+			d.NetbiosNameServers = values
+		case "netbios-node-type":
+			d.NetbiosNodeType = values[0]
+		}
+	}
+}
+
+// GetDHCPOptions returns the DHCP Options Sets together with
+// the VPCs using each of them
+func (c *AWSClient) GetDHCPOptionsWithContext(ctx aws.Context) (*DHCPOptionsList, error) {
+	output, err := c.ec2conn.DescribeDhcpOptionsWithContext(ctx, &ec2.DescribeDhcpOptionsInput{Filters: c.ec2TagFilters()})
+	if err != nil {
+		return nil, err
+	}
+
+	vpcs, err := c.ec2conn.DescribeVpcsWithContext(ctx, &ec2.DescribeVpcsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := DHCPOptionsList{}
+	for _, v := range output.DhcpOptions {
+		if v == nil || !c.wantID(v.DhcpOptionsId) {
+			continue
+		}
+
+		tmp := &DHCPOptions{}
+		tmp.setDHCPOptions(v)
+		for _, vpc := range vpcs.Vpcs {
+			if vpc != nil && aws.StringValue(vpc.DhcpOptionsId) == aws.StringValue(tmp.Id) {
+				tmp.VpcIds = append(tmp.VpcIds, vpc.VpcId)
+			}
+		}
+
+		res = append(res, tmp)
+	}
+
+	return &res, nil
+}
+
+// GetDHCPOptions calls GetDHCPOptionsWithContext with a background context
+func (c *AWSClient) GetDHCPOptions() (*DHCPOptionsList, error) {
+	return c.GetDHCPOptionsWithContext(aws.BackgroundContext())
+}
+
+func (d *DHCPOptionsList) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformList": makeTerraformList,
+		"StringValue":       aws.StringValue,
+	}
+
+	return renderHCL(w, EC2_DHCP_OPTIONS, funcMap, d)
+}
+
+// WriteImports writes the terraform import commands of 'DHCPOptionsList'
+func (d *DHCPOptionsList) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"StringValue": aws.StringValue,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_vpc_dhcp_options.{{ sanitizeName .Id }} {{ .Id }}
+{{ $dhcpId := .Id }}{{ range .VpcIds -}}
+terraform import aws_vpc_dhcp_options_association.{{ sanitizeName (printf "%s_%s" (StringValue $dhcpId) (StringValue .)) }} {{ . }}
+{{ end }}{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, d)
+}
+
+//**************** END DHCP Options Set ****************
+
+//**************** BEGIN Elastic Network Interface ****************
+
+type ENIAttachment struct {
+	InstanceId  *string
+	DeviceIndex *int64
+}
+
+type ENI struct {
+	Id              *string
+	SubnetId        *string
+	Description     *string
+	PrivateIps      []*string
+	SecurityGroups  []*string
+	SourceDestCheck *bool
+	Attachment      *ENIAttachment
+	Tags            *Tags
+}
+
+type ENIs []*ENI
+
+// isUnmanageableENI reports whether the ENI is owned by AWS (NAT Gateway, Lambda, ELB, ...)
+// or is the primary interface of an instance, either way Terraform can't manage it on its own
+func isUnmanageableENI(src *ec2.NetworkInterface) bool {
+	if safeBool(src.RequesterManaged, false) {
+		return true
+	}
+
+	if safeString(src.InterfaceType, ec2.NetworkInterfaceTypeInterface) != ec2.NetworkInterfaceTypeInterface {
+		return true
+	}
+
+	if src.Attachment != nil && src.Attachment.InstanceId != nil && safeInt64(src.Attachment.DeviceIndex, -1) == 0 {
+		return true
+	}
+
+	return false
+}
+
+func (e *ENI) setENI(src *ec2.NetworkInterface) {
+	e.Id = src.NetworkInterfaceId
+	e.SubnetId = src.SubnetId
+	e.Description = src.Description
+	e.SourceDestCheck = src.SourceDestCheck
+	e.Tags = &Tags{}
+	e.Tags.setTags(src.TagSet)
+
+	for _, v := range src.PrivateIpAddresses {
+		if v != nil && v.PrivateIpAddress != nil {
+			e.PrivateIps = append(e.PrivateIps, v.PrivateIpAddress)
+		}
+	}
+
+	for _, v := range src.Groups {
+		if v != nil && v.GroupId != nil {
+			e.SecurityGroups = append(e.SecurityGroups, v.GroupId)
+		}
+	}
+
+	if src.Attachment != nil && src.Attachment.InstanceId != nil {
+		e.Attachment = &ENIAttachment{
+			InstanceId:  src.Attachment.InstanceId,
+			DeviceIndex: src.Attachment.DeviceIndex,
+		}
+	}
+}
+
+func (c *AWSClient) GetENIsWithContext(ctx aws.Context) (*ENIs, error) {
+	opt := ec2.DescribeNetworkInterfacesInput{Filters: c.ec2TagFilters()}
+	res := ENIs{}
+	for {
+		output, err := c.ec2conn.DescribeNetworkInterfacesWithContext(ctx, &opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range output.NetworkInterfaces {
+			if v == nil || isUnmanageableENI(v) || !c.wantID(v.NetworkInterfaceId) {
+				continue
+			}
+
+			tmp := &ENI{}
+			tmp.setENI(v)
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_network_interface", len(res))
+
+		if output.NextToken == nil {
+			break
+		} else {
+			opt.NextToken = output.NextToken
+		}
+	}
+
+	return &res, nil
+}
+
+// GetENIs calls GetENIsWithContext with a background context
+func (c *AWSClient) GetENIs() (*ENIs, error) {
+	return c.GetENIsWithContext(aws.BackgroundContext())
+}
+
+func (e *ENIs) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformList": makeTerraformList,
+	}
+
+	return renderHCL(w, EC2_NETWORK_INTERFACE, funcMap, e)
+}
+
+// WriteImports writes the terraform import commands of 'ENIs'
+func (e *ENIs) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_network_interface.{{ sanitizeName .Id }} {{ .Id }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, e)
+}
+
+//**************** END Elastic Network Interface ****************
+
+//**************** BEGIN Placement Group ****************
+
+type PlacementGroup struct {
+	Name     *string
+	Strategy *string
+
+	// partition strategy only
+	PartitionCount *int64
+}
+
+type PlacementGroups []*PlacementGroup
+
+// GetPlacementGroupsWithContext returns the placement groups which are
+// not being deleted
+func (c *AWSClient) GetPlacementGroupsWithContext(ctx aws.Context) (*PlacementGroups, error) {
+	// Placement groups carry no tags, TagFilter excludes all of them
+	if c.untaggable() {
+		return &PlacementGroups{}, nil
+	}
+
+	output, err := c.ec2conn.DescribePlacementGroupsWithContext(ctx, &ec2.DescribePlacementGroupsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := PlacementGroups{}
+	for _, v := range output.PlacementGroups {
+		if v == nil || !c.wantID(v.GroupName) {
+			continue
+		}
+		if state := aws.StringValue(v.State); state == ec2.PlacementGroupStateDeleting || state == ec2.PlacementGroupStateDeleted {
+			continue
+		}
+
+		tmp := &PlacementGroup{
+			Name:     v.GroupName,
+			Strategy: v.Strategy,
+		}
+		if aws.StringValue(v.Strategy) == ec2.PlacementStrategyPartition {
+			tmp.PartitionCount = v.PartitionCount
+		}
+
+		res = append(res, tmp)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return aws.StringValue(res[i].Name) < aws.StringValue(res[j].Name)
+	})
+
+	return &res, nil
+}
+
+// GetPlacementGroups calls GetPlacementGroupsWithContext with a background context
+func (c *AWSClient) GetPlacementGroups() (*PlacementGroups, error) {
+	return c.GetPlacementGroupsWithContext(aws.BackgroundContext())
+}
+
+func (p *PlacementGroups) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_placement_group" .Name }}
+    resource "aws_placement_group" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      strategy = "{{ .Strategy }}"
+      {{- if .PartitionCount }}
+      partition_count = {{ .PartitionCount }}
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, p)
+}
+
+// WriteImports writes the terraform import commands of 'PlacementGroups'
+func (p *PlacementGroups) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_placement_group.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, p)
+}
+
+//**************** END Placement Group ****************
+
+//**************** BEGIN Spot Instance Request ****************
+
+type SpotRequest struct {
+	ID                           *string
+	SpotPrice                    *string
+	SpotType                     *string
+	BlockDurationMinutes         *int64
+	InstanceInterruptionBehavior *string
+	LaunchGroup                  *string
+	CreationTime                 *time.Time
+	Tags                         *Tags
+
+	// Launch specification
+	ImageID            *string
+	InstanceType       *string
+	KeyName            *string
+	SubnetID           *string
+	AvailabilityZone   *string
+	IamInstanceProfile *string
+	EbsOptimized       *bool
+	Monitoring         *bool
+	SecurityGroups     []*string
+	SecurityGroupIDs   []*string
+	UserDataBase64     *string
+}
+
+type SpotRequests []*SpotRequest
+
+func (s *SpotRequest) set(src *ec2.SpotInstanceRequest) {
+	s.ID = src.SpotInstanceRequestId
+	s.SpotPrice = src.SpotPrice
+	s.SpotType = src.Type
+	s.BlockDurationMinutes = src.BlockDurationMinutes
+	s.InstanceInterruptionBehavior = src.InstanceInterruptionBehavior
+	s.LaunchGroup = src.LaunchGroup
+	s.CreationTime = src.CreateTime
+	s.Tags = &Tags{}
+	s.Tags.setTags(src.Tags)
+
+	spec := src.LaunchSpecification
+	if spec == nil {
+		return
+	}
+
+	s.ImageID = spec.ImageId
+	s.InstanceType = spec.InstanceType
+	s.KeyName = spec.KeyName
+	s.SubnetID = spec.SubnetId
+	s.EbsOptimized = spec.EbsOptimized
+	if len(aws.StringValue(spec.UserData)) > 0 {
+		s.UserDataBase64 = spec.UserData
+	}
+	if spec.Placement != nil {
+		s.AvailabilityZone = spec.Placement.AvailabilityZone
+	}
+	if spec.Monitoring != nil && aws.BoolValue(spec.Monitoring.Enabled) {
+		s.Monitoring = aws.Bool(true)
+	}
+	if p := spec.IamInstanceProfile; p != nil {
+		if p.Name != nil {
+			s.IamInstanceProfile = p.Name
+		} else if p.Arn != nil {
+			s.IamInstanceProfile = aws.String(arnName(p.Arn))
+		}
+	}
+
+	// Names are only used by EC2-Classic requests, VPC ones need IDs
+	for _, sg := range spec.SecurityGroups {
+		if sg == nil {
+			continue
+		}
+		s.SecurityGroups = append(s.SecurityGroups, sg.GroupName)
+		s.SecurityGroupIDs = append(s.SecurityGroupIDs, sg.GroupId)
+	}
+	sortStrings(s.SecurityGroups)
+	sortStrings(s.SecurityGroupIDs)
+}
+
+// GetSpotRequestsWithContext returns the spot instance requests which
+// are neither cancelled nor closed
+func (c *AWSClient) GetSpotRequestsWithContext(ctx aws.Context) (*SpotRequests, error) {
+	res := SpotRequests{}
+
+	opt := &ec2.DescribeSpotInstanceRequestsInput{Filters: c.ec2TagFilters()}
+	for {
+		out, err := c.ec2conn.DescribeSpotInstanceRequestsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.SpotInstanceRequests {
+			if v == nil || !c.wantID(v.SpotInstanceRequestId, v.InstanceId) || !c.wantCreated(v.CreateTime) {
+				continue
+			}
+			if state := aws.StringValue(v.State); state == ec2.SpotInstanceStateCancelled || state == ec2.SpotInstanceStateClosed {
+				continue
+			}
+
+			tmp := &SpotRequest{}
+			tmp.set(v)
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_spot_instance_request", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetSpotRequests calls GetSpotRequestsWithContext with a background context
+func (c *AWSClient) GetSpotRequests() (*SpotRequests, error) {
+	return c.GetSpotRequestsWithContext(aws.BackgroundContext())
+}
+
+func (s *SpotRequests) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+		"BoolValue":        aws.BoolValue,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_spot_instance_request" .ID }}
+    resource "aws_spot_instance_request" "{{ sanitizeName .ID }}" {
+      {{- if .SpotPrice }}
+      spot_price = "{{ .SpotPrice }}"
+      {{- end }}
+      spot_type = "{{ .SpotType }}"
+      {{- if .BlockDurationMinutes }}
+      block_duration_minutes = {{ .BlockDurationMinutes }}
+      {{- end }}
+      {{- if .InstanceInterruptionBehavior }}
+      instance_interruption_behaviour = "{{ .InstanceInterruptionBehavior }}"
+      {{- end }}
+      {{- if .LaunchGroup }}
+      launch_group = "{{ .LaunchGroup }}"
+      {{- end }}
+      ami = "{{ .ImageID }}"
+      instance_type = "{{ .InstanceType }}"
+      {{- if .KeyName }}
+      key_name = "{{ .KeyName }}"
+      {{- end }}
+      {{- if .AvailabilityZone }}
+      availability_zone = "{{ .AvailabilityZone }}"
+      {{- end }}
+      {{- if .SubnetID }}
+      subnet_id = "{{ .SubnetID }}"
+      {{- if .SecurityGroupIDs }}
+      vpc_security_group_ids = [{{ StringValueSlice .SecurityGroupIDs | joinstring "," }}]
+      {{- end }}
+      {{- else if .SecurityGroups }}
+      security_groups = [{{ StringValueSlice .SecurityGroups | joinstring "," }}]
+      {{- end }}
+      {{- if .IamInstanceProfile }}
+      iam_instance_profile = "{{ .IamInstanceProfile }}"
+      {{- end }}
+      {{- if BoolValue .EbsOptimized }}
+      ebs_optimized = true
+      {{- end }}
+      {{- if BoolValue .Monitoring }}
+      monitoring = true
+      {{- end }}
+      {{- if .UserDataBase64 }}
+      user_data_base64 = "{{ .UserDataBase64 }}"
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, s)
+}
+
+// WriteImports writes a note for every 'SpotRequests', which Terraform
+// can not import
+func (s *SpotRequests) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+# aws_spot_instance_request.{{ sanitizeName .ID }} ({{ .ID }}) can not be imported
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, s)
+}
+
+//**************** END Spot Instance Request ****************
+
+//**************** BEGIN Transit Gateway ****************
+
+type TransitGateway struct {
+	ID                           *string
+	ARN                          *string
+	Description                  *string
+	AmazonSideASN                *int64
+	AutoAcceptSharedAttachments  *string
+	DefaultRouteTableAssociation *string
+	DefaultRouteTablePropagation *string
+	DNSSupport                   *string
+	VPNECMPSupport               *string
+	CreationTime                 *time.Time
+	Tags                         *Tags
+}
+
+type TransitGateways []*TransitGateway
+
+func (t *TransitGateway) set(src *ec2.TransitGateway) {
+	t.ID = src.TransitGatewayId
+	t.ARN = src.TransitGatewayArn
+	t.Description = src.Description
+	t.CreationTime = src.CreationTime
+	t.Tags = &Tags{}
+	t.Tags.setTags(src.Tags)
+
+	if o := src.Options; o != nil {
+		t.AmazonSideASN = o.AmazonSideAsn
+		t.AutoAcceptSharedAttachments = o.AutoAcceptSharedAttachments
+		t.DefaultRouteTableAssociation = o.DefaultRouteTableAssociation
+		t.DefaultRouteTablePropagation = o.DefaultRouteTablePropagation
+		t.DNSSupport = o.DnsSupport
+		t.VPNECMPSupport = o.VpnEcmpSupport
+	}
+}
+
+// GetTransitGatewaysWithContext returns the transit gateways which are
+// neither deleted nor being deleted
+func (c *AWSClient) GetTransitGatewaysWithContext(ctx aws.Context) (*TransitGateways, error) {
+	res := TransitGateways{}
+
+	opt := &ec2.DescribeTransitGatewaysInput{Filters: c.ec2TagFilters()}
+	for {
+		out, err := c.ec2conn.DescribeTransitGatewaysWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.TransitGateways {
+			if v == nil || !c.wantID(v.TransitGatewayId, v.TransitGatewayArn) || !c.wantCreated(v.CreationTime) {
+				continue
+			}
+			if state := aws.StringValue(v.State); state == ec2.TransitGatewayStateDeleted || state == ec2.TransitGatewayStateDeleting {
+				continue
+			}
+
+			tmp := &TransitGateway{}
+			tmp.set(v)
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_ec2_transit_gateway", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetTransitGateways calls GetTransitGatewaysWithContext with a background context
+func (c *AWSClient) GetTransitGateways() (*TransitGateways, error) {
+	return c.GetTransitGatewaysWithContext(aws.BackgroundContext())
+}
+
+func (t *TransitGateways) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_ec2_transit_gateway" .ID }}
+    resource "aws_ec2_transit_gateway" "{{ sanitizeName .ID }}" {
+      {{- if .Description }}
+      description = {{ hclString .Description }}
+      {{- end }}
+      {{- if .AmazonSideASN }}
+      amazon_side_asn = {{ .AmazonSideASN }}
+      {{- end }}
+      {{- if .AutoAcceptSharedAttachments }}
+      auto_accept_shared_attachments = "{{ .AutoAcceptSharedAttachments }}"
+      {{- end }}
+      {{- if .DefaultRouteTableAssociation }}
+      default_route_table_association = "{{ .DefaultRouteTableAssociation }}"
+      {{- end }}
+      {{- if .DefaultRouteTablePropagation }}
+      default_route_table_propagation = "{{ .DefaultRouteTablePropagation }}"
+      {{- end }}
+      {{- if .DNSSupport }}
+      dns_support = "{{ .DNSSupport }}"
+      {{- end }}
+      {{- if .VPNECMPSupport }}
+      vpn_ecmp_support = "{{ .VPNECMPSupport }}"
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, t)
+}
+
+// WriteImports writes the terraform import commands of 'TransitGateways'
+func (t *TransitGateways) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_ec2_transit_gateway.{{ sanitizeName .ID }} {{ .ID }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, t)
+}
+
+//**************** END Transit Gateway ****************
+
+//**************** BEGIN Transit Gateway VPC Attachment ****************
+
+type TransitGatewayVPCAttachment struct {
+	ID               *string
+	TransitGatewayID *string
+	VPCID            *string
+	SubnetIDs        []*string
+	DNSSupport       *string
+	IPv6Support      *string
+	CreationTime     *time.Time
+	Tags             *Tags
+}
+
+type TransitGatewayVPCAttachments []*TransitGatewayVPCAttachment
+
+func (t *TransitGatewayVPCAttachment) set(src *ec2.TransitGatewayVpcAttachment) {
+	t.ID = src.TransitGatewayAttachmentId
+	t.TransitGatewayID = src.TransitGatewayId
+	t.VPCID = src.VpcId
+	t.SubnetIDs = src.SubnetIds
+	sortStrings(t.SubnetIDs)
+	t.CreationTime = src.CreationTime
+	t.Tags = &Tags{}
+	t.Tags.setTags(src.Tags)
+
+	if o := src.Options; o != nil {
+		t.DNSSupport = o.DnsSupport
+		t.IPv6Support = o.Ipv6Support
+	}
+}
+
+// GetTransitGatewayVPCAttachmentsWithContext returns the VPC attachments
+// of transit gateways which are neither deleted, being deleted, failed
+// nor rejected
+func (c *AWSClient) GetTransitGatewayVPCAttachmentsWithContext(ctx aws.Context) (*TransitGatewayVPCAttachments, error) {
+	res := TransitGatewayVPCAttachments{}
+
+	opt := &ec2.DescribeTransitGatewayVpcAttachmentsInput{Filters: c.ec2TagFilters()}
+	for {
+		out, err := c.ec2conn.DescribeTransitGatewayVpcAttachmentsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.TransitGatewayVpcAttachments {
+			if v == nil || !c.wantID(v.TransitGatewayAttachmentId, v.TransitGatewayId, v.VpcId) || !c.wantCreated(v.CreationTime) {
+				continue
+			}
+			switch aws.StringValue(v.State) {
+			case ec2.TransitGatewayAttachmentStateDeleted, ec2.TransitGatewayAttachmentStateDeleting,
+				ec2.TransitGatewayAttachmentStateFailed, ec2.TransitGatewayAttachmentStateRejected:
+				continue
+			}
+
+			tmp := &TransitGatewayVPCAttachment{}
+			tmp.set(v)
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_ec2_transit_gateway_vpc_attachment", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetTransitGatewayVPCAttachments calls GetTransitGatewayVPCAttachmentsWithContext with a background context
+func (c *AWSClient) GetTransitGatewayVPCAttachments() (*TransitGatewayVPCAttachments, error) {
+	return c.GetTransitGatewayVPCAttachmentsWithContext(aws.BackgroundContext())
+}
+
+func (t *TransitGatewayVPCAttachments) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_ec2_transit_gateway_vpc_attachment" .ID }}
+    resource "aws_ec2_transit_gateway_vpc_attachment" "{{ sanitizeName .ID }}" {
+      transit_gateway_id = "{{ .TransitGatewayID }}"
+      vpc_id = "{{ .VPCID }}"
+      subnet_ids = [{{ StringValueSlice .SubnetIDs | joinstring "," }}]
+      {{- if .DNSSupport }}
+      dns_support = "{{ .DNSSupport }}"
+      {{- end }}
+      {{- if .IPv6Support }}
+      ipv6_support = "{{ .IPv6Support }}"
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, t)
+}
+
+// WriteImports writes the terraform import commands of 'TransitGatewayVPCAttachments'
+func (t *TransitGatewayVPCAttachments) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_ec2_transit_gateway_vpc_attachment.{{ sanitizeName .ID }} {{ .ID }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, t)
+}
+
+//**************** END Transit Gateway VPC Attachment ****************
+
+//**************** BEGIN VPN Gateway ****************
+
+type VPNGateway struct {
+	ID               *string
+	VPCID            *string
+	AvailabilityZone *string
+	AmazonSideASN    *int64
+	Tags             *Tags
+}
+
+type VPNGateways []*VPNGateway
+
+func (v *VPNGateway) set(src *ec2.VpnGateway) {
+	v.ID = src.VpnGatewayId
+	v.AvailabilityZone = src.AvailabilityZone
+	v.AmazonSideASN = src.AmazonSideAsn
+	v.Tags = &Tags{}
+	v.Tags.setTags(src.Tags)
+
+	for _, a := range src.VpcAttachments {
+		if a == nil {
+			continue
+		}
+		if state := aws.StringValue(a.State); state == ec2.AttachmentStatusAttached || state == ec2.AttachmentStatusAttaching {
+			v.VPCID = a.VpcId
+		}
+	}
+}
+
+// GetVPNGatewaysWithContext returns the VPN gateways which are neither
+// deleted nor being deleted
+func (c *AWSClient) GetVPNGatewaysWithContext(ctx aws.Context) (*VPNGateways, error) {
+	output, err := c.ec2conn.DescribeVpnGatewaysWithContext(ctx, &ec2.DescribeVpnGatewaysInput{Filters: c.ec2TagFilters()})
+	if err != nil {
+		return nil, err
+	}
+
+	res := VPNGateways{}
+	for _, v := range output.VpnGateways {
+		if v == nil || !c.wantID(v.VpnGatewayId) {
+			continue
+		}
+		if state := aws.StringValue(v.State); state == ec2.VpnStateDeleted || state == ec2.VpnStateDeleting {
+			continue
+		}
+
+		tmp := &VPNGateway{}
+		tmp.set(v)
+		res = append(res, tmp)
+	}
+
+	return &res, nil
+}
+
+// GetVPNGateways calls GetVPNGatewaysWithContext with a background context
+func (c *AWSClient) GetVPNGateways() (*VPNGateways, error) {
+	return c.GetVPNGatewaysWithContext(aws.BackgroundContext())
+}
+
+func (v *VPNGateways) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_vpn_gateway" .ID }}
+    resource "aws_vpn_gateway" "{{ sanitizeName .ID }}" {
+      {{- if .VPCID }}
+      vpc_id = "{{ .VPCID }}"
+      {{- end }}
+      {{- if .AvailabilityZone }}
+      availability_zone = "{{ .AvailabilityZone }}"
+      {{- end }}
+      {{- if .AmazonSideASN }}
+      amazon_side_asn = "{{ .AmazonSideASN }}"
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, v)
+}
+
+// WriteImports writes the terraform import commands of 'VPNGateways'
+func (v *VPNGateways) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_vpn_gateway.{{ sanitizeName .ID }} {{ .ID }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, v)
+}
+
+//**************** END VPN Gateway ****************
+
+//**************** BEGIN Customer Gateway ****************
+
+type CustomerGateway struct {
+	ID        *string
+	BGPASN    *string
+	IPAddress *string
+	Type      *string
+	Tags      *Tags
+}
+
+type CustomerGateways []*CustomerGateway
+
+// GetCustomerGatewaysWithContext returns the customer gateways which are
+// neither deleted nor being deleted
+func (c *AWSClient) GetCustomerGatewaysWithContext(ctx aws.Context) (*CustomerGateways, error) {
+	output, err := c.ec2conn.DescribeCustomerGatewaysWithContext(ctx, &ec2.DescribeCustomerGatewaysInput{Filters: c.ec2TagFilters()})
+	if err != nil {
+		return nil, err
+	}
+
+	res := CustomerGateways{}
+	for _, v := range output.CustomerGateways {
+		if v == nil || !c.wantID(v.CustomerGatewayId, v.IpAddress) {
+			continue
+		}
+		if state := aws.StringValue(v.State); state == ec2.VpnStateDeleted || state == ec2.VpnStateDeleting {
+			continue
+		}
+
+		tmp := &CustomerGateway{
+			ID:        v.CustomerGatewayId,
+			BGPASN:    v.BgpAsn,
+			IPAddress: v.IpAddress,
+			Type:      v.Type,
+			Tags:      &Tags{},
+		}
+		tmp.Tags.setTags(v.Tags)
+
+		res = append(res, tmp)
+	}
+
+	return &res, nil
+}
+
+// GetCustomerGateways calls GetCustomerGatewaysWithContext with a background context
+func (c *AWSClient) GetCustomerGateways() (*CustomerGateways, error) {
+	return c.GetCustomerGatewaysWithContext(aws.BackgroundContext())
+}
+
+func (g *CustomerGateways) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_customer_gateway" .ID }}
+    resource "aws_customer_gateway" "{{ sanitizeName .ID }}" {
+      bgp_asn = {{ .BGPASN }}
+      ip_address = "{{ .IPAddress }}"
+      type = "{{ .Type }}"
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, g)
+}
+
+// WriteImports writes the terraform import commands of 'CustomerGateways'
+func (g *CustomerGateways) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_customer_gateway.{{ sanitizeName .ID }} {{ .ID }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, g)
+}
+
+//**************** END Customer Gateway ****************
+
+//**************** BEGIN VPN Connection ****************
+
+type VPNConnection struct {
+	ID                *string
+	VPNGatewayID      *string
+	TransitGatewayID  *string
+	CustomerGatewayID *string
+	Type              *string
+	StaticRoutesOnly  *bool
+	Tags              *Tags
+
+	// Destination CIDR blocks of the static routes
+	Routes []*string
+}
+
+type VPNConnections []*VPNConnection
+
+func (v *VPNConnection) set(src *ec2.VpnConnection) {
+	v.ID = src.VpnConnectionId
+	v.VPNGatewayID = src.VpnGatewayId
+	v.TransitGatewayID = src.TransitGatewayId
+	v.CustomerGatewayID = src.CustomerGatewayId
+	v.Type = src.Type
+	v.Tags = &Tags{}
+	v.Tags.setTags(src.Tags)
+
+	if src.Options != nil && aws.BoolValue(src.Options.StaticRoutesOnly) {
+		v.StaticRoutesOnly = aws.Bool(true)
+	}
+
+	for _, r := range src.Routes {
+		if r == nil || aws.StringValue(r.Source) != ec2.VpnStaticRouteSourceStatic {
+			continue
+		}
+		if state := aws.StringValue(r.State); state == ec2.VpnStateDeleted || state == ec2.VpnStateDeleting {
+			continue
+		}
+		v.Routes = append(v.Routes, r.DestinationCidrBlock)
+	}
+	sortStrings(v.Routes)
+}
+
+// GetVPNConnectionsWithContext returns the VPN connections which are
+// neither deleted nor being deleted, with their static routes
+func (c *AWSClient) GetVPNConnectionsWithContext(ctx aws.Context) (*VPNConnections, error) {
+	output, err := c.ec2conn.DescribeVpnConnectionsWithContext(ctx, &ec2.DescribeVpnConnectionsInput{Filters: c.ec2TagFilters()})
+	if err != nil {
+		return nil, err
+	}
+
+	res := VPNConnections{}
+	for _, v := range output.VpnConnections {
+		if v == nil || !c.wantID(v.VpnConnectionId) {
+			continue
+		}
+		if state := aws.StringValue(v.State); state == ec2.VpnStateDeleted || state == ec2.VpnStateDeleting {
+			continue
+		}
+
+		tmp := &VPNConnection{}
+		tmp.set(v)
+		res = append(res, tmp)
+	}
+
+	return &res, nil
+}
+
+// GetVPNConnections calls GetVPNConnectionsWithContext with a background context
+func (c *AWSClient) GetVPNConnections() (*VPNConnections, error) {
+	return c.GetVPNConnectionsWithContext(aws.BackgroundContext())
+}
+
+func (v *VPNConnections) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"BoolValue":   aws.BoolValue,
+		"StringValue": aws.StringValue,
+	}
+
+	// The pre-shared keys of the tunnels are not returned by the API
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{- $id := .ID }}
+    {{- $label := sanitizeName .ID }}
+    {{ resourceID "aws_vpn_connection" .ID }}
+    resource "aws_vpn_connection" "{{ $label }}" {
+      # WARNING: tunnel1_preshared_key & tunnel2_preshared_key can not be read, Terraform generates new ones on apply unless they are set
+      customer_gateway_id = "{{ .CustomerGatewayID }}"
+      {{- if .VPNGatewayID }}
+      vpn_gateway_id = "{{ .VPNGatewayID }}"
+      {{- end }}
+      {{- if .TransitGatewayID }}
+      transit_gateway_id = "{{ .TransitGatewayID }}"
+      {{- end }}
+      type = "{{ .Type }}"
+      {{- if BoolValue .StaticRoutesOnly }}
+      static_routes_only = true
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- range .Routes }}
+
+    resource "aws_vpn_connection_route" "{{ sanitizeName (printf "%s_%s" (StringValue $id) (StringValue .)) }}" {
+      vpn_connection_id = "${aws_vpn_connection.{{ $label }}.id}"
+      destination_cidr_block = "{{ . }}"
+    }
+    {{- end }}
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, v)
+}
+
+// WriteImports writes the terraform import commands of 'VPNConnections'.
+// Their static routes can not be imported
+func (v *VPNConnections) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"StringValue": aws.StringValue,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+{{ $id := .ID -}}
+terraform import aws_vpn_connection.{{ sanitizeName .ID }} {{ .ID }}
+{{ range .Routes -}}
+# aws_vpn_connection_route.{{ sanitizeName (printf "%s_%s" (StringValue $id) (StringValue .)) }} ({{ . }}) can not be imported
+{{ end -}}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, v)
+}
+
+//**************** END VPN Connection ****************
@@ -0,0 +1,632 @@
+package tfit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// fakeEC2 serves canned EC2 responses. Paginated calls return the page
+// keyed by the NextToken of the request, "" for the first one. Calls the
+// fake does not implement panic on the nil ec2API
+type fakeEC2 struct {
+	ec2API
+
+	instances map[string]*ec2.DescribeInstancesOutput
+	// base64 encoded user data by instance ID
+	userData map[string]string
+
+	vpcs        *ec2.DescribeVpcsOutput
+	classicLink *ec2.DescribeVpcClassicLinkOutput
+	dnsSupport  map[string]*ec2.DescribeVpcClassicLinkDnsSupportOutput
+	// values of describe-vpc-attribute by VPC ID & attribute
+	vpcAttributes map[string]map[string]bool
+}
+
+// page returns the page of pages for token
+func page(pages interface{}, token *string) (interface{}, error) {
+	v := reflect.ValueOf(pages).MapIndex(reflect.ValueOf(aws.StringValue(token)))
+	if !v.IsValid() {
+		return nil, fmt.Errorf("unexpected NextToken %q", aws.StringValue(token))
+	}
+
+	return v.Interface(), nil
+}
+
+func (f *fakeEC2) DescribeInstancesWithContext(_ aws.Context, in *ec2.DescribeInstancesInput, _ ...request.Option) (*ec2.DescribeInstancesOutput, error) {
+	// Like AWS, unknown InstanceIds fail the call
+	if len(in.InstanceIds) > 0 {
+		return nil, awserr.New("InvalidInstanceID.NotFound", "The instance IDs do not exist", nil)
+	}
+
+	out, err := page(f.instances, in.NextToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, v := range in.Filters {
+		if aws.StringValue(v.Name) == "instance-id" {
+			ids = aws.StringValueSlice(v.Values)
+		}
+	}
+	if ids == nil {
+		return out.(*ec2.DescribeInstancesOutput), nil
+	}
+
+	// Instances the instance-id filter leaves out
+	filtered := *out.(*ec2.DescribeInstancesOutput)
+	filtered.Reservations = nil
+	for _, rsv := range out.(*ec2.DescribeInstancesOutput).Reservations {
+		var kept []*ec2.Instance
+		for _, v := range rsv.Instances {
+			if containsString(ids, aws.StringValue(v.InstanceId)) {
+				kept = append(kept, v)
+			}
+		}
+		if kept != nil {
+			filtered.Reservations = append(filtered.Reservations, reservation(kept...))
+		}
+	}
+
+	return &filtered, nil
+}
+
+func (f *fakeEC2) DescribeInstanceAttributeWithContext(_ aws.Context, in *ec2.DescribeInstanceAttributeInput, _ ...request.Option) (*ec2.DescribeInstanceAttributeOutput, error) {
+	out := &ec2.DescribeInstanceAttributeOutput{InstanceId: in.InstanceId}
+	if data, ok := f.userData[aws.StringValue(in.InstanceId)]; ok && aws.StringValue(in.Attribute) == ec2.InstanceAttributeNameUserData {
+		out.UserData = &ec2.AttributeValue{Value: aws.String(data)}
+	}
+
+	return out, nil
+}
+
+func (f *fakeEC2) DescribeVolumesWithContext(aws.Context, *ec2.DescribeVolumesInput, ...request.Option) (*ec2.DescribeVolumesOutput, error) {
+	return &ec2.DescribeVolumesOutput{}, nil
+}
+
+func (f *fakeEC2) DescribeVpcsWithContext(aws.Context, *ec2.DescribeVpcsInput, ...request.Option) (*ec2.DescribeVpcsOutput, error) {
+	return f.vpcs, nil
+}
+
+func (f *fakeEC2) DescribeVpcClassicLinkWithContext(aws.Context, *ec2.DescribeVpcClassicLinkInput, ...request.Option) (*ec2.DescribeVpcClassicLinkOutput, error) {
+	if f.classicLink == nil {
+		return &ec2.DescribeVpcClassicLinkOutput{}, nil
+	}
+
+	return f.classicLink, nil
+}
+
+func (f *fakeEC2) DescribeVpcClassicLinkDnsSupportWithContext(_ aws.Context, in *ec2.DescribeVpcClassicLinkDnsSupportInput, _ ...request.Option) (*ec2.DescribeVpcClassicLinkDnsSupportOutput, error) {
+	if f.dnsSupport == nil {
+		return &ec2.DescribeVpcClassicLinkDnsSupportOutput{}, nil
+	}
+
+	out, err := page(f.dnsSupport, in.NextToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(*ec2.DescribeVpcClassicLinkDnsSupportOutput), nil
+}
+
+func (f *fakeEC2) DescribeVpcAttributeWithContext(_ aws.Context, in *ec2.DescribeVpcAttributeInput, _ ...request.Option) (*ec2.DescribeVpcAttributeOutput, error) {
+	attribute := aws.StringValue(in.Attribute)
+	value, ok := f.vpcAttributes[aws.StringValue(in.VpcId)][attribute]
+	if !ok {
+		return &ec2.DescribeVpcAttributeOutput{VpcId: in.VpcId}, nil
+	}
+
+	out := &ec2.DescribeVpcAttributeOutput{VpcId: in.VpcId}
+	switch attribute {
+	case ec2.VpcAttributeNameEnableDnsHostnames:
+		out.EnableDnsHostnames = &ec2.AttributeBooleanValue{Value: aws.Bool(value)}
+	case ec2.VpcAttributeNameEnableDnsSupport:
+		out.EnableDnsSupport = &ec2.AttributeBooleanValue{Value: aws.Bool(value)}
+	}
+
+	return out, nil
+}
+
+func newFakeClient(f *fakeEC2) *AWSClient {
+	return &AWSClient{Logger: log.New(ioutil.Discard, "", 0), ec2conn: f}
+}
+
+func ec2Instance(id string, state int64, tags ...string) *ec2.Instance {
+	res := &ec2.Instance{
+		InstanceId:   aws.String(id),
+		ImageId:      aws.String("ami-12345678"),
+		InstanceType: aws.String("t2.micro"),
+		State:        &ec2.InstanceState{Code: aws.Int64(state)},
+	}
+	for k := 0; k+1 < len(tags); k += 2 {
+		res.Tags = append(res.Tags, &ec2.Tag{Key: aws.String(tags[k]), Value: aws.String(tags[k+1])})
+	}
+
+	return res
+}
+
+func reservation(instances ...*ec2.Instance) *ec2.Reservation {
+	return &ec2.Reservation{Instances: instances}
+}
+
+func TestGetInstances(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     map[string]*ec2.DescribeInstancesOutput
+		skipASG   bool
+		ids       []string
+		wantIDs   []string
+		wantHCL   []string
+		unwantHCL []string
+	}{
+		{
+			name: "single page",
+			pages: map[string]*ec2.DescribeInstancesOutput{
+				"": {Reservations: []*ec2.Reservation{reservation(ec2Instance("i-1", 16), ec2Instance("i-2", 80))}},
+			},
+			wantIDs: []string{"i-1", "i-2"},
+		},
+		{
+			name: "pagination",
+			pages: map[string]*ec2.DescribeInstancesOutput{
+				"": {
+					Reservations: []*ec2.Reservation{reservation(ec2Instance("i-1", 16))},
+					NextToken:    aws.String("page2"),
+				},
+				"page2": {
+					Reservations: []*ec2.Reservation{reservation(ec2Instance("i-2", 16)), reservation(ec2Instance("i-3", 16))},
+					NextToken:    aws.String("page3"),
+				},
+				"page3": {Reservations: []*ec2.Reservation{reservation(ec2Instance("i-4", 16))}},
+			},
+			wantIDs: []string{"i-1", "i-2", "i-3", "i-4"},
+		},
+		{
+			name: "terminated instances skipped",
+			pages: map[string]*ec2.DescribeInstancesOutput{
+				"": {Reservations: []*ec2.Reservation{reservation(ec2Instance("i-1", 48), ec2Instance("i-2", 16), ec2Instance("i-3", 48))}},
+			},
+			wantIDs:   []string{"i-2"},
+			unwantHCL: []string{"i-1_instance", "i-3_instance"},
+		},
+		{
+			name: "auto scaling instances skipped",
+			pages: map[string]*ec2.DescribeInstancesOutput{
+				"": {Reservations: []*ec2.Reservation{reservation(ec2Instance("i-1", 16, "aws:autoscaling:groupName", "web"), ec2Instance("i-2", 16))}},
+			},
+			skipASG: true,
+			wantIDs: []string{"i-2"},
+		},
+		{
+			name: "IDs of terminated instances ignored",
+			pages: map[string]*ec2.DescribeInstancesOutput{
+				"": {Reservations: []*ec2.Reservation{reservation(ec2Instance("i-1", 16), ec2Instance("i-2", 16))}},
+			},
+			ids:     []string{"i-2", "i-gone"},
+			wantIDs: []string{"i-2"},
+		},
+		{
+			name: "tags",
+			pages: map[string]*ec2.DescribeInstancesOutput{
+				"": {Reservations: []*ec2.Reservation{reservation(ec2Instance("i-1", 16, "Name", "web", "Role", `say "hi"`))}},
+			},
+			wantIDs: []string{"i-1"},
+			wantHCL: []string{`"Name" = "web"`, `"Role" = "say \"hi\""`},
+		},
+		{
+			name: "no tags",
+			pages: map[string]*ec2.DescribeInstancesOutput{
+				"": {Reservations: []*ec2.Reservation{reservation(ec2Instance("i-1", 16))}},
+			},
+			wantIDs:   []string{"i-1"},
+			unwantHCL: []string{"tags"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeClient(&fakeEC2{instances: tt.pages})
+			c.SkipASGInstances = tt.skipASG
+			c.IDs = tt.ids
+
+			instances, err := c.GetInstances()
+			if err != nil {
+				t.Fatalf("GetInstances: %v", err)
+			}
+
+			var ids []string
+			for _, v := range *instances {
+				ids = append(ids, aws.StringValue(v.InstanceID))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("GetInstances returned %q, want %q", ids, tt.wantIDs)
+			}
+
+			var buf bytes.Buffer
+			if err := instances.WriteHCL(&buf); err != nil {
+				t.Fatalf("WriteHCL: %v", err)
+			}
+			for _, want := range tt.wantHCL {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("want %s in:\n%s", want, buf.String())
+				}
+			}
+			for _, unwant := range tt.unwantHCL {
+				if strings.Contains(buf.String(), unwant) {
+					t.Errorf("unexpected %s in:\n%s", unwant, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func ec2VPC(id string, tags ...string) *ec2.Vpc {
+	res := &ec2.Vpc{VpcId: aws.String(id), CidrBlock: aws.String("10.0.0.0/16")}
+	for k := 0; k+1 < len(tags); k += 2 {
+		res.Tags = append(res.Tags, &ec2.Tag{Key: aws.String(tags[k]), Value: aws.String(tags[k+1])})
+	}
+
+	return res
+}
+
+func TestGetVPCs(t *testing.T) {
+	tests := []struct {
+		name      string
+		fake      *fakeEC2
+		routines  int
+		wantIDs   []string
+		wantHCL   []string
+		unwantHCL []string
+	}{
+		{
+			name:    "sorted by ID",
+			fake:    &fakeEC2{vpcs: &ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{ec2VPC("vpc-3"), ec2VPC("vpc-1"), ec2VPC("vpc-2")}}},
+			wantIDs: []string{"vpc-1", "vpc-2", "vpc-3"},
+		},
+		{
+			name:     "single routine",
+			fake:     &fakeEC2{vpcs: &ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{ec2VPC("vpc-2"), ec2VPC("vpc-1")}}},
+			routines: 1,
+			wantIDs:  []string{"vpc-1", "vpc-2"},
+		},
+		{
+			name: "attributes",
+			fake: &fakeEC2{
+				vpcs: &ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{ec2VPC("vpc-1"), ec2VPC("vpc-2")}},
+				vpcAttributes: map[string]map[string]bool{
+					"vpc-1": {ec2.VpcAttributeNameEnableDnsHostnames: true, ec2.VpcAttributeNameEnableDnsSupport: true},
+				},
+			},
+			wantIDs: []string{"vpc-1", "vpc-2"},
+			wantHCL: []string{"enable_dns_hostnames = true", "enable_dns_support   = true"},
+		},
+		{
+			name:    "tags",
+			fake:    &fakeEC2{vpcs: &ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{ec2VPC("vpc-1", "Name", "main", "Team", "a\nb")}}},
+			wantIDs: []string{"vpc-1"},
+			wantHCL: []string{`resource "aws_vpc" "main"`, `"Name" = "main"`, `"Team" = "a\nb"`},
+		},
+		{
+			name:      "no tags",
+			fake:      &fakeEC2{vpcs: &ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{ec2VPC("vpc-1")}}},
+			wantIDs:   []string{"vpc-1"},
+			wantHCL:   []string{`resource "aws_vpc" "vpc-1"`},
+			unwantHCL: []string{"tags"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeClient(tt.fake)
+			c.MaxVPCRoutines = tt.routines
+
+			vpcs, err := c.GetVPCs()
+			if err != nil {
+				t.Fatalf("GetVPCs: %v", err)
+			}
+
+			var ids []string
+			for _, v := range *vpcs {
+				ids = append(ids, aws.StringValue(v.VPCId))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("GetVPCs returned %q, want %q", ids, tt.wantIDs)
+			}
+
+			var buf bytes.Buffer
+			if err := vpcs.WriteHCL(&buf); err != nil {
+				t.Fatalf("WriteHCL: %v", err)
+			}
+			for _, want := range tt.wantHCL {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("want %s in:\n%s", want, buf.String())
+				}
+			}
+			for _, unwant := range tt.unwantHCL {
+				if strings.Contains(buf.String(), unwant) {
+					t.Errorf("unexpected %s in:\n%s", unwant, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestInstanceSetMonitoring(t *testing.T) {
+	tests := []struct {
+		name       string
+		monitoring *ec2.Monitoring
+		want       bool
+	}{
+		{"missing block", nil, false},
+		{"missing state", &ec2.Monitoring{}, false},
+		{"disabled", &ec2.Monitoring{State: aws.String(ec2.MonitoringStateDisabled)}, false},
+		{"enabled", &ec2.Monitoring{State: aws.String(ec2.MonitoringStateEnabled)}, true},
+		{"pending", &ec2.Monitoring{State: aws.String(ec2.MonitoringStatePending)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := ec2Instance("i-1", 16)
+			src.Monitoring = tt.monitoring
+
+			i := &Instance{}
+			if err := i.set(src); err != nil {
+				t.Fatalf("set: %v", err)
+			}
+			if i.Monitoring == nil || *i.Monitoring != tt.want {
+				t.Errorf("Monitoring = %v, want %v", aws.BoolValue(i.Monitoring), tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeVpcClassicLinkDnsSupportPages(t *testing.T) {
+	f := &fakeEC2{
+		vpcs: &ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{ec2VPC("vpc-1"), ec2VPC("vpc-2")}},
+		dnsSupport: map[string]*ec2.DescribeVpcClassicLinkDnsSupportOutput{
+			"": {
+				Vpcs:      []*ec2.ClassicLinkDnsSupport{{VpcId: aws.String("vpc-1"), ClassicLinkDnsSupported: aws.Bool(false)}},
+				NextToken: aws.String("page2"),
+			},
+			"page2": {
+				Vpcs: []*ec2.ClassicLinkDnsSupport{{VpcId: aws.String("vpc-2"), ClassicLinkDnsSupported: aws.Bool(true)}},
+			},
+		},
+	}
+	c := newFakeClient(f)
+
+	out, err := c.describeVpcClassicLinkDnsSupport(aws.BackgroundContext())
+	if err != nil {
+		t.Fatalf("describeVpcClassicLinkDnsSupport: %v", err)
+	}
+	if len(out.Vpcs) != 2 {
+		t.Fatalf("describeVpcClassicLinkDnsSupport returned %d VPCs, want the 2 of both pages", len(out.Vpcs))
+	}
+
+	vpcs, err := c.GetVPCs()
+	if err != nil {
+		t.Fatalf("GetVPCs: %v", err)
+	}
+	want := map[string]bool{"vpc-1": false, "vpc-2": true}
+	for _, v := range *vpcs {
+		id := aws.StringValue(v.VPCId)
+		if v.EnableClassicLinkDnsSupport == nil || *v.EnableClassicLinkDnsSupport != want[id] {
+			t.Errorf("%s: EnableClassicLinkDnsSupport = %v, want %v", id, aws.BoolValue(v.EnableClassicLinkDnsSupport), want[id])
+		}
+	}
+}
+
+func gzipped(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestSetUserData(t *testing.T) {
+	cloudConfig := "#cloud-config\npackages:\n  - nginx\n"
+	compressed := gzipped(t, cloudConfig)
+	// Gzip magic bytes followed by garbage
+	invalid := append([]byte{0x1f, 0x8b}, []byte("not a gzip stream\n")...)
+
+	tests := []struct {
+		name          string
+		data          []byte
+		wantUserData  *string
+		wantBase64    *string
+		wantGunzipped *string
+		wantHCL       []string
+	}{
+		{
+			name:         "plain",
+			data:         []byte(cloudConfig),
+			wantUserData: aws.String(cloudConfig),
+			wantHCL:      []string{"user_data = <<EOF\n#cloud-config\n"},
+		},
+		{
+			name:          "gzip",
+			data:          compressed,
+			wantBase64:    aws.String(base64.StdEncoding.EncodeToString(compressed)),
+			wantGunzipped: aws.String(cloudConfig),
+			wantHCL: []string{
+				"# The user data is gzip compressed, decompressed:\n  # #cloud-config\n  # packages:\n  #   - nginx\n",
+				"user_data_base64 = \"" + base64.StdEncoding.EncodeToString(compressed) + "\"",
+			},
+		},
+		{
+			name:       "invalid gzip",
+			data:       invalid,
+			wantBase64: aws.String(base64.StdEncoding.EncodeToString(invalid)),
+			wantHCL:    []string{"user_data_base64 = \"" + base64.StdEncoding.EncodeToString(invalid) + "\""},
+		},
+		{
+			name:       "no trailing newline",
+			data:       []byte("#!/bin/sh\necho hi"),
+			wantBase64: aws.String(base64.StdEncoding.EncodeToString([]byte("#!/bin/sh\necho hi"))),
+			wantHCL:    []string{"user_data_base64 = "},
+		},
+		{
+			name:          "gzip without trailing newline",
+			data:          gzipped(t, "#!/bin/sh\necho hi"),
+			wantBase64:    aws.String(base64.StdEncoding.EncodeToString(gzipped(t, "#!/bin/sh\necho hi"))),
+			wantGunzipped: aws.String("#!/bin/sh\necho hi"),
+			wantHCL:       []string{"# #!/bin/sh\n  # echo hi\n", "user_data_base64 = "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeClient(&fakeEC2{userData: map[string]string{"i-1": base64.StdEncoding.EncodeToString(tt.data)}})
+
+			i := &Instance{InstanceID: aws.String("i-1"), ImageID: aws.String("ami-12345678"), InstanceType: aws.String("t2.micro")}
+			if err := c.setUserData(aws.BackgroundContext(), i); err != nil {
+				t.Fatalf("setUserData: %v", err)
+			}
+
+			if !reflect.DeepEqual(i.UserData, tt.wantUserData) {
+				t.Errorf("UserData = %q, want %q", aws.StringValue(i.UserData), aws.StringValue(tt.wantUserData))
+			}
+			if !reflect.DeepEqual(i.UserDataBase64, tt.wantBase64) {
+				t.Errorf("UserDataBase64 = %q, want %q", aws.StringValue(i.UserDataBase64), aws.StringValue(tt.wantBase64))
+			}
+			if !reflect.DeepEqual(i.UserDataGunzipped, tt.wantGunzipped) {
+				t.Errorf("UserDataGunzipped = %q, want %q", aws.StringValue(i.UserDataGunzipped), aws.StringValue(tt.wantGunzipped))
+			}
+
+			var buf bytes.Buffer
+			if err := (&Instances{i}).WriteHCL(&buf); err != nil {
+				t.Fatalf("WriteHCL: %v", err)
+			}
+			for _, want := range tt.wantHCL {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("want %q in:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGetInstancesVPCsSparseResponses(t *testing.T) {
+	f := &fakeEC2{
+		instances: map[string]*ec2.DescribeInstancesOutput{
+			"": {Reservations: []*ec2.Reservation{nil, {}, {Instances: []*ec2.Instance{nil, {}}}}},
+		},
+		vpcs:        &ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{nil, {}}},
+		classicLink: &ec2.DescribeVpcClassicLinkOutput{Vpcs: []*ec2.VpcClassicLink{nil, {}}},
+		dnsSupport: map[string]*ec2.DescribeVpcClassicLinkDnsSupportOutput{
+			"": {Vpcs: []*ec2.ClassicLinkDnsSupport{nil, {}}},
+		},
+	}
+	c := newFakeClient(f)
+
+	if _, err := c.GetInstances(); err != nil {
+		t.Errorf("GetInstances: %v", err)
+	}
+	if _, err := c.GetVPCs(); err != nil {
+		t.Errorf("GetVPCs: %v", err)
+	}
+}
+
+func TestVPCEndpointsWriteHCLInjectedTags(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{
+		ExtraTags: map[string]string{"ManagedBy": "terraform", "Team": "platform"},
+	}
+
+	endpoints := &VPCEndpoints{{
+		Id:          aws.String("vpce-1"),
+		VpcId:       aws.String("vpc-1"),
+		ServiceName: aws.String("com.amazonaws.us-east-1.s3"),
+		Tags:        &Tags{"Team": aws.String("network"), "Name": aws.String("s3")},
+	}}
+
+	var buf bytes.Buffer
+	if err := endpoints.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	out := strings.Join(strings.Fields(buf.String()), " ")
+
+	// The endpoint's own tags win over the injected ones
+	want := `tags { "ManagedBy" = "terraform" "Name" = "s3" "Team" = "network" }`
+	if !strings.Contains(out, want) {
+		t.Errorf("want %s in:\n%s", want, buf.String())
+	}
+}
+
+func TestSecurityGroupCrossAccountRule(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{}
+
+	sg := &SecurityGroup{}
+	sg.setSecurityGroup(&ec2.SecurityGroup{
+		GroupId:   aws.String("sg-local"),
+		GroupName: aws.String("web"),
+		VpcId:     aws.String("vpc-1"),
+		IpPermissions: []*ec2.IpPermission{{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int64(443),
+			ToPort:     aws.Int64(443),
+			UserIdGroupPairs: []*ec2.UserIdGroupPair{
+				// A group of the VPC peered from another account
+				{UserId: aws.String("210987654321"), GroupId: aws.String("sg-peer"), VpcPeeringConnectionId: aws.String("pcx-1")},
+				{UserId: aws.String("123456789012"), GroupId: aws.String("sg-same")},
+			},
+		}},
+	}, aws.String("123456789012"))
+
+	var buf bytes.Buffer
+	if err := (&SecurityGroups{sg}).WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	want := `security_groups = ["210987654321/sg-peer", "sg-same"]`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("want %s in:\n%s", want, buf.String())
+	}
+}
+
+func TestInstanceSecurityGroupsSorted(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{}
+
+	src := ec2Instance("i-1", 16)
+	src.VpcId = aws.String("vpc-1")
+	src.SubnetId = aws.String("subnet-1")
+	for _, id := range []string{"sg-c", "sg-a", "sg-b"} {
+		src.SecurityGroups = append(src.SecurityGroups, &ec2.GroupIdentifier{
+			GroupId:   aws.String(id),
+			GroupName: aws.String("name-" + id),
+		})
+	}
+
+	i := &Instance{}
+	if err := i.set(src); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&Instances{i}).WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	want := `vpc_security_group_ids = ["sg-a", "sg-b", "sg-c"]`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("want %s in:\n%s", want, buf.String())
+	}
+}
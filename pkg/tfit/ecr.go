@@ -0,0 +1,140 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+)
+
+//**************** ECR Repository ****************
+type ECRRepository struct {
+	Name            *string
+	Arn             *string
+	LifecyclePolicy *string
+	Tags            *Tags
+}
+
+type ECRRepositories []*ECRRepository
+
+func (r *ECRRepository) getLifecyclePolicy(ctx aws.Context, c *AWSClient) error {
+	output, err := c.ecrconn.GetLifecyclePolicyWithContext(ctx, &ecr.GetLifecyclePolicyInput{RepositoryName: r.Name})
+	if err != nil {
+		return handleError(err)
+	}
+
+	r.LifecyclePolicy = output.LifecyclePolicyText
+
+	return nil
+}
+
+func (r *ECRRepository) getTags(ctx aws.Context, c *AWSClient) error {
+	output, err := c.ecrconn.ListTagsForResourceWithContext(ctx, &ecr.ListTagsForResourceInput{ResourceArn: r.Arn})
+	if err != nil {
+		return err
+	}
+
+	r.Tags = &Tags{}
+	for _, v := range output.Tags {
+		if v != nil && v.Key != nil {
+			(*r.Tags)[*v.Key] = v.Value
+		}
+	}
+
+	return nil
+}
+
+func (c *AWSClient) GetECRRepositoriesWithContext(ctx aws.Context) (*ECRRepositories, error) {
+	var res ECRRepositories
+
+	opt := &ecr.DescribeRepositoriesInput{}
+	for {
+		out, err := c.ecrconn.DescribeRepositoriesWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Repositories {
+			if v == nil || !c.wantID(v.RepositoryName, v.RepositoryArn) {
+				continue
+			}
+
+			tmp := &ECRRepository{
+				Name: v.RepositoryName,
+				Arn:  v.RepositoryArn,
+			}
+			if err := tmp.getTags(ctx, c); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+			if err := tmp.getLifecyclePolicy(ctx, c); err != nil {
+				return nil, err
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_ecr_repository", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetECRRepositories calls GetECRRepositoriesWithContext with a background context
+func (c *AWSClient) GetECRRepositories() (*ECRRepositories, error) {
+	return c.GetECRRepositoriesWithContext(aws.BackgroundContext())
+}
+
+func (r *ECRRepositories) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_ecr_repository" .Name }}
+    resource "aws_ecr_repository" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- if .LifecyclePolicy }}
+
+    resource "aws_ecr_lifecycle_policy" "{{ sanitizeName .Name }}" {
+      repository = "{{ .Name }}"
+      {{ jsonWarning .LifecyclePolicy }}
+      policy = <<POLICY
+      {{ jsonDocument .LifecyclePolicy }}
+POLICY
+    }
+    {{- end }}
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, r)
+}
+
+// WriteImports writes the terraform import commands of 'ECRRepositories'
+func (r *ECRRepositories) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_ecr_repository.{{ sanitizeName .Name }} {{ .Name }}
+{{ if .LifecyclePolicy -}}
+terraform import aws_ecr_lifecycle_policy.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, r)
+}
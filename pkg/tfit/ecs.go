@@ -0,0 +1,390 @@
+package tfit
+
+import (
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// Maximum number of clusters & services accepted by
+// ecs:DescribeClusters & ecs:DescribeServices
+const (
+	ecsDescribeClustersMax = 100
+	ecsDescribeServicesMax = 10
+)
+
+//**************** ECS Cluster ****************
+type ECSCluster struct {
+	Name *string
+	ARN  *string
+	Tags *Tags
+}
+
+type ECSClusters []*ECSCluster
+
+// listECSClusters returns the ARN of every cluster
+func (c *AWSClient) listECSClusters(ctx aws.Context) ([]*string, error) {
+	var res []*string
+
+	opt := &ecs.ListClustersInput{}
+	for {
+		out, err := c.ecsconn.ListClustersWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, out.ClusterArns...)
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return res, nil
+}
+
+func (c *AWSClient) GetECSClustersWithContext(ctx aws.Context) (*ECSClusters, error) {
+	arns, err := c.listECSClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res ECSClusters
+	for i := 0; i < len(arns); i += ecsDescribeClustersMax {
+		end := i + ecsDescribeClustersMax
+		if end > len(arns) {
+			end = len(arns)
+		}
+
+		out, err := c.ecsconn.DescribeClustersWithContext(ctx, &ecs.DescribeClustersInput{
+			Clusters: arns[i:end],
+			Include:  []*string{aws.String(ecs.ClusterFieldTags)},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Clusters {
+			if v == nil || aws.StringValue(v.Status) != "ACTIVE" || !c.wantID(v.ClusterName, v.ClusterArn) {
+				continue
+			}
+
+			tmp := &ECSCluster{
+				Name: v.ClusterName,
+				ARN:  v.ClusterArn,
+				Tags: ecsTags(v.Tags),
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+
+			res = append(res, tmp)
+		}
+	}
+
+	return &res, nil
+}
+
+// GetECSClusters calls GetECSClustersWithContext with a background context
+func (c *AWSClient) GetECSClusters() (*ECSClusters, error) {
+	return c.GetECSClustersWithContext(aws.BackgroundContext())
+}
+
+func (e *ECSClusters) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_ecs_cluster" .ARN }}
+    resource "aws_ecs_cluster" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, e)
+}
+
+// WriteImports writes the terraform import commands of 'ECSClusters'
+func (e *ECSClusters) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_ecs_cluster.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, e)
+}
+
+//**************** ECS Service ****************
+type ECSService struct {
+	Name               *string
+	ARN                *string
+	ClusterName        *string
+	ClusterARN         *string
+	TaskDefinition     *string
+	DesiredCount       *int64
+	LaunchType         *string
+	SchedulingStrategy *string
+	PlatformVersion    *string
+	IAMRole            *string
+
+	HealthCheckGracePeriod *int64
+	MinimumHealthyPercent  *int64
+	MaximumPercent         *int64
+
+	LoadBalancers []*ecs.LoadBalancer
+
+	// awsvpc network mode only
+	Subnets        []*string
+	SecurityGroups []*string
+	AssignPublicIP bool
+
+	Tags *Tags
+
+	// Set by ResolveReferences when the cluster is exported as well
+	ClusterExported bool
+}
+
+type ECSServices []*ECSService
+
+func (s *ECSService) set(clusterName *string, src *ecs.Service) {
+	s.Name = src.ServiceName
+	s.ARN = src.ServiceArn
+	s.ClusterName = clusterName
+	s.ClusterARN = src.ClusterArn
+	s.TaskDefinition = src.TaskDefinition
+	s.DesiredCount = src.DesiredCount
+	s.LaunchType = src.LaunchType
+	s.SchedulingStrategy = src.SchedulingStrategy
+	s.PlatformVersion = src.PlatformVersion
+	s.HealthCheckGracePeriod = src.HealthCheckGracePeriodSeconds
+	s.LoadBalancers = src.LoadBalancers
+	s.Tags = ecsTags(src.Tags)
+
+	// The service linked role is used when no role was given
+	if !strings.Contains(aws.StringValue(src.RoleArn), "/aws-service-role/") {
+		s.IAMRole = src.RoleArn
+	}
+
+	if d := src.DeploymentConfiguration; d != nil {
+		s.MinimumHealthyPercent = d.MinimumHealthyPercent
+		s.MaximumPercent = d.MaximumPercent
+	}
+
+	if n := src.NetworkConfiguration; n != nil && n.AwsvpcConfiguration != nil {
+		s.Subnets = n.AwsvpcConfiguration.Subnets
+		s.SecurityGroups = n.AwsvpcConfiguration.SecurityGroups
+		s.AssignPublicIP = aws.StringValue(n.AwsvpcConfiguration.AssignPublicIp) == ecs.AssignPublicIpEnabled
+		sortStrings(s.Subnets)
+		sortStrings(s.SecurityGroups)
+	}
+}
+
+// getECSServices returns the services of a cluster
+func (c *AWSClient) getECSServices(ctx aws.Context, cluster *ecs.Cluster) (ECSServices, error) {
+	var arns []*string
+
+	opt := &ecs.ListServicesInput{Cluster: cluster.ClusterArn}
+	for {
+		out, err := c.ecsconn.ListServicesWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		arns = append(arns, out.ServiceArns...)
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	var res ECSServices
+	for i := 0; i < len(arns); i += ecsDescribeServicesMax {
+		end := i + ecsDescribeServicesMax
+		if end > len(arns) {
+			end = len(arns)
+		}
+
+		out, err := c.ecsconn.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
+			Cluster:  cluster.ClusterArn,
+			Services: arns[i:end],
+			Include:  []*string{aws.String(ecs.ServiceFieldTags)},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Services {
+			if v == nil || aws.StringValue(v.Status) != "ACTIVE" || !c.wantID(v.ServiceName, v.ServiceArn) {
+				continue
+			}
+
+			tmp := &ECSService{}
+			tmp.set(cluster.ClusterName, v)
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+
+			res = append(res, tmp)
+		}
+	}
+
+	return res, nil
+}
+
+// GetECSServicesWithContext returns the services of every active cluster
+func (c *AWSClient) GetECSServicesWithContext(ctx aws.Context) (*ECSServices, error) {
+	arns, err := c.listECSClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res ECSServices
+	for i := 0; i < len(arns); i += ecsDescribeClustersMax {
+		end := i + ecsDescribeClustersMax
+		if end > len(arns) {
+			end = len(arns)
+		}
+
+		out, err := c.ecsconn.DescribeClustersWithContext(ctx, &ecs.DescribeClustersInput{Clusters: arns[i:end]})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Clusters {
+			if v == nil || aws.StringValue(v.Status) != "ACTIVE" {
+				continue
+			}
+
+			services, err := c.getECSServices(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, services...)
+		}
+	}
+
+	return &res, nil
+}
+
+// GetECSServices calls GetECSServicesWithContext with a background context
+func (c *AWSClient) GetECSServices() (*ECSServices, error) {
+	return c.GetECSServicesWithContext(aws.BackgroundContext())
+}
+
+func (e *ECSServices) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+		"StringValue":      aws.StringValue,
+	}
+
+	// Services reference their cluster when it is exported along with
+	// them, see ResolveReferences, and hold its ARN otherwise
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_ecs_service" .ARN }}
+    resource "aws_ecs_service" "{{ sanitizeName (printf "%s_%s" (StringValue .ClusterName) (StringValue .Name)) }}" {
+      name = "{{ .Name }}"
+      {{- if .ClusterExported }}
+      cluster = "${aws_ecs_cluster.{{ sanitizeName .ClusterName }}.id}"
+      {{- else }}
+      cluster = "{{ .ClusterARN }}"
+      {{- end }}
+      task_definition = "{{ .TaskDefinition }}"
+      {{- if eq (StringValue .SchedulingStrategy) "DAEMON" }}
+      scheduling_strategy = "DAEMON"
+      {{- else }}
+      desired_count = {{ .DesiredCount }}
+      {{- end }}
+      {{- if .LaunchType }}
+      launch_type = "{{ .LaunchType }}"
+      {{- end }}
+      {{- if and (eq (StringValue .LaunchType) "FARGATE") .PlatformVersion }}
+      platform_version = "{{ .PlatformVersion }}"
+      {{- end }}
+      {{- if .IAMRole }}
+      iam_role = "{{ .IAMRole }}"
+      {{- end }}
+      {{- if .HealthCheckGracePeriod }}
+      health_check_grace_period_seconds = {{ .HealthCheckGracePeriod }}
+      {{- end }}
+      {{- if .MinimumHealthyPercent }}
+      deployment_minimum_healthy_percent = {{ .MinimumHealthyPercent }}
+      {{- end }}
+      {{- if .MaximumPercent }}
+      deployment_maximum_percent = {{ .MaximumPercent }}
+      {{- end }}
+      {{- range .LoadBalancers }}
+      load_balancer {
+        {{- if .TargetGroupArn }}
+        target_group_arn = "{{ .TargetGroupArn }}"
+        {{- end }}
+        {{- if .LoadBalancerName }}
+        elb_name = "{{ .LoadBalancerName }}"
+        {{- end }}
+        container_name = "{{ .ContainerName }}"
+        container_port = {{ .ContainerPort }}
+      }
+      {{- end }}
+      {{- if .Subnets }}
+      network_configuration {
+        subnets = [{{ StringValueSlice .Subnets | joinstring "," }}]
+        {{- if .SecurityGroups }}
+        security_groups = [{{ StringValueSlice .SecurityGroups | joinstring "," }}]
+        {{- end }}
+        assign_public_ip = {{ .AssignPublicIP }}
+      }
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, e)
+}
+
+// WriteImports writes the terraform import commands of 'ECSServices'
+func (e *ECSServices) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"StringValue": aws.StringValue,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_ecs_service.{{ sanitizeName (printf "%s_%s" (StringValue .ClusterName) (StringValue .Name)) }} {{ .ClusterName }}/{{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, e)
+}
+
+func ecsTags(src []*ecs.Tag) *Tags {
+	res := &Tags{}
+	for _, v := range src {
+		if v != nil && v.Key != nil {
+			(*res)[*v.Key] = v.Value
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,222 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/efs"
+)
+
+//**************** EFS File System ****************
+type EFSFileSystem struct {
+	FileSystemID          *string
+	CreationToken         *string
+	PerformanceMode       *string
+	ThroughputMode        *string
+	ProvisionedThroughput *float64
+	Encrypted             *bool
+	KmsKeyID              *string
+	Tags                  *Tags
+
+	MountTargets []*EFSMountTarget
+}
+
+type EFSFileSystems []*EFSFileSystem
+
+type EFSMountTarget struct {
+	MountTargetID  *string
+	SubnetID       *string
+	IPAddress      *string
+	SecurityGroups []*string
+}
+
+func (f *EFSFileSystem) getTags(ctx aws.Context, c *AWSClient) error {
+	f.Tags = &Tags{}
+
+	opt := &efs.DescribeTagsInput{FileSystemId: f.FileSystemID}
+	for {
+		out, err := c.efsconn.DescribeTagsWithContext(ctx, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.Tags {
+			if v != nil && v.Key != nil {
+				(*f.Tags)[*v.Key] = v.Value
+			}
+		}
+
+		if out.NextMarker == nil {
+			break
+		}
+		opt.Marker = out.NextMarker
+	}
+
+	return nil
+}
+
+func (f *EFSFileSystem) getMountTargets(ctx aws.Context, c *AWSClient) error {
+	opt := &efs.DescribeMountTargetsInput{FileSystemId: f.FileSystemID}
+	for {
+		out, err := c.efsconn.DescribeMountTargetsWithContext(ctx, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.MountTargets {
+			if v == nil || aws.StringValue(v.LifeCycleState) != efs.LifeCycleStateAvailable {
+				continue
+			}
+
+			sgs, err := c.efsconn.DescribeMountTargetSecurityGroupsWithContext(ctx, &efs.DescribeMountTargetSecurityGroupsInput{
+				MountTargetId: v.MountTargetId,
+			})
+			if err != nil {
+				return err
+			}
+			sortStrings(sgs.SecurityGroups)
+
+			f.MountTargets = append(f.MountTargets, &EFSMountTarget{
+				MountTargetID:  v.MountTargetId,
+				SubnetID:       v.SubnetId,
+				IPAddress:      v.IpAddress,
+				SecurityGroups: sgs.SecurityGroups,
+			})
+		}
+
+		if out.NextMarker == nil {
+			break
+		}
+		opt.Marker = out.NextMarker
+	}
+
+	return nil
+}
+
+// GetEFSFileSystemsWithContext returns the available file systems with
+// their mount targets
+func (c *AWSClient) GetEFSFileSystemsWithContext(ctx aws.Context) (*EFSFileSystems, error) {
+	var res EFSFileSystems
+
+	opt := &efs.DescribeFileSystemsInput{}
+	for {
+		out, err := c.efsconn.DescribeFileSystemsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.FileSystems {
+			if v == nil || aws.StringValue(v.LifeCycleState) != efs.LifeCycleStateAvailable ||
+				!c.wantID(v.FileSystemId, v.CreationToken) || !c.wantCreated(v.CreationTime) {
+				continue
+			}
+
+			tmp := &EFSFileSystem{
+				FileSystemID:    v.FileSystemId,
+				CreationToken:   v.CreationToken,
+				PerformanceMode: v.PerformanceMode,
+				ThroughputMode:  v.ThroughputMode,
+				Encrypted:       v.Encrypted,
+				KmsKeyID:        v.KmsKeyId,
+			}
+			if aws.StringValue(v.ThroughputMode) == efs.ThroughputModeProvisioned {
+				tmp.ProvisionedThroughput = v.ProvisionedThroughputInMibps
+			}
+
+			if err := tmp.getTags(ctx, c); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+			if err := tmp.getMountTargets(ctx, c); err != nil {
+				return nil, err
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_efs_file_system", len(res))
+
+		if out.NextMarker == nil {
+			break
+		}
+		opt.Marker = out.NextMarker
+	}
+
+	return &res, nil
+}
+
+// GetEFSFileSystems calls GetEFSFileSystemsWithContext with a background context
+func (c *AWSClient) GetEFSFileSystems() (*EFSFileSystems, error) {
+	return c.GetEFSFileSystemsWithContext(aws.BackgroundContext())
+}
+
+func (f *EFSFileSystems) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+		"BoolValue":        aws.BoolValue,
+		"float":            formatFloat,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{- $name := sanitizeName .FileSystemID }}
+    {{ resourceID "aws_efs_file_system" .FileSystemID }}
+    resource "aws_efs_file_system" "{{ $name }}" {
+      creation_token = "{{ .CreationToken }}"
+      performance_mode = "{{ .PerformanceMode }}"
+      {{- if .ThroughputMode }}
+      throughput_mode = "{{ .ThroughputMode }}"
+      {{- end }}
+      {{- if .ProvisionedThroughput }}
+      provisioned_throughput_in_mibps = {{ float .ProvisionedThroughput }}
+      {{- end }}
+      {{- if .Encrypted }}
+      encrypted = {{ BoolValue .Encrypted }}
+      {{- end }}
+      {{- if .KmsKeyID }}
+      kms_key_id = "{{ .KmsKeyID }}"
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- range .MountTargets }}
+
+    resource "aws_efs_mount_target" "{{ sanitizeName .MountTargetID }}" {
+      file_system_id = "${aws_efs_file_system.{{ $name }}.id}"
+      subnet_id = "{{ .SubnetID }}"
+      {{- if .IPAddress }}
+      ip_address = "{{ .IPAddress }}"
+      {{- end }}
+      {{- if .SecurityGroups }}
+      security_groups = [{{ StringValueSlice .SecurityGroups | joinstring "," }}]
+      {{- end }}
+    }
+    {{- end }}
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, f)
+}
+
+// WriteImports writes the terraform import commands of 'EFSFileSystems'
+func (f *EFSFileSystems) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_efs_file_system.{{ sanitizeName .FileSystemID }} {{ .FileSystemID }}
+{{ range .MountTargets -}}
+terraform import aws_efs_mount_target.{{ sanitizeName .MountTargetID }} {{ .MountTargetID }}
+{{ end }}{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, f)
+}
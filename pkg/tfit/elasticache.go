@@ -0,0 +1,416 @@
+package tfit
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+)
+
+//**************** ElastiCache Cluster ****************
+type ElastiCacheCluster struct {
+	ClusterID          *string
+	Engine             *string
+	EngineVersion      *string
+	NodeType           *string
+	NumCacheNodes      *int64
+	ParameterGroupName *string
+	SubnetGroupName    *string
+	SecurityGroupIDs   []*string
+	SecurityGroupNames []*string
+	Port               *int64
+	AvailabilityZone   *string
+	MaintenanceWindow  *string
+	CreateTime         *time.Time
+	Tags               *Tags
+
+	// Set when the cluster is a node of a replication group
+	ReplicationGroupID *string
+}
+
+type ElastiCacheClusters []*ElastiCacheCluster
+
+// getElastiCacheTags returns the tags of the cluster or replication group
+// resource, e.g. "cluster:my-cluster", ListTagsForResource takes its ARN
+func (c *AWSClient) getElastiCacheTags(ctx aws.Context, resource string) (*Tags, error) {
+	account, err := c.accountIDWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	arn := fmt.Sprintf("arn:%s:elasticache:%s:%s:%s", c.partition(), c.region, account, resource)
+	out, err := c.elasticacheconn.ListTagsForResourceWithContext(ctx, &elasticache.ListTagsForResourceInput{
+		ResourceName: aws.String(arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Tags{}
+	for _, v := range out.TagList {
+		if v != nil && v.Key != nil {
+			(*res)[*v.Key] = v.Value
+		}
+	}
+
+	return res, nil
+}
+
+func (e *ElastiCacheCluster) set(src *elasticache.CacheCluster) {
+	e.ClusterID = src.CacheClusterId
+	e.Engine = src.Engine
+	e.EngineVersion = src.EngineVersion
+	e.NodeType = src.CacheNodeType
+	e.NumCacheNodes = src.NumCacheNodes
+	if src.CacheParameterGroup != nil {
+		e.ParameterGroupName = src.CacheParameterGroup.CacheParameterGroupName
+	}
+	e.SubnetGroupName = src.CacheSubnetGroupName
+	e.AvailabilityZone = src.PreferredAvailabilityZone
+	e.MaintenanceWindow = src.PreferredMaintenanceWindow
+	e.ReplicationGroupID = src.ReplicationGroupId
+	e.CreateTime = src.CacheClusterCreateTime
+
+	for _, v := range src.SecurityGroups {
+		if v != nil && v.SecurityGroupId != nil {
+			e.SecurityGroupIDs = append(e.SecurityGroupIDs, v.SecurityGroupId)
+		}
+	}
+	for _, v := range src.CacheSecurityGroups {
+		if v != nil && v.CacheSecurityGroupName != nil {
+			e.SecurityGroupNames = append(e.SecurityGroupNames, v.CacheSecurityGroupName)
+		}
+	}
+	sortStrings(e.SecurityGroupIDs)
+	sortStrings(e.SecurityGroupNames)
+
+	// Memcached clusters have a configuration endpoint, Redis ones
+	// only the endpoints of their nodes
+	if src.ConfigurationEndpoint != nil {
+		e.Port = src.ConfigurationEndpoint.Port
+	} else if len(src.CacheNodes) > 0 && src.CacheNodes[0] != nil && src.CacheNodes[0].Endpoint != nil {
+		e.Port = src.CacheNodes[0].Endpoint.Port
+	}
+}
+
+// getCacheClusters returns every cache cluster, replication group
+// nodes included
+func (c *AWSClient) getCacheClusters(ctx aws.Context) (ElastiCacheClusters, error) {
+	var res ElastiCacheClusters
+
+	opt := &elasticache.DescribeCacheClustersInput{ShowCacheNodeInfo: aws.Bool(true)}
+	for {
+		out, err := c.elasticacheconn.DescribeCacheClustersWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.CacheClusters {
+			if v == nil {
+				continue
+			}
+			tmp := &ElastiCacheCluster{}
+			tmp.set(v)
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_elasticache_cluster", len(res))
+
+		if out.Marker == nil {
+			break
+		}
+		opt.Marker = out.Marker
+	}
+
+	return res, nil
+}
+
+// GetElastiCacheClustersWithContext returns the standalone cache clusters.
+// The nodes of a replication group belong to
+// GetElastiCacheReplicationGroupsWithContext
+func (c *AWSClient) GetElastiCacheClustersWithContext(ctx aws.Context) (*ElastiCacheClusters, error) {
+	clusters, err := c.getCacheClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res ElastiCacheClusters
+	for _, v := range clusters {
+		if v.ReplicationGroupID != nil || !c.wantID(v.ClusterID) || !c.wantCreated(v.CreateTime) {
+			continue
+		}
+
+		v.Tags, err = c.getElastiCacheTags(ctx, "cluster:"+aws.StringValue(v.ClusterID))
+		if err != nil {
+			return nil, err
+		}
+		if !c.matchTags(v.Tags) {
+			continue
+		}
+
+		res = append(res, v)
+	}
+
+	return &res, nil
+}
+
+// GetElastiCacheClusters calls GetElastiCacheClustersWithContext with a background context
+func (c *AWSClient) GetElastiCacheClusters() (*ElastiCacheClusters, error) {
+	return c.GetElastiCacheClustersWithContext(aws.BackgroundContext())
+}
+
+func (e *ElastiCacheClusters) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_elasticache_cluster" .ClusterID }}
+    resource "aws_elasticache_cluster" "{{ sanitizeName .ClusterID }}" {
+      cluster_id = "{{ .ClusterID }}"
+      engine = "{{ .Engine }}"
+      engine_version = "{{ .EngineVersion }}"
+      node_type = "{{ .NodeType }}"
+      num_cache_nodes = {{ .NumCacheNodes }}
+      {{- if .ParameterGroupName }}
+      parameter_group_name = "{{ .ParameterGroupName }}"
+      {{- end }}
+      {{- if .SubnetGroupName }}
+      subnet_group_name = "{{ .SubnetGroupName }}"
+      {{- end }}
+      {{- if .SecurityGroupIDs }}
+      security_group_ids = [{{ StringValueSlice .SecurityGroupIDs | joinstring "," }}]
+      {{- end }}
+      {{- if .SecurityGroupNames }}
+      security_group_names = [{{ StringValueSlice .SecurityGroupNames | joinstring "," }}]
+      {{- end }}
+      {{- if .Port }}
+      port = {{ .Port }}
+      {{- end }}
+      {{- if .AvailabilityZone }}
+      availability_zone = "{{ .AvailabilityZone }}"
+      {{- end }}
+      {{- if .MaintenanceWindow }}
+      maintenance_window = "{{ .MaintenanceWindow }}"
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, e)
+}
+
+// WriteImports writes the terraform import commands of 'ElastiCacheClusters'
+func (e *ElastiCacheClusters) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_elasticache_cluster.{{ sanitizeName .ClusterID }} {{ .ClusterID }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, e)
+}
+
+//**************** ElastiCache Replication Group ****************
+type ElastiCacheReplicationGroup struct {
+	ReplicationGroupID       *string
+	Description              *string
+	NodeType                 *string
+	AutomaticFailover        bool
+	AtRestEncryptionEnabled  *bool
+	TransitEncryptionEnabled *bool
+
+	// Without cluster mode
+	NumberCacheClusters int
+
+	// With cluster mode
+	ClusterEnabled       bool
+	NumNodeGroups        int
+	ReplicasPerNodeGroup int
+
+	Tags *Tags
+
+	// Settings of the member clusters, see ElastiCacheCluster
+	Member *ElastiCacheCluster
+}
+
+type ElastiCacheReplicationGroups []*ElastiCacheReplicationGroup
+
+func (c *AWSClient) GetElastiCacheReplicationGroupsWithContext(ctx aws.Context) (*ElastiCacheReplicationGroups, error) {
+	clusters, err := c.getCacheClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[string]*ElastiCacheCluster)
+	for _, v := range clusters {
+		members[aws.StringValue(v.ClusterID)] = v
+	}
+
+	var res ElastiCacheReplicationGroups
+	opt := &elasticache.DescribeReplicationGroupsInput{}
+	for {
+		out, err := c.elasticacheconn.DescribeReplicationGroupsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.ReplicationGroups {
+			if v == nil || !c.wantID(v.ReplicationGroupId) {
+				continue
+			}
+
+			// Replication groups have no creation time, the one of their
+			// first member stands for it
+			var member *ElastiCacheCluster
+			for _, id := range v.MemberClusters {
+				if m, ok := members[aws.StringValue(id)]; ok {
+					member = m
+					break
+				}
+			}
+			if member == nil {
+				member = &ElastiCacheCluster{}
+			}
+			if !c.wantCreated(member.CreateTime) {
+				continue
+			}
+
+			tags, err := c.getElastiCacheTags(ctx, "replicationgroup:"+aws.StringValue(v.ReplicationGroupId))
+			if err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tags) {
+				continue
+			}
+
+			tmp := &ElastiCacheReplicationGroup{
+				ReplicationGroupID:       v.ReplicationGroupId,
+				Description:              v.Description,
+				NodeType:                 v.CacheNodeType,
+				AtRestEncryptionEnabled:  v.AtRestEncryptionEnabled,
+				TransitEncryptionEnabled: v.TransitEncryptionEnabled,
+				NumberCacheClusters:      len(v.MemberClusters),
+				ClusterEnabled:           aws.BoolValue(v.ClusterEnabled),
+				NumNodeGroups:            len(v.NodeGroups),
+				Member:                   member,
+				Tags:                     tags,
+			}
+
+			switch aws.StringValue(v.AutomaticFailover) {
+			case elasticache.AutomaticFailoverStatusEnabled, elasticache.AutomaticFailoverStatusEnabling:
+				tmp.AutomaticFailover = true
+			}
+
+			if len(v.NodeGroups) > 0 && v.NodeGroups[0] != nil && len(v.NodeGroups[0].NodeGroupMembers) > 0 {
+				tmp.ReplicasPerNodeGroup = len(v.NodeGroups[0].NodeGroupMembers) - 1
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_elasticache_replication_group", len(res))
+
+		if out.Marker == nil {
+			break
+		}
+		opt.Marker = out.Marker
+	}
+
+	return &res, nil
+}
+
+// GetElastiCacheReplicationGroups calls GetElastiCacheReplicationGroupsWithContext with a background context
+func (c *AWSClient) GetElastiCacheReplicationGroups() (*ElastiCacheReplicationGroups, error) {
+	return c.GetElastiCacheReplicationGroupsWithContext(aws.BackgroundContext())
+}
+
+func (e *ElastiCacheReplicationGroups) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+		"BoolValue":        aws.BoolValue,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_elasticache_replication_group" .ReplicationGroupID }}
+    resource "aws_elasticache_replication_group" "{{ sanitizeName .ReplicationGroupID }}" {
+      replication_group_id = "{{ .ReplicationGroupID }}"
+      replication_group_description = {{ hclString .Description }}
+      node_type = "{{ .NodeType }}"
+      automatic_failover_enabled = {{ .AutomaticFailover }}
+      {{- if .ClusterEnabled }}
+      cluster_mode {
+        num_node_groups = {{ .NumNodeGroups }}
+        replicas_per_node_group = {{ .ReplicasPerNodeGroup }}
+      }
+      {{- else }}
+      number_cache_clusters = {{ .NumberCacheClusters }}
+      {{- end }}
+      {{- if .AtRestEncryptionEnabled }}
+      at_rest_encryption_enabled = {{ BoolValue .AtRestEncryptionEnabled }}
+      {{- end }}
+      {{- if .TransitEncryptionEnabled }}
+      transit_encryption_enabled = {{ BoolValue .TransitEncryptionEnabled }}
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+      {{- with .Member }}
+      {{- if .EngineVersion }}
+      engine_version = "{{ .EngineVersion }}"
+      {{- end }}
+      {{- if .ParameterGroupName }}
+      parameter_group_name = "{{ .ParameterGroupName }}"
+      {{- end }}
+      {{- if .SubnetGroupName }}
+      subnet_group_name = "{{ .SubnetGroupName }}"
+      {{- end }}
+      {{- if .SecurityGroupIDs }}
+      security_group_ids = [{{ StringValueSlice .SecurityGroupIDs | joinstring "," }}]
+      {{- end }}
+      {{- if .SecurityGroupNames }}
+      security_group_names = [{{ StringValueSlice .SecurityGroupNames | joinstring "," }}]
+      {{- end }}
+      {{- if .Port }}
+      port = {{ .Port }}
+      {{- end }}
+      {{- if .MaintenanceWindow }}
+      maintenance_window = "{{ .MaintenanceWindow }}"
+      {{- end }}
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, e)
+}
+
+// WriteImports writes the terraform import commands of 'ElastiCacheReplicationGroups'
+func (e *ElastiCacheReplicationGroups) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_elasticache_replication_group.{{ sanitizeName .ReplicationGroupID }} {{ .ReplicationGroupID }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, e)
+}
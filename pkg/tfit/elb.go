@@ -55,6 +55,9 @@ type ELBs []*ELB
 func (e *ELB) setInstances(src []*elb.Instance) {
 	if src != nil {
 		for _, v := range src {
+			if v == nil {
+				continue
+			}
 			e.Instances = append(e.Instances, v.InstanceId)
 		}
 	}
@@ -63,6 +66,9 @@ func (e *ELB) setInstances(src []*elb.Instance) {
 func (e *ELB) setListener(src []*elb.ListenerDescription) {
 	if src != nil {
 		for _, v := range src {
+			if v == nil || v.Listener == nil {
+				continue
+			}
 			tmp := ELBListerner{
 				InstancePort:         v.Listener.InstancePort,
 				InstanceProtocol:     v.Listener.InstanceProtocol,
@@ -108,7 +114,7 @@ func (e *ELB) setAccessLog(src *elb.AccessLog) {
 	}
 }
 
-func (e *ELB) setELBAttributes(src *elb.LoadBalancerDescription, c *AWSClient) error {
+func (e *ELB) setELBAttributes(ctx aws.Context, src *elb.LoadBalancerDescription, c *AWSClient) error {
 	e.setInstances(src.Instances)
 	e.setHealthCheck(src.HealthCheck)
 
@@ -122,37 +128,42 @@ func (e *ELB) setELBAttributes(src *elb.LoadBalancerDescription, c *AWSClient) e
 	e.setListener(src.ListenerDescriptions)
 
 	opt := elb.DescribeLoadBalancerAttributesInput{LoadBalancerName: e.Name}
-	data, err := c.elbconn.DescribeLoadBalancerAttributes(&opt)
+	data, err := c.elbconn.DescribeLoadBalancerAttributesWithContext(ctx, &opt)
 	if err != nil {
 		return err
 	}
-	e.setAccessLog(data.LoadBalancerAttributes.AccessLog)
+	if attrs := data.LoadBalancerAttributes; attrs != nil {
+		e.setAccessLog(attrs.AccessLog)
 
-	if data.LoadBalancerAttributes.ConnectionDraining != nil {
-		e.ConnectionDraining = data.LoadBalancerAttributes.ConnectionDraining.Enabled
-		e.ConnectionDrainingTimeOut = data.LoadBalancerAttributes.ConnectionDraining.Timeout
-	}
+		if attrs.ConnectionDraining != nil {
+			e.ConnectionDraining = attrs.ConnectionDraining.Enabled
+			e.ConnectionDrainingTimeOut = attrs.ConnectionDraining.Timeout
+		}
 
-	if data.LoadBalancerAttributes.ConnectionSettings != nil {
-		e.IdleTimeout = data.LoadBalancerAttributes.ConnectionSettings.IdleTimeout
-	}
+		if attrs.ConnectionSettings != nil {
+			e.IdleTimeout = attrs.ConnectionSettings.IdleTimeout
+		}
 
-	if data.LoadBalancerAttributes.CrossZoneLoadBalancing != nil {
-		e.CrossZoneLoadBalancing = data.LoadBalancerAttributes.CrossZoneLoadBalancing.Enabled
+		if attrs.CrossZoneLoadBalancing != nil {
+			e.CrossZoneLoadBalancing = attrs.CrossZoneLoadBalancing.Enabled
+		}
 	}
 
 	// Get Tags
 	describeTagsOpt := elb.DescribeTagsInput{
 		LoadBalancerNames: []*string{e.Name},
 	}
-	tagsOutput, err := c.elbconn.DescribeTags(&describeTagsOpt)
+	tagsOutput, err := c.elbconn.DescribeTagsWithContext(ctx, &describeTagsOpt)
 	if err != nil {
 		return err
 	}
 
-	if len(tagsOutput.TagDescriptions) > 0 && len(tagsOutput.TagDescriptions[0].Tags) > 0 {
+	if len(tagsOutput.TagDescriptions) > 0 && tagsOutput.TagDescriptions[0] != nil && len(tagsOutput.TagDescriptions[0].Tags) > 0 {
 		e.Tags = make(map[string]*string)
 		for _, t := range tagsOutput.TagDescriptions[0].Tags {
+			if t == nil || t.Key == nil {
+				continue
+			}
 			e.Tags[aws.StringValue(t.Key)] = t.Value
 		}
 	}
@@ -160,16 +171,19 @@ func (e *ELB) setELBAttributes(src *elb.LoadBalancerDescription, c *AWSClient) e
 	return nil
 }
 
-func (c *AWSClient) ListELBs() (*ELBs, error) {
+func (c *AWSClient) ListELBsWithContext(ctx aws.Context) (*ELBs, error) {
 	opt := elb.DescribeLoadBalancersInput{}
 	var output ELBs
 	for {
-		data, err := c.elbconn.DescribeLoadBalancers(&opt)
+		data, err := c.elbconn.DescribeLoadBalancersWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, v := range data.LoadBalancerDescriptions {
+			if v == nil || !c.wantID(v.LoadBalancerName) {
+				continue
+			}
 			tmp := ELB{
 				Name:              v.LoadBalancerName,
 				AvailabilityZones: v.AvailabilityZones,
@@ -177,14 +191,19 @@ func (c *AWSClient) ListELBs() (*ELBs, error) {
 				Subnets:           v.Subnets,
 			}
 
-			err := tmp.setELBAttributes(v, c)
+			err := tmp.setELBAttributes(ctx, v, c)
 			if err != nil {
 				return nil, err
 			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
 			output = append(output, &tmp)
 
 		}
 
+		c.progress("aws_elb", len(output))
+
 		if data.NextMarker != nil {
 			opt.Marker = data.NextMarker
 		} else {
@@ -196,6 +215,11 @@ func (c *AWSClient) ListELBs() (*ELBs, error) {
 
 }
 
+// ListELBs calls ListELBsWithContext with a background context
+func (c *AWSClient) ListELBs() (*ELBs, error) {
+	return c.ListELBsWithContext(aws.BackgroundContext())
+}
+
 func (elb *ELBs) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"joinstring":       joinStringSlice,
@@ -205,7 +229,8 @@ func (elb *ELBs) WriteHCL(w io.Writer) error {
 	tmpl := `
 	{{ if . }}
 		{{ range . }}
-	resource "aws_elb" "{{ .Name }}" {
+	{{ resourceID "aws_elb" .Name }}
+	resource "aws_elb" "{{ sanitizeName .Name }}" {
     name = "{{ .Name }}"
 
     {{- if .AvailabilityZones }}
@@ -285,10 +310,11 @@ func (elb *ELBs) WriteHCL(w io.Writer) error {
       {{- end }}
     {{- end }}
 
-    {{- if .Tags }}
+    {{- $tags := tags .Tags }}
+    {{- if $tags }}
       tags {
-        {{- range $k, $v := .Tags }}
-        "{{ $k }}" = "{{ $v }}"
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
         {{- end }}
       }
     {{- end }}
@@ -301,3 +327,13 @@ func (elb *ELBs) WriteHCL(w io.Writer) error {
 	return renderHCL(w, tmpl, funcMap, elb)
 
 }
+
+// WriteImports writes the terraform import commands of 'ELBs'
+func (elb *ELBs) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_elb.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, elb)
+}
@@ -0,0 +1,144 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/glacier"
+)
+
+//**************** Glacier Vault ****************
+type GlacierVault struct {
+	Name         *string
+	ARN          *string
+	AccessPolicy *string
+	Tags         *Tags
+}
+
+type GlacierVaults []*GlacierVault
+
+func (v *GlacierVault) getAccessPolicy(ctx aws.Context, c *AWSClient) error {
+	output, err := c.glacierconn.GetVaultAccessPolicyWithContext(ctx, &glacier.GetVaultAccessPolicyInput{
+		AccountId: aws.String("-"),
+		VaultName: v.Name,
+	})
+	// A vault without an access policy is reported as not found
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == glacier.ErrCodeResourceNotFoundException {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if output.Policy != nil {
+		v.AccessPolicy = output.Policy.Policy
+	}
+
+	return nil
+}
+
+func (v *GlacierVault) getTags(ctx aws.Context, c *AWSClient) error {
+	output, err := c.glacierconn.ListTagsForVaultWithContext(ctx, &glacier.ListTagsForVaultInput{
+		AccountId: aws.String("-"),
+		VaultName: v.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	v.Tags = &Tags{}
+	for k, tag := range output.Tags {
+		(*v.Tags)[k] = tag
+	}
+
+	return nil
+}
+
+// GetGlacierVaultsWithContext returns the vaults of the account of the
+// credentials ("-")
+func (c *AWSClient) GetGlacierVaultsWithContext(ctx aws.Context) (*GlacierVaults, error) {
+	var res GlacierVaults
+
+	opt := &glacier.ListVaultsInput{AccountId: aws.String("-")}
+	for {
+		out, err := c.glacierconn.ListVaultsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.VaultList {
+			if v == nil || !c.wantID(v.VaultName, v.VaultARN) {
+				continue
+			}
+
+			tmp := &GlacierVault{Name: v.VaultName, ARN: v.VaultARN}
+			if err := tmp.getTags(ctx, c); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+			if err := tmp.getAccessPolicy(ctx, c); err != nil {
+				return nil, err
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_glacier_vault", len(res))
+
+		if out.Marker == nil {
+			break
+		}
+		opt.Marker = out.Marker
+	}
+
+	return &res, nil
+}
+
+// GetGlacierVaults calls GetGlacierVaultsWithContext with a background context
+func (c *AWSClient) GetGlacierVaults() (*GlacierVaults, error) {
+	return c.GetGlacierVaultsWithContext(aws.BackgroundContext())
+}
+
+func (g *GlacierVaults) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_glacier_vault" .Name }}
+    resource "aws_glacier_vault" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      {{- if .AccessPolicy }}
+      {{ jsonWarning .AccessPolicy }}
+      access_policy = <<EOF
+      {{ jsonDocument .AccessPolicy }}
+EOF
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, g)
+}
+
+// WriteImports writes the terraform import commands of 'GlacierVaults'
+func (g *GlacierVaults) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_glacier_vault.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, g)
+}
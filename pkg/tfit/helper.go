@@ -2,17 +2,22 @@ package tfit
 
 import (
 	"bytes"
+	"crypto/sha1"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/sts"
@@ -32,22 +37,124 @@ type chanItem struct {
 
 type Tags map[string]*string
 
+// RenderOptions holds settings shared by every WriteHCL
+type RenderOptions struct {
+	// ExtraTags are added to the tags of every taggable resource.
+	// Tags already carried by the resource are never overwritten
+	ExtraTags map[string]string
+
+	// CommentID emits a "# tfit-id: <hash>" comment above every resource.
+	// The hash only depends on the resource type and its AWS ID
+	CommentID bool
+
+	// DataSources rewrites the AccountID & Region segments of ARNs to the
+	// aws_caller_identity & aws_region data sources (see WriteDataSources).
+	// Region is the one of the provider, S3 buckets of other regions are
+	// flagged with a warning unless Regions binds them to their provider
+	DataSources bool
+	AccountID   string
+	Region      string
+
+	// HCL2 renders the Terraform 0.12+ syntax. The only map arguments the
+	// templates render, tags (S3 filter ones included) & alarm dimensions,
+	// become attributes ("tags = {}") rather than blocks ("tags {}"). The
+	// rest of the output is valid for both versions, e.g. the quoted
+	// ignore_changes lists 0.12+ still accept
+	HCL2 bool
+
+	// TFVersion is the Terraform version targeted, "0.11" when empty. It
+	// is set with SetTFVersion and checked with TFVersionAtLeast
+	TFVersion string
+
+	// ProviderV4 follows the AWS provider v4+ conventions, e.g. S3 bucket
+	// logging, versioning, encryption & lifecycle become separate resources
+	ProviderV4 bool
+
+	// SecretPlaceholders emits an aws_secretsmanager_secret_version with a
+	// placeholder value for every secret, the values are never exported
+	SecretPlaceholders bool
+
+	// LabelPrefix is prepended to every resource label and Provider, when
+	// set, is rendered as the provider of every resource. Both are used
+	// to export several regions into the same configuration
+	LabelPrefix string
+	Provider    string
+
+	// Regions maps the regions exported apart to their scope: resources
+	// of a region are labeled "<scope>_..." and bound to "aws.<scope>".
+	// GlobalLabelPrefix is the LabelPrefix of the global resources (IAM,
+	// S3, ...), exported once for all regions. Both let regional & global
+	// resources reference each other, and S3 buckets, global but living
+	// in a region, be bound to the provider of their region
+	Regions           map[string]string
+	GlobalLabelPrefix string
+
+	// PostCollect is invoked by RunPostCollect once all resources are
+	// collected and before any of them is rendered. It may mutate the
+	// snapshot, e.g. to add tags from a CMDB or to drop resources which
+	// should not be exported
+	PostCollect func(snapshot *Snapshot) error
+
+	// References maps AWS IDs to the address of the exported resources,
+	// see ReferenceMap. The reference attributes (vpc_id, subnet_id, ...)
+	// holding one of these IDs are rendered as references
+	References map[string]string
+}
+
+// RenderOpts is used by every WriteHCL, set it before rendering
+var RenderOpts = &RenderOptions{}
+
 func (t *Tags) setTags(src []*ec2.Tag) {
 	for _, v := range src {
+		if v == nil || v.Key == nil {
+			continue
+		}
 		map[string]*string(*t)[*v.Key] = v.Value
 	}
 }
 
-func (c *Config) GetAccountId() (*string, error) {
-	creds := GetCredentials(c)
-	sess, err := session.NewSession(&aws.Config{Credentials: creds})
+// safeString returns the value src points to, or def when src is nil.
+// AWS responses are sparse, so collectors should read optional fields
+// through these helpers instead of dereferencing them directly
+func safeString(src *string, def string) string {
+	if src == nil {
+		return def
+	}
+
+	return *src
+}
+
+// safeBool returns the value src points to, or def when src is nil
+func safeBool(src *bool, def bool) bool {
+	if src == nil {
+		return def
+	}
+
+	return *src
+}
+
+// safeInt64 returns the value src points to, or def when src is nil
+func safeInt64(src *int64, def int64) int64 {
+	if src == nil {
+		return def
+	}
+
+	return *src
+}
+
+func (c *Config) GetAccountIdWithContext(ctx aws.Context) (*string, error) {
+	creds, err := GetCredentials(c)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := session.NewSession(c.awsConfig(creds))
 	if err != nil {
 		return nil, fmt.Errorf("Error creating AWS session: %s", err)
 	}
 
 	stsconn := sts.New(sess, aws.NewConfig().WithRegion(c.Region))
 
-	output, err := stsconn.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	output, err := stsconn.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
 	if err != nil {
 		return nil, fmt.Errorf("Error calling GetCallerIdentity: %s", err)
 	}
@@ -55,6 +162,11 @@ func (c *Config) GetAccountId() (*string, error) {
 	return output.Account, nil
 }
 
+// GetAccountId calls GetAccountIdWithContext with a background context
+func (c *Config) GetAccountId() (*string, error) {
+	return c.GetAccountIdWithContext(aws.BackgroundContext())
+}
+
 func handleError(err error) error {
 	if awsErr, ok := err.(awserr.Error); ok {
 		switch awsErr.Code() {
@@ -70,6 +182,10 @@ func handleError(err error) error {
 			return nil
 		case "NoSuchCORSConfiguration":
 			return nil
+		case "NoSuchTagSet":
+			return nil
+		case "LifecyclePolicyNotFoundException":
+			return nil
 		default:
 			return err
 		}
@@ -78,7 +194,38 @@ func handleError(err error) error {
 	return err
 }
 
-func GetCredentials(c *Config) *credentials.Credentials {
+// MultiError gathers the errors of the resources which could not be
+// fetched with AWSClient.ContinueOnError. It is returned along with the
+// resources which were
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for k, v := range e.Errors {
+		msgs[k] = v.Error()
+	}
+
+	return fmt.Sprintf("%d resources failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// add records the failure of the resource id
+func (e *MultiError) add(id *string, err error) {
+	e.Errors = append(e.Errors, fmt.Errorf("%s: %v", aws.StringValue(id), err))
+}
+
+// errorOrNil returns e when an error was recorded, a nil error otherwise
+func (e *MultiError) errorOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// GetCredentials returns the credentials of c, the ones of the role
+// assumed when RoleARN is set
+func GetCredentials(c *Config) (*credentials.Credentials, error) {
 	providers := []credentials.Provider{
 		&credentials.StaticProvider{Value: credentials.Value{
 			AccessKeyID:     c.AccessKey,
@@ -92,7 +239,50 @@ func GetCredentials(c *Config) *credentials.Credentials {
 		},
 	}
 
-	return credentials.NewChainCredentials(providers)
+	creds := credentials.NewChainCredentials(providers)
+	if len(c.RoleARN) == 0 {
+		return creds, nil
+	}
+
+	return assumeRole(c, creds)
+}
+
+// assumedRoles caches the credentials of the assumed roles, so that the
+// clients of a Config share them and an MFA token is asked only once
+var assumedRoles = struct {
+	sync.Mutex
+	creds map[string]*credentials.Credentials
+}{creds: make(map[string]*credentials.Credentials)}
+
+// assumeRole returns the credentials of c.RoleARN, assumed with creds
+func assumeRole(c *Config, creds *credentials.Credentials) (*credentials.Credentials, error) {
+	key := strings.Join([]string{c.AccessKey, c.CredsFile, c.Profile, c.RoleARN, c.ExternalID, c.MFASerial, c.Endpoint}, "|")
+
+	assumedRoles.Lock()
+	defer assumedRoles.Unlock()
+
+	if res, ok := assumedRoles.creds[key]; ok {
+		return res, nil
+	}
+
+	cfg := c.awsConfig(creds).WithRegion(c.Region)
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating AWS session: %s", err)
+	}
+
+	res := stscreds.NewCredentialsWithClient(sts.New(sess), c.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+		if len(c.ExternalID) > 0 {
+			p.ExternalID = aws.String(c.ExternalID)
+		}
+		if len(c.MFASerial) > 0 {
+			p.SerialNumber = aws.String(c.MFASerial)
+			p.TokenProvider = c.TokenProvider
+		}
+	})
+	assumedRoles.creds[key] = res
+
+	return res, nil
 }
 
 func makeTerraformResourceName(src *string) string {
@@ -104,6 +294,84 @@ func makeTerraformResourceName(src *string) string {
 	return output
 }
 
+// arnName returns the last segment of the resource of an ARN, e.g.
+// the name of a policy, as a Terraform resource name
+func arnName(arn *string) string {
+	tokens := strings.Split(aws.StringValue(arn), "/")
+	return makeTerraformResourceName(aws.String(tokens[len(tokens)-1]))
+}
+
+var illegalNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// sanitizeName returns the first non empty name (string or *string),
+// prefixed by RenderOpts.LabelPrefix, as a valid Terraform resource name:
+// illegal characters are replaced by "_" and a name which does not start
+// with a letter or "_" is prefixed by "r".
+// Pass the AWS ID last so that the name is never empty
+func sanitizeName(names ...interface{}) string {
+	return prefixedName(RenderOpts.LabelPrefix, names...)
+}
+
+// globalName is sanitizeName for the references to global resources,
+// prefixed by RenderOpts.GlobalLabelPrefix when regions are exported apart
+func globalName(names ...interface{}) string {
+	if RenderOpts.Regions == nil {
+		return sanitizeName(names...)
+	}
+	return prefixedName(RenderOpts.GlobalLabelPrefix, names...)
+}
+
+// regionName is sanitizeName for the references to the resources of
+// region, prefixed by its scope when the regions are exported apart
+func regionName(region *string, names ...interface{}) string {
+	if scope, ok := RenderOpts.Regions[aws.StringValue(region)]; ok {
+		return prefixedName(scope+"_", names...)
+	}
+	return sanitizeName(names...)
+}
+
+// regionProvider returns the provider of the resources of region, when
+// the regions are exported apart
+func regionProvider(region *string) string {
+	if scope, ok := RenderOpts.Regions[aws.StringValue(region)]; ok {
+		return "aws." + scope
+	}
+	return ""
+}
+
+// otherRegion reports whether region is known and is not the one of the
+// provider, nor of a provider of RenderOpts.Regions
+func otherRegion(region *string) bool {
+	if region == nil || len(RenderOpts.Region) == 0 || *region == RenderOpts.Region {
+		return false
+	}
+	_, ok := RenderOpts.Regions[*region]
+	return !ok
+}
+
+func prefixedName(prefix string, names ...interface{}) string {
+	for _, v := range names {
+		var name string
+		switch n := v.(type) {
+		case string:
+			name = n
+		case *string:
+			name = safeString(n, "")
+		}
+		if len(name) == 0 {
+			continue
+		}
+
+		name = illegalNameChars.ReplaceAllString(prefix+name, "_")
+		if c := name[0]; c != '_' && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') {
+			name = "r" + name
+		}
+		return name
+	}
+
+	return ""
+}
+
 func getZoneId(src *string) *string {
 	if strings.Contains(aws.StringValue(src), "/") {
 		tokens := strings.Split(aws.StringValue(src), "/")
@@ -125,18 +393,39 @@ func quote(src string) string {
 func makeTerraformList(src []*string) string {
 	var tmp []string
 	for _, v := range src {
-		tmp = append(tmp, strconv.Quote(*v))
+		tmp = append(tmp, strconv.Quote(aws.StringValue(v)))
 	}
 
 	return strings.Join(tmp, ",")
 }
 
+// sortStrings sorts src in place by value, nil first
+func sortStrings(src []*string) {
+	sort.Slice(src, func(i, j int) bool {
+		return aws.StringValue(src[i]) < aws.StringValue(src[j])
+	})
+}
+
+// containsString reports whether s is an element of src
+func containsString(src []string, s string) bool {
+	for _, v := range src {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// joinStringSlice quotes every element of src and joins them with sep.
+// src is left untouched
 func joinStringSlice(sep string, src []string) string {
+	quoted := make([]string, len(src))
 	for k, v := range src {
-		src[k] = quote(v)
+		quoted[k] = quote(v)
 	}
 
-	return strings.Join(src, sep)
+	return strings.Join(quoted, sep)
 }
 
 // HCLFmt read HCL formatted text from io.Reader
@@ -156,19 +445,147 @@ func HCLFmt(r io.Reader, w io.Writer) error {
 	return printer.Fprint(w, hclFile.Node)
 }
 
-func prettyJSON(src *string) string {
-	var data map[string]interface{}
-	buf := bytes.NewBufferString(aws.StringValue(src))
-	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
-		panic(err)
+// Validate parses the HCL read from r. A parse error is reported with
+// the resource it occurred in and the offending lines
+func Validate(r io.Reader) error {
+	src := bytes.NewBuffer(nil)
+	if _, err := src.ReadFrom(r); err != nil {
+		return err
+	}
+
+	_, err := parser.Parse(src.Bytes())
+	if err == nil {
+		return nil
+	}
+
+	posErr, ok := err.(*parser.PosError)
+	if !ok || posErr.Pos.Line < 1 {
+		return err
+	}
+
+	lines := strings.Split(src.String(), "\n")
+	line := posErr.Pos.Line - 1
+	if line >= len(lines) {
+		line = len(lines) - 1
+	}
+
+	name := "unknown resource"
+	for n := line; n >= 0; n-- {
+		if m := resourceNameRegexp.FindStringSubmatch(lines[n]); m != nil {
+			name = m[1] + "." + m[2]
+			break
+		}
+	}
+
+	var snippet []string
+	for n := line - 2; n <= line+2; n++ {
+		if n < 0 || n >= len(lines) {
+			continue
+		}
+		marker := "  "
+		if n == line {
+			marker = "> "
+		}
+		snippet = append(snippet, fmt.Sprintf("%s%4d | %s", marker, n+1, lines[n]))
+	}
+
+	return fmt.Errorf("%s: %s\n%s", name, err, strings.Join(snippet, "\n"))
+}
+
+// Label of a resource, or of a data source
+var resourceNameRegexp = regexp.MustCompile(`^\s*(?:resource|data)\s+"([^"]+)"\s+"([^"]+)"`)
+
+// prettyJSON indents the JSON document src
+func prettyJSON(src *string) (string, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(aws.StringValue(src)), &data); err != nil {
+		return "", err
 	}
+
 	b, err := json.MarshalIndent(data, "", " ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// heredoc returns src, which must end with a newline, as a heredoc.
+// Interpolations & HCL2 directives are escaped and the delimiter is chosen not to
+// collide with a line of src
+func heredoc(src *string) string {
+	doc := escapeTemplates(aws.StringValue(src))
+
+	marker := "EOF"
+	for n := 0; strings.Contains("\n"+doc, "\n"+marker+"\n"); n++ {
+		marker = fmt.Sprintf("EOF%d", n)
+	}
+
+	return "<<" + marker + "\n" + doc + marker
+}
+
+// commentLines returns src as HCL comment lines
+func commentLines(src *string) string {
+	lines := strings.Split(strings.TrimSuffix(aws.StringValue(src), "\n"), "\n")
+	for n, v := range lines {
+		lines[n] = strings.TrimRight("# "+v, " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// hclEscaper escapes the characters which can not appear as is in a
+// quoted HCL string
+var hclEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// hclString returns src, a string or *string, as a quoted HCL string.
+// Like heredoc, it escapes interpolations & HCL2 directives
+func hclString(src interface{}) string {
+	var str string
+	switch v := src.(type) {
+	case string:
+		str = v
+	case *string:
+		str = aws.StringValue(v)
+	default:
+		str = fmt.Sprint(v)
+	}
+
+	return `"` + escapeTemplates(hclEscaper.Replace(str)) + `"`
+}
+
+// escapeTemplates escapes the interpolations, and the HCL2 directives, of
+// src so that Terraform renders them as is, e.g. the ${aws:username}
+// variables of IAM policies
+func escapeTemplates(src string) string {
+	src = strings.Replace(src, "${", "$${", -1)
+	if RenderOpts.HCL2 {
+		src = strings.Replace(src, "%{", "%%{", -1)
+	}
 
+	return src
+}
+
+// jsonDocument is prettyJSON for the templates, the document being
+// rendered into a heredoc its interpolations are escaped. A document which
+// is not valid JSON is rendered as is, jsonWarning flags it
+func jsonDocument(src *string) string {
+	doc, err := prettyJSON(src)
 	if err != nil {
-		panic(err)
+		return escapeTemplates(aws.StringValue(src))
 	}
 
-	return string(b)
+	return escapeTemplates(doc)
+}
+
+// jsonWarning returns a comment to render above a document
+// which jsonDocument could not indent
+func jsonWarning(src *string) string {
+	if _, err := prettyJSON(src); err != nil {
+		return fmt.Sprintf("# WARNING: invalid JSON document rendered as is (%s)", err)
+	}
+
+	return ""
 }
 
 func unEscapeHTML(src *string) (string, error) {
@@ -182,11 +599,435 @@ func doHCLRendering(w io.Writer, t *template.Template, target interface{}) error
 		return err
 	}
 
+	if len(RenderOpts.References) > 0 {
+		buf = bytes.NewBufferString(linkReferences(buf.String()))
+	}
+
+	if RenderOpts.DataSources {
+		buf = bytes.NewBufferString(portableARNs(buf.String()))
+	}
+
+	if len(RenderOpts.Provider) > 0 {
+		// Resources bound to a provider by their template keep it
+		buf = bytes.NewBufferString(resourceRegexp.ReplaceAllStringFunc(buf.String(), func(header string) string {
+			if providerRegexp.MatchString(header) {
+				return header
+			}
+			return fmt.Sprintf("%s\n  provider = %q", header, RenderOpts.Provider)
+		}))
+	}
+
+	if RenderOpts.HCL2 {
+		buf = bytes.NewBufferString(mapBlockRegexp.ReplaceAllString(buf.String(), "${1} = {${2}"))
+	}
+
+	if err := Validate(bytes.NewReader(buf.Bytes())); err != nil {
+		return err
+	}
+
 	return HCLFmt(buf, w)
 }
 
+// First line of every resource, with its provider when it comes first
+var resourceRegexp = regexp.MustCompile(`(?m)^(\s*resource\s+"[^"]+"\s+"[^"]+"\s*\{)(\s*provider\s*=)?`)
+
+var providerRegexp = regexp.MustCompile(`provider\s*=$`)
+
+// resource "type" "label" {
+var resourceHeaderRegexp = regexp.MustCompile(`^\s*resource\s+"([^"]+)"\s+"([^"]+)"`)
+
+// Quoted IDs of the resources which may be referenced
+var referenceIDRegexp = regexp.MustCompile(`"((vpc|subnet|sg|rtb|acl|pcx|vpce|dopt|eni|tgw|vgw|cgw|vpn|i)-[0-9a-f]+)"`)
+
+// Resource types, by ID prefix, whose "id" attribute is the AWS ID
+var referenceTypes = map[string]string{
+	"vpc":    "aws_vpc",
+	"subnet": "aws_subnet",
+	"sg":     "aws_security_group",
+	"rtb":    "aws_route_table",
+	"acl":    "aws_network_acl",
+	"pcx":    "aws_vpc_peering_connection",
+	"vpce":   "aws_vpc_endpoint",
+	"dopt":   "aws_vpc_dhcp_options",
+	"eni":    "aws_network_interface",
+	"tgw":    "aws_ec2_transit_gateway",
+	"vgw":    "aws_vpn_gateway",
+	"cgw":    "aws_customer_gateway",
+	"vpn":    "aws_vpn_connection",
+	"i":      "aws_instance",
+}
+
+// Attributes of the templates holding the IDs of other resources
+var referenceAttrRegexp = regexp.MustCompile(`^\s*(vpc_id|peer_vpc_id|vpc_classic_link_id|subnet_id|subnet_ids|subnets|vpc_zone_identifier|` +
+	`security_groups|security_group_ids|vpc_security_group_ids|vpc_classic_link_security_groups|route_table_id|route_table_ids|` +
+	`network_acl_id|network_interface_id|instance_id|instance|instances|vpc_peering_connection_id|dhcp_options_id|transit_gateway_id|gateway_id|vpn_gateway_id|customer_gateway_id|vpn_connection_id)\s*=`)
+
+// ReferenceMap returns the address of the resources imported by the
+// "terraform import" commands of imports (see WriteImports) by AWS ID.
+// Only the resource types of referenceTypes are kept
+func ReferenceMap(imports string) map[string]string {
+	res := make(map[string]string)
+	for _, line := range strings.Split(imports, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 || fields[0] != "terraform" || fields[1] != "import" {
+			continue
+		}
+
+		address, id := fields[2], fields[3]
+		m := referenceIDRegexp.FindStringSubmatch(`"` + id + `"`)
+		if m == nil || m[1] != id || !strings.HasPrefix(address, referenceTypes[m[2]]+".") {
+			continue
+		}
+		res[id] = address
+	}
+
+	return res
+}
+
+// linkReferences rewrites the IDs held by the reference attributes of src
+// into references to the resources of RenderOpts.References. IDs of
+// resources which were not exported are left as is, and a resource never
+// references itself, e.g. a security group allowing its own traffic.
+// The inline rules of security groups are left as is too, groups
+// allowing each other would be a dependency cycle
+func linkReferences(src string) string {
+	lines := strings.Split(src, "\n")
+	current, currentType := "", ""
+	for i, line := range lines {
+		if m := resourceHeaderRegexp.FindStringSubmatch(line); m != nil {
+			current, currentType = m[1]+"."+m[2], m[1]
+			continue
+		}
+		m := referenceAttrRegexp.FindStringSubmatch(line)
+		if m == nil || (currentType == "aws_security_group" && m[1] == "security_groups") {
+			continue
+		}
+
+		lines[i] = referenceIDRegexp.ReplaceAllStringFunc(line, func(quoted string) string {
+			address, ok := RenderOpts.References[strings.Trim(quoted, `"`)]
+			if !ok || address == current {
+				return quoted
+			}
+			return fmt.Sprintf(`"${%s.id}"`, address)
+		})
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Map arguments written as blocks by the templates. Their keys are always
+// quoted, which tells them apart from real blocks such as the dimensions
+// of an auto scaling policy metric. A map argument added to a template
+// has to be listed here for --hcl2 to rewrite it
+var mapBlockRegexp = regexp.MustCompile(`(?m)^(\s*(?:tags|dimensions))\s*\{(\s*["}])`)
+
+// arn:partition:service:region:account-id:
+var arnRegexp = regexp.MustCompile(`arn:(aws[a-z-]*):([a-z0-9-]+):([a-z0-9-]*):([0-9]*):`)
+
+// portableARNs replaces the region & account ID of every ARN in src,
+// when they match RenderOpts, by the data sources of WriteDataSources
+func portableARNs(src string) string {
+	return arnRegexp.ReplaceAllStringFunc(src, func(arn string) string {
+		m := arnRegexp.FindStringSubmatch(arn)
+		region, account := m[3], m[4]
+		if len(region) > 0 && region == RenderOpts.Region {
+			region = "${data.aws_region.current.name}"
+		}
+		if len(account) > 0 && account == RenderOpts.AccountID {
+			account = "${data.aws_caller_identity.current.account_id}"
+		}
+
+		return fmt.Sprintf("arn:%s:%s:%s:%s:", m[1], m[2], region, account)
+	})
+}
+
+// defaultTFVersion is the Terraform version the templates target when
+// RenderOpts.TFVersion is empty
+const defaultTFVersion = "0.11"
+
+// parseTFVersion returns the major & minor numbers of a Terraform version,
+// e.g. "0.12", "v0.13.7" or "1.5". The patch number is ignored
+func parseTFVersion(version string) (major, minor int, err error) {
+	tokens := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(tokens) < 2 {
+		return 0, 0, fmt.Errorf("invalid Terraform version %q, expected <major>.<minor>, e.g. 0.12", version)
+	}
+
+	if major, err = strconv.Atoi(tokens[0]); err != nil {
+		return 0, 0, fmt.Errorf("invalid Terraform version %q, expected <major>.<minor>, e.g. 0.12", version)
+	}
+	if minor, err = strconv.Atoi(tokens[1]); err != nil {
+		return 0, 0, fmt.Errorf("invalid Terraform version %q, expected <major>.<minor>, e.g. 0.12", version)
+	}
+
+	return major, minor, nil
+}
+
+// SetTFVersion checks and sets the Terraform version targeted. From 0.12
+// the HCL2 syntax is rendered
+func (o *RenderOptions) SetTFVersion(version string) error {
+	if _, _, err := parseTFVersion(version); err != nil {
+		return err
+	}
+
+	o.TFVersion = version
+	if TFVersionAtLeast("0.12") {
+		o.HCL2 = true
+	}
+	return nil
+}
+
+// TFVersionAtLeast reports whether the Terraform version targeted is
+// version or a later one. Templates call it as tfVersionAtLeast, e.g.
+// {{ if tfVersionAtLeast "0.13" }}
+func TFVersionAtLeast(version string) bool {
+	current := RenderOpts.TFVersion
+	if len(current) == 0 {
+		current = defaultTFVersion
+	}
+
+	major, minor, err := parseTFVersion(current)
+	if err != nil {
+		return false
+	}
+	wantMajor, wantMinor, err := parseTFVersion(version)
+	if err != nil {
+		return false
+	}
+
+	return major > wantMajor || (major == wantMajor && minor >= wantMinor)
+}
+
+// WriteTerraformBlock writes the terraform block declaring the source of
+// the AWS provider, required from Terraform 0.13
+func WriteTerraformBlock(w io.Writer) error {
+	tmpl := `
+	terraform {
+	  required_providers {
+	    aws = {
+	      source = "hashicorp/aws"
+	      {{- if .ProviderV4 }}
+	      version = ">= 4.0"
+	      {{- end }}
+	    }
+	  }
+	}
+	`
+	return renderHCL(w, tmpl, template.FuncMap{}, RenderOpts)
+}
+
+// Provider is the configuration of an AWS provider block
+type Provider struct {
+	// Alias is set for the providers of a multi-region or multi-profile
+	// export, e.g. "us-east-1" for resources bound to "aws.us-east-1"
+	Alias      string
+	Region     string
+	Profile    string
+	RoleARN    string
+	ExternalID string
+}
+
+// WriteProvider writes the provider "aws" block of p
+func WriteProvider(w io.Writer, p Provider) error {
+	tmpl := `
+	provider "aws" {
+	  {{- if .Alias }}
+	  alias = "{{ .Alias }}"
+	  {{- end }}
+	  {{- if .Region }}
+	  region = "{{ .Region }}"
+	  {{- end }}
+	  {{- if .Profile }}
+	  profile = "{{ .Profile }}"
+	  {{- end }}
+	  {{- if .RoleARN }}
+
+	  assume_role {
+	    role_arn = "{{ .RoleARN }}"
+	    {{- if .ExternalID }}
+	    external_id = {{ hclString .ExternalID }}
+	    {{- end }}
+	  }
+	  {{- end }}
+	}
+	`
+	return renderHCL(w, tmpl, template.FuncMap{}, p)
+}
+
+// WriteDataSources writes the data sources referenced by the ARNs
+// rewritten when RenderOpts.DataSources is set
+func WriteDataSources(w io.Writer) error {
+	tmpl := `
+	data "aws_caller_identity" "current" {}
+
+	data "aws_region" "current" {}
+	`
+	return renderHCL(w, tmpl, template.FuncMap{}, nil)
+}
+
+// WriteJSON writes res, a collection such as *Instances or a map of them,
+// as indented JSON. Keys are the Go field names and nil fields are left
+// out, so that the sparse AWS responses don't turn into walls of nulls
+func WriteJSON(w io.Writer, res interface{}) error {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	b, err = json.MarshalIndent(omitNulls(doc), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+// omitNulls removes the null members of the objects of a decoded JSON document
+func omitNulls(src interface{}) interface{} {
+	switch v := src.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if value == nil {
+				delete(v, key)
+				continue
+			}
+			v[key] = omitNulls(value)
+		}
+	case []interface{}:
+		for k, value := range v {
+			v[k] = omitNulls(value)
+		}
+	}
+
+	return src
+}
+
+// flattenTags turns the different tag representations used by the
+// collectors into a plain map
+func flattenTags(src interface{}) map[string]string {
+	res := make(map[string]string)
+
+	switch tags := src.(type) {
+	case *Tags:
+		if tags != nil {
+			for k, v := range *tags {
+				res[k] = safeString(v, "")
+			}
+		}
+	case Tags:
+		for k, v := range tags {
+			res[k] = safeString(v, "")
+		}
+	case map[string]*string:
+		for k, v := range tags {
+			res[k] = safeString(v, "")
+		}
+	case []*ResourceTag:
+		for _, v := range tags {
+			if v != nil && v.Key != nil {
+				res[*v.Key] = safeString(v.Value, "")
+			}
+		}
+	case []*TagDescription:
+		for _, v := range tags {
+			if v != nil && v.Key != nil {
+				res[*v.Key] = safeString(v.Value, "")
+			}
+		}
+	}
+
+	return res
+}
+
+// tagMap flattens the tags of a resource, merged with RenderOpts.ExtraTags.
+// Templates range over the result in key order, so tags are always
+// rendered in the same order
+func tagMap(src interface{}) map[string]string {
+	res := flattenTags(src)
+	for k, v := range RenderOpts.ExtraTags {
+		if _, ok := res[k]; !ok {
+			res[k] = v
+		}
+	}
+
+	return res
+}
+
+// resourceID returns the "# tfit-id" comment of a resource when
+// RenderOpts.CommentID is set. ids are joined to form the AWS ID,
+// for resources identified by more than one attribute
+func resourceID(resourceType string, ids ...interface{}) string {
+	if !RenderOpts.CommentID {
+		return ""
+	}
+
+	parts := []string{resourceType}
+	for _, v := range ids {
+		switch id := v.(type) {
+		case *string:
+			parts = append(parts, safeString(id, ""))
+		default:
+			parts = append(parts, fmt.Sprint(id))
+		}
+	}
+
+	sum := sha1.Sum([]byte(strings.Join(parts, "/")))
+	return fmt.Sprintf("# tfit-id: %x", sum[:6])
+}
+
+// asgTags appends RenderOpts.ExtraTags to the tags of an AutoScaling Group.
+// Injected tags are not propagated to the instances launched by the group.
+// Tags are sorted by key so that the output does not depend on the API order
+func asgTags(src []*TagDescription) []*TagDescription {
+	res := append([]*TagDescription{}, src...)
+	seen := make(map[string]bool)
+	for _, v := range src {
+		seen[safeString(v.Key, "")] = true
+	}
+
+	keys := make([]string, 0, len(RenderOpts.ExtraTags))
+	for k := range RenderOpts.ExtraTags {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		res = append(res, &TagDescription{
+			Key:               aws.String(k),
+			Value:             aws.String(RenderOpts.ExtraTags[k]),
+			PropagateAtLaunch: aws.Bool(false),
+		})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return safeString(res[i].Key, "") < safeString(res[j].Key, "")
+	})
+	return res
+}
+
 func renderHCL(w io.Writer, Tmpl string, funcMap template.FuncMap, target interface{}) error {
-	t := template.New("").Funcs(funcMap)
+	t := template.New("").Funcs(template.FuncMap{
+		"tags":             tagMap,
+		"asgTags":          asgTags,
+		"resourceID":       resourceID,
+		"sanitizeName":     sanitizeName,
+		"globalName":       globalName,
+		"regionName":       regionName,
+		"regionProvider":   regionProvider,
+		"otherRegion":      otherRegion,
+		"jsonDocument":     jsonDocument,
+		"jsonWarning":      jsonWarning,
+		"hclString":        hclString,
+		"tfVersionAtLeast": TFVersionAtLeast,
+	}).Funcs(funcMap)
 	t, err := t.Parse(Tmpl)
 	if err != nil {
 		return err
@@ -196,7 +1037,9 @@ func renderHCL(w io.Writer, Tmpl string, funcMap template.FuncMap, target interf
 }
 
 func renderTerraformImportCmd(Output io.Writer, Tmpl string, funcMap template.FuncMap, target interface{}) error {
-	t := template.New("").Funcs(funcMap)
+	t := template.New("").Funcs(template.FuncMap{
+		"sanitizeName": sanitizeName,
+	}).Funcs(funcMap)
 	t, err := t.Parse(Tmpl)
 	if err != nil {
 		return err
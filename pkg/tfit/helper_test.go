@@ -0,0 +1,567 @@
+package tfit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/parser"
+)
+
+// stubSession returns a session with static credentials, for the clients
+// of the services which are not behind an interface, see stubSend
+func stubSession() *session.Session {
+	return session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+	}))
+}
+
+// stubSend makes the requests of a client be answered by send, which
+// fills in r.Data instead of calling AWS
+func stubSend(h *request.Handlers, send func(r *request.Request)) {
+	h.Send.Clear()
+	h.Send.PushBack(send)
+	h.UnmarshalMeta.Clear()
+	h.Unmarshal.Clear()
+	h.ValidateResponse.Clear()
+}
+
+func TestJoinStringSlice(t *testing.T) {
+	tests := []struct {
+		name string
+		sep  string
+		src  []string
+		want string
+	}{
+		{"empty", ",", nil, ""},
+		{"single", ",", []string{"a"}, `"a"`},
+		{"several", ",", []string{"a", "b", "c"}, `"a","b","c"`},
+		{"already quoted", ", ", []string{`"a"`, "b"}, `"a", "b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := append([]string(nil), tt.src...)
+			if got := joinStringSlice(tt.sep, src); got != tt.want {
+				t.Errorf("joinStringSlice(%q, %q) = %s, want %s", tt.sep, tt.src, got, tt.want)
+			}
+			if !reflect.DeepEqual(src, tt.src) {
+				t.Errorf("joinStringSlice modified its input: %q, want %q", src, tt.src)
+			}
+		})
+	}
+}
+
+// joinStringSlice used to quote src in place, a second call on the same
+// slice, e.g. rendering the imports after the HCL, then saw the quoted
+// elements
+func TestJoinStringSliceTwice(t *testing.T) {
+	src := []string{"subnet-1", "subnet-2"}
+
+	first := joinStringSlice(",", src)
+	second := joinStringSlice(",", src)
+	if first != second {
+		t.Errorf("joinStringSlice returned %s, then %s on the same input", first, second)
+	}
+	if want := []string{"subnet-1", "subnet-2"}; !reflect.DeepEqual(src, want) {
+		t.Errorf("joinStringSlice modified its input: %q, want %q", src, want)
+	}
+}
+
+func BenchmarkJoinStringSlice(b *testing.B) {
+	src := make([]string, 100)
+	for k := range src {
+		src[k] = "sg-0123456789abcdef0"
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		joinStringSlice(",", src)
+	}
+}
+
+func TestJSONDocument(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+
+	tests := []struct {
+		name string
+		hcl2 bool
+		src  string
+		want string
+	}{
+		{"plain", false, `{"a":"b"}`, "{\n \"a\": \"b\"\n}"},
+		{"interpolation", false, `{"a":"${aws:username}"}`, "{\n \"a\": \"$${aws:username}\"\n}"},
+		{"directive hcl", false, `{"a":"%{x}"}`, "{\n \"a\": \"%{x}\"\n}"},
+		{"directive hcl2", true, `{"a":"%{x}"}`, "{\n \"a\": \"%%{x}\"\n}"},
+		{"invalid JSON", false, `{"a": ${aws:username}`, `{"a": $${aws:username}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RenderOpts.HCL2 = tt.hcl2
+			if got := jsonDocument(&tt.src); got != tt.want {
+				t.Errorf("jsonDocument(%s) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHCLString(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+
+	tests := []struct {
+		name string
+		hcl2 bool
+		src  interface{}
+		want string
+	}{
+		{"plain", false, "web", `"web"`},
+		{"pointer", false, aws.String("web"), `"web"`},
+		{"nil pointer", false, (*string)(nil), `""`},
+		{"double quote", false, `say "hi"`, `"say \"hi\""`},
+		{"newline", false, "a\nb", `"a\nb"`},
+		{"backslash & tab", false, "a\\b\tc", `"a\\b\tc"`},
+		{"interpolation", false, "${var.x}", `"$${var.x}"`},
+		{"directive hcl", false, "%{if}", `"%{if}"`},
+		{"directive hcl2", true, "%{if}", `"%%{if}"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RenderOpts.HCL2 = tt.hcl2
+			if got := hclString(tt.src); got != tt.want {
+				t.Errorf("hclString(%q) = %s, want %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+// The tag values quoted by hclString must come out of HCLFmt unchanged
+func TestHCLStringRoundTrip(t *testing.T) {
+	for _, value := range []string{`say "hi"`, "line 1\nline 2", "\"quoted\"\r\n\ttabbed\\"} {
+		src := "tags {\n  \"Name\" = " + hclString(value) + "\n}\n"
+
+		var buf bytes.Buffer
+		if err := HCLFmt(strings.NewReader(src), &buf); err != nil {
+			t.Fatalf("HCLFmt(%q): %v", src, err)
+		}
+
+		f, err := parser.Parse(buf.Bytes())
+		if err != nil {
+			t.Fatalf("formatted HCL does not parse: %v\n%s", err, buf.String())
+		}
+
+		var got interface{}
+		ast.Walk(f.Node, func(n ast.Node) (ast.Node, bool) {
+			if lit, ok := n.(*ast.LiteralType); ok {
+				got = lit.Token.Value()
+			}
+			return n, true
+		})
+		if got != value {
+			t.Errorf("tag value %q came out of HCLFmt as %q", value, got)
+		}
+	}
+}
+
+// sparse returns a *T whose nested structs are allocated, down to depth,
+// with every other field nil. Slices hold a nil and a sparse element
+func sparse(t reflect.Type, depth int) reflect.Value {
+	res := reflect.New(t)
+	if depth == 0 || t.Kind() != reflect.Struct {
+		return res
+	}
+
+	for k := 0; k < t.NumField(); k++ {
+		f := t.Field(k)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		switch {
+		case f.Type.Kind() == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct:
+			res.Elem().Field(k).Set(sparse(f.Type.Elem(), depth-1))
+		case f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Ptr && f.Type.Elem().Elem().Kind() == reflect.Struct:
+			s := reflect.MakeSlice(f.Type, 2, 2)
+			s.Index(1).Set(sparse(f.Type.Elem().Elem(), depth-1))
+			res.Elem().Field(k).Set(s)
+		}
+	}
+
+	return res
+}
+
+// Every collector must cope with sparse API responses, the nested fields
+// AWS leaves out being nil
+func TestSetSparseResponses(t *testing.T) {
+	tests := []struct {
+		name string
+		src  interface{}
+		set  func(src interface{})
+	}{
+		{"acm", acm.CertificateDetail{}, func(src interface{}) { (&ACMCertificate{}).set(src.(*acm.CertificateDetail)) }},
+		{"autoscaling", autoscaling.Group{}, func(src interface{}) { (&Group{}).set(src.(*autoscaling.Group)) }},
+		{"beanstalk app", elasticbeanstalk.ApplicationDescription{}, func(src interface{}) {
+			(&BeanstalkApp{}).set(src.(*elasticbeanstalk.ApplicationDescription))
+		}},
+		{"beanstalk env", elasticbeanstalk.EnvironmentDescription{}, func(src interface{}) {
+			(&BeanstalkEnv{}).set(src.(*elasticbeanstalk.EnvironmentDescription))
+		}},
+		{"cloudfront", cloudfront.DistributionConfig{}, func(src interface{}) { (&Distribution{}).set(src.(*cloudfront.DistributionConfig)) }},
+		{"cloudtrail", cloudtrail.Trail{}, func(src interface{}) { (&Trail{}).set(src.(*cloudtrail.Trail)) }},
+		{"cloudwatch", cloudwatch.MetricAlarm{}, func(src interface{}) { (&MetricAlarm{}).set(src.(*cloudwatch.MetricAlarm)) }},
+		{"codebuild", codebuild.Project{}, func(src interface{}) { (&CodeBuildProject{}).set(src.(*codebuild.Project)) }},
+		{"instance", ec2.Instance{}, func(src interface{}) { (&Instance{}).set(src.(*ec2.Instance)) }},
+		{"instances", ec2.Reservation{}, func(src interface{}) { (&Instances{}).set(src.(*ec2.Reservation).Instances) }},
+		{"subnet", ec2.Subnet{}, func(src interface{}) { (&Subnet{}).setSubnet(src.(*ec2.Subnet)) }},
+		{"security group", ec2.SecurityGroup{}, func(src interface{}) {
+			(&SecurityGroup{}).setSecurityGroup(src.(*ec2.SecurityGroup), aws.String("123456789012"))
+		}},
+		{"route table", ec2.RouteTable{}, func(src interface{}) { (&RouteTable{}).setRouteTable(src.(*ec2.RouteTable)) }},
+		{"network acl", ec2.NetworkAcl{}, func(src interface{}) { (&NetworkACL{}).setNetworkACL(src.(*ec2.NetworkAcl)) }},
+		{"vpc peering", ec2.VpcPeeringConnection{}, func(src interface{}) {
+			(&VPCPeering{}).setVPCPeering(src.(*ec2.VpcPeeringConnection))
+		}},
+		{"vpc endpoint", ec2.VpcEndpoint{}, func(src interface{}) { (&VPCEndpoint{}).setVPCEndpoint(src.(*ec2.VpcEndpoint)) }},
+		{"dhcp options", ec2.DhcpOptions{}, func(src interface{}) { (&DHCPOptions{}).setDHCPOptions(src.(*ec2.DhcpOptions)) }},
+		{"eni", ec2.NetworkInterface{}, func(src interface{}) { (&ENI{}).setENI(src.(*ec2.NetworkInterface)) }},
+		{"spot request", ec2.SpotInstanceRequest{}, func(src interface{}) { (&SpotRequest{}).set(src.(*ec2.SpotInstanceRequest)) }},
+		{"transit gateway", ec2.TransitGateway{}, func(src interface{}) { (&TransitGateway{}).set(src.(*ec2.TransitGateway)) }},
+		{"transit gateway attachment", ec2.TransitGatewayVpcAttachment{}, func(src interface{}) {
+			(&TransitGatewayVPCAttachment{}).set(src.(*ec2.TransitGatewayVpcAttachment))
+		}},
+		{"vpn gateway", ec2.VpnGateway{}, func(src interface{}) { (&VPNGateway{}).set(src.(*ec2.VpnGateway)) }},
+		{"vpn connection", ec2.VpnConnection{}, func(src interface{}) { (&VPNConnection{}).set(src.(*ec2.VpnConnection)) }},
+		{"elasticache", elasticache.CacheCluster{}, func(src interface{}) { (&ElastiCacheCluster{}).set(src.(*elasticache.CacheCluster)) }},
+		{"elb", elb.LoadBalancerDescription{}, func(src interface{}) {
+			v := src.(*elb.LoadBalancerDescription)
+			e := &ELB{}
+			e.setInstances(v.Instances)
+			e.setListener(v.ListenerDescriptions)
+			e.setHealthCheck(v.HealthCheck)
+		}},
+		{"elb access log", elb.AccessLog{}, func(src interface{}) { (&ELB{}).setAccessLog(src.(*elb.AccessLog)) }},
+		{"iam user", iam.User{}, func(src interface{}) { (&User{}).setUser(src.(*iam.User)) }},
+		{"organizations", organizations.Account{}, func(src interface{}) { (&Account{}).set(src.(*organizations.Account)) }},
+		{"route53", route53.ResourceRecordSet{}, func(src interface{}) {
+			v := src.(*route53.ResourceRecordSet)
+			r := &RecordSet{}
+			// Like GetResourceRecordSetsWithContext, aliases are only set when present
+			if v.AliasTarget != nil {
+				r.setAlias(v.AliasTarget)
+			}
+			r.setValue(v.ResourceRecords)
+		}},
+		{"db subnet group", rds.DBSubnetGroup{}, func(src interface{}) { (&DBSubnetGroup{}).set(src.(*rds.DBSubnetGroup)) }},
+		{"db parameter group", rds.DBParameterGroup{}, func(src interface{}) { (&DBParameterGroup{}).set(src.(*rds.DBParameterGroup)) }},
+		{"db cluster", rds.DBCluster{}, func(src interface{}) { (&DBCluster{}).set(src.(*rds.DBCluster)) }},
+		{"db cluster instance", rds.DBInstance{}, func(src interface{}) { (&DBClusterInstance{}).set(src.(*rds.DBInstance)) }},
+		{"redshift", redshift.Cluster{}, func(src interface{}) { (&RedshiftCluster{}).set(src.(*redshift.Cluster)) }},
+		{"s3 lifecycle", s3.GetBucketLifecycleConfigurationOutput{}, func(src interface{}) {
+			(&Bucket{}).setLifecycleRule(src.(*s3.GetBucketLifecycleConfigurationOutput).Rules)
+		}},
+		{"ses", ses.IdentityNotificationAttributes{}, func(src interface{}) {
+			(&SESIdentity{}).setNotifications(src.(*ses.IdentityNotificationAttributes))
+		}},
+	}
+
+	for _, tt := range tests {
+		for depth := 0; depth <= 3; depth++ {
+			t.Run(fmt.Sprintf("%s/depth %d", tt.name, depth), func(t *testing.T) {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Errorf("panic on a sparse response: %v", r)
+					}
+				}()
+				tt.set(sparse(reflect.TypeOf(tt.src), depth).Interface())
+			})
+		}
+	}
+}
+
+func TestWriteProvider(t *testing.T) {
+	tests := []struct {
+		name   string
+		p      Provider
+		want   []string
+		unwant []string
+	}{
+		{
+			name:   "no role",
+			p:      Provider{Region: "eu-west-1", Profile: "dev"},
+			want:   []string{`region  = "eu-west-1"`, `profile = "dev"`},
+			unwant: []string{"assume_role"},
+		},
+		{
+			name:   "role",
+			p:      Provider{Region: "eu-west-1", RoleARN: "arn:aws:iam::123456789012:role/audit"},
+			want:   []string{"assume_role {", `role_arn = "arn:aws:iam::123456789012:role/audit"`},
+			unwant: []string{"external_id"},
+		},
+		{
+			name: "role with external ID",
+			p:    Provider{Alias: "prod", RoleARN: "arn:aws:iam::123456789012:role/audit", ExternalID: "xyz"},
+			want: []string{`alias = "prod"`, "assume_role {", `role_arn    = "arn:aws:iam::123456789012:role/audit"`, `external_id = "xyz"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteProvider(&buf, tt.p); err != nil {
+				t.Fatalf("WriteProvider: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("want %s in:\n%s", want, buf.String())
+				}
+			}
+			for _, unwant := range tt.unwant {
+				if strings.Contains(buf.String(), unwant) {
+					t.Errorf("unexpected %s in:\n%s", unwant, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestResourceIDStable(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{CommentID: true}
+
+	// ids maps the label of every rendered resource to its tfit-id
+	idRegexp := regexp.MustCompile(`# tfit-id: ([0-9a-f]+)\s+resource "[^"]+" "([^"]+)"`)
+	ids := func(names ...string) map[string]string {
+		var groups LogGroups
+		for _, name := range names {
+			// Fresh values on every render, the ID must not depend on them
+			groups = append(groups, &LogGroup{Name: aws.String(name), RetentionInDays: aws.Int64(7)})
+		}
+
+		var buf bytes.Buffer
+		if err := groups.WriteHCL(&buf); err != nil {
+			t.Fatalf("WriteHCL: %v", err)
+		}
+
+		res := make(map[string]string)
+		for _, m := range idRegexp.FindAllStringSubmatch(buf.String(), -1) {
+			res[m[2]] = m[1]
+		}
+		if len(res) != len(names) {
+			t.Fatalf("want %d tfit-id comments, got %d in:\n%s", len(names), len(res), buf.String())
+		}
+		return res
+	}
+
+	first := ids("app", "web")
+	second := ids("web", "app")
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("tfit-id changed between renders: %v, then %v", first, second)
+	}
+	if first["app"] == first["web"] {
+		t.Errorf("app & web share tfit-id %s", first["app"])
+	}
+}
+
+func TestWriteDataSources(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{DataSources: true, AccountID: "123456789012", Region: "eu-west-1"}
+
+	var buf bytes.Buffer
+	if err := WriteDataSources(&buf); err != nil {
+		t.Fatalf("WriteDataSources: %v", err)
+	}
+	for _, want := range []string{`data "aws_caller_identity" "current" {}`, `data "aws_region" "current" {}`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("want %s in:\n%s", want, buf.String())
+		}
+	}
+
+	machines := &StateMachines{{
+		Name:       aws.String("orders"),
+		Definition: aws.String(`{"StartAt":"Done","States":{"Done":{"Type":"Succeed"}}}`),
+		RoleARN:    aws.String("arn:aws:iam::123456789012:role/orders"),
+	}}
+	buf.Reset()
+	if err := machines.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	want := `role_arn = "arn:aws:iam::${data.aws_caller_identity.current.account_id}:role/orders"`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("want %s in:\n%s", want, buf.String())
+	}
+}
+
+func TestPortableARNs(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{DataSources: true, AccountID: "123456789012", Region: "eu-west-1"}
+
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{
+			src:  "arn:aws:sqs:eu-west-1:123456789012:orders",
+			want: "arn:aws:sqs:${data.aws_region.current.name}:${data.aws_caller_identity.current.account_id}:orders",
+		},
+		// Another account or region stays literal
+		{
+			src:  "arn:aws:sqs:us-east-1:210987654321:orders",
+			want: "arn:aws:sqs:us-east-1:210987654321:orders",
+		},
+		// Global services have no region
+		{
+			src:  "arn:aws:iam::123456789012:role/orders",
+			want: "arn:aws:iam::${data.aws_caller_identity.current.account_id}:role/orders",
+		},
+		{
+			src:  "arn:aws:s3:::bucket",
+			want: "arn:aws:s3:::bucket",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := portableARNs(tt.src); got != tt.want {
+			t.Errorf("portableARNs(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestHCL2Syntax(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+
+	tags := &Tags{"Name": aws.String("web")}
+	tests := []struct {
+		name   string
+		res    interface{ WriteHCL(io.Writer) error }
+		hcl1   []string
+		hcl2   []string
+		unwant []string
+	}{
+		{
+			name: "tags",
+			res:  &Instances{{InstanceID: aws.String("i-1"), ImageID: aws.String("ami-12345678"), InstanceType: aws.String("t2.micro"), Tags: tags}},
+			hcl1: []string{`tags { "Name" = "web" }`},
+			hcl2: []string{`tags = { "Name" = "web" }`},
+		},
+		{
+			name: "alarm dimensions",
+			res: &MetricAlarms{{
+				AlarmName:          aws.String("cpu"),
+				ComparisonOperator: aws.String("GreaterThanThreshold"),
+				EvaluationPeriods:  aws.Int64(1),
+				MetricName:         aws.String("CPUUtilization"),
+				Namespace:          aws.String("AWS/EC2"),
+				Period:             aws.Int64(60),
+				Statistic:          aws.String("Average"),
+				Threshold:          aws.Float64(80),
+				Dimensions:         map[string]*string{"InstanceId": aws.String("i-1")},
+			}},
+			hcl1: []string{`dimensions { "InstanceId" = "i-1" }`},
+			hcl2: []string{`dimensions = { "InstanceId" = "i-1" }`},
+		},
+		{
+			// These dimensions are blocks, they are left alone
+			name: "metric specification dimensions",
+			res: &AppAutoScalingTargets{{
+				ServiceNamespace:  aws.String("dynamodb"),
+				ResourceID:        aws.String("table/Orders"),
+				ScalableDimension: aws.String("dynamodb:table:ReadCapacityUnits"),
+				MinCapacity:       aws.Int64(1),
+				MaxCapacity:       aws.Int64(10),
+				Policies: []*applicationautoscaling.ScalingPolicy{{
+					PolicyName: aws.String("read"),
+					PolicyType: aws.String("TargetTrackingScaling"),
+					TargetTrackingScalingPolicyConfiguration: &applicationautoscaling.TargetTrackingScalingPolicyConfiguration{
+						TargetValue: aws.Float64(70),
+						CustomizedMetricSpecification: &applicationautoscaling.CustomizedMetricSpecification{
+							MetricName: aws.String("Reads"),
+							Namespace:  aws.String("App"),
+							Statistic:  aws.String("Average"),
+							Dimensions: []*applicationautoscaling.MetricDimension{{Name: aws.String("Table"), Value: aws.String("Orders")}},
+						},
+					},
+				}},
+			}},
+			hcl1:   []string{`dimensions { name = "Table" value = "Orders" }`},
+			hcl2:   []string{`dimensions { name = "Table" value = "Orders" }`},
+			unwant: []string{"dimensions = {"},
+		},
+		{
+			name: "nested tags",
+			res: &Buckets{{
+				Name: aws.String("logs"),
+				Tags: tags,
+				ReplicationConfiguration: &s3.ReplicationConfiguration{
+					Role: aws.String("arn:aws:iam::123456789012:role/replication"),
+					Rules: []*s3.ReplicationRule{{
+						ID:          aws.String("tagged"),
+						Status:      aws.String("Enabled"),
+						Priority:    aws.Int64(1),
+						Filter:      &s3.ReplicationRuleFilter{Tag: &s3.Tag{Key: aws.String("Replicate"), Value: aws.String("yes")}},
+						Destination: &s3.Destination{Bucket: aws.String("arn:aws:s3:::logs-replica")},
+					}},
+				},
+			}},
+			hcl1: []string{`tags { "Name" = "web" }`, `tags { "Replicate" = "yes" }`},
+			hcl2: []string{`tags = { "Name" = "web" }`, `tags = { "Replicate" = "yes" }`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, hcl2 := range []bool{false, true} {
+				*RenderOpts = RenderOptions{HCL2: hcl2}
+
+				var buf bytes.Buffer
+				if err := tt.res.WriteHCL(&buf); err != nil {
+					t.Fatalf("hcl2=%v: WriteHCL: %v", hcl2, err)
+				}
+				out := strings.Join(strings.Fields(buf.String()), " ")
+
+				want := tt.hcl1
+				if hcl2 {
+					want = tt.hcl2
+				}
+				for _, want := range want {
+					if !strings.Contains(out, want) {
+						t.Errorf("hcl2=%v: want %s in:\n%s", hcl2, want, buf.String())
+					}
+				}
+				for _, unwant := range tt.unwant {
+					if strings.Contains(out, unwant) {
+						t.Errorf("hcl2=%v: unexpected %s in:\n%s", hcl2, unwant, buf.String())
+					}
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package tfit
 import (
 	"fmt"
 	"io"
+	"strings"
 	"text/template"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -22,11 +23,14 @@ type Policy struct {
 
 type Policies []*Policy
 
-func (c *AWSClient) GetPolicy(p *Policy) error {
-	out, err := c.iamconn.GetPolicy(&iam.GetPolicyInput{PolicyArn: p.Arn})
+func (c *AWSClient) GetPolicyWithContext(ctx aws.Context, p *Policy) error {
+	out, err := c.iamconn.GetPolicyWithContext(ctx, &iam.GetPolicyInput{PolicyArn: p.Arn})
 	if err != nil {
 		return err
 	}
+	if out.Policy == nil {
+		return fmt.Errorf("Policy %s not found", safeString(p.Arn, ""))
+	}
 	p.Description = out.Policy.Description
 	p.DefaultVersionId = out.Policy.DefaultVersionId
 	p.Path = out.Policy.Path
@@ -35,12 +39,21 @@ func (c *AWSClient) GetPolicy(p *Policy) error {
 	return nil
 }
 
-func (c *AWSClient) GetPolicyDocument(p *Policy) error {
-	doc, err := c.iamconn.GetPolicyVersion(&iam.GetPolicyVersionInput{PolicyArn: p.Arn, VersionId: p.DefaultVersionId})
+// GetPolicy calls GetPolicyWithContext with a background context
+func (c *AWSClient) GetPolicy(p *Policy) error {
+	return c.GetPolicyWithContext(aws.BackgroundContext(), p)
+}
+
+func (c *AWSClient) GetPolicyDocumentWithContext(ctx aws.Context, p *Policy) error {
+	doc, err := c.iamconn.GetPolicyVersionWithContext(ctx, &iam.GetPolicyVersionInput{PolicyArn: p.Arn, VersionId: p.DefaultVersionId})
 	if err != nil {
 		return err
 	}
 
+	if doc.PolicyVersion == nil {
+		return fmt.Errorf("Policy version %s of %s not found", safeString(p.DefaultVersionId, ""), safeString(p.Arn, ""))
+	}
+
 	d, err := unEscapeHTML(doc.PolicyVersion.Document)
 	if err != nil {
 		return err
@@ -50,7 +63,17 @@ func (c *AWSClient) GetPolicyDocument(p *Policy) error {
 	return nil
 }
 
-func (c *AWSClient) GetPolicies() (*Policies, error) {
+// GetPolicyDocument calls GetPolicyDocumentWithContext with a background context
+func (c *AWSClient) GetPolicyDocument(p *Policy) error {
+	return c.GetPolicyDocumentWithContext(aws.BackgroundContext(), p)
+}
+
+func (c *AWSClient) GetPoliciesWithContext(ctx aws.Context) (*Policies, error) {
+	// Managed policies cannot be tagged, TagFilter excludes all of them
+	if c.untaggable() {
+		return &Policies{}, nil
+	}
+
 	var res Policies
 
 	opt := &iam.ListPoliciesInput{
@@ -59,7 +82,7 @@ func (c *AWSClient) GetPolicies() (*Policies, error) {
 
 	for {
 		// Get all Local managed policies
-		out, err := c.iamconn.ListPolicies(opt)
+		out, err := c.iamconn.ListPoliciesWithContext(ctx, opt)
 		if err != nil {
 			return nil, err
 		}
@@ -67,19 +90,25 @@ func (c *AWSClient) GetPolicies() (*Policies, error) {
 		ch := make(chan *chanItem, len(out.Policies))
 
 		for _, v := range out.Policies {
+			if !c.wantID(v.PolicyName, v.PolicyId, v.Arn) || !c.wantCreated(v.CreateDate) {
+				ch <- &chanItem{}
+				continue
+			}
 
 			go func(Arn *string) {
 				p := &Policy{
 					Arn: Arn,
 				}
-				err := c.GetPolicy(p)
+				err := c.GetPolicyWithContext(ctx, p)
 				if err != nil {
 					ch <- &chanItem{err: err}
+					return
 				}
 
-				err = c.GetPolicyDocument(p)
+				err = c.GetPolicyDocumentWithContext(ctx, p)
 				if err != nil {
 					ch <- &chanItem{err: err}
+					return
 				}
 
 				ch <- &chanItem{obj: p}
@@ -90,12 +119,18 @@ func (c *AWSClient) GetPolicies() (*Policies, error) {
 		for range out.Policies {
 			receiver := <-ch
 			if receiver.err != nil {
-				return nil, err
+				return nil, receiver.err
+			}
+
+			if receiver.obj == nil {
+				continue
 			}
 
 			res = append(res, receiver.obj.(*Policy))
 		}
 
+		c.progress("aws_iam_policy", len(res))
+
 		// Check if output was truncated
 		if aws.BoolValue(out.IsTruncated) {
 			opt.Marker = out.Marker
@@ -107,22 +142,29 @@ func (c *AWSClient) GetPolicies() (*Policies, error) {
 	return &res, nil
 }
 
+// GetPolicies calls GetPoliciesWithContext with a background context
+func (c *AWSClient) GetPolicies() (*Policies, error) {
+	return c.GetPoliciesWithContext(aws.BackgroundContext())
+}
+
 func (p *Policies) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{}
 
 	tmpl := `
 	{{ if . }}
     {{ range . }}
-    resource "aws_iam_policy" "{{ .PolicyName }}" {
+    {{ resourceID "aws_iam_policy" .Arn }}
+    resource "aws_iam_policy" "{{ sanitizeName .PolicyName }}" {
       name = "{{ .PolicyName }}"
       {{- if .Path }}
       path = "{{.Path }}"
       {{- end }}
       {{- if .Description }}
-      description = "{{ .Description }}"
+      description = {{ hclString .Description }}
       {{- end }}
+      {{ jsonWarning .Document }}
       policy = <<EOF
-      {{ .Document }}
+      {{ jsonDocument .Document }}
 EOF
     }
     {{- end }}
@@ -131,7 +173,41 @@ EOF
 	return renderHCL(w, tmpl, funcMap, p)
 }
 
-//**************** IAM Role ****************
+// WriteImports writes the terraform import commands of 'Policies'
+func (p *Policies) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_iam_policy.{{ sanitizeName .PolicyName }} {{ .Arn }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, p)
+}
+
+//**************** IAM Inline Policy ****************
+
+// InlinePolicy is a policy embedded in a role, a user or a group
+type InlinePolicy struct {
+	Name     *string
+	Document *string
+}
+
+// newInlinePolicy unescapes the document of an inline policy. A document
+// which cannot be unescaped is kept as is, the templates flag it as
+// invalid JSON
+func (c *AWSClient) newInlinePolicy(owner, name, document *string) *InlinePolicy {
+	p := &InlinePolicy{Name: name, Document: document}
+
+	d, err := unEscapeHTML(document)
+	if err != nil {
+		c.logf("Inline policy %s of %s: %s", safeString(name, ""), safeString(owner, ""), err)
+	} else {
+		p.Document = &d
+	}
+
+	return p
+}
+
+// **************** IAM Role ****************
 type Role struct {
 	Name                     *string
 	AssumeRolePolicyDocument *string
@@ -140,20 +216,108 @@ type Role struct {
 	Path                     *string
 	MaxSessionDuration       *int64
 	PermissionBoundaryArn    *string
+	Tags                     *Tags
+
+	AttachedPolicyArns []*string
+	InlinePolicies     []*InlinePolicy
 }
 
 type Roles []*Role
 
-func (c *AWSClient) ListRoles() (*Roles, error) {
+// setRoleTags fetches the tags of r, ListRoles leaves them out
+func (c *AWSClient) setRoleTags(ctx aws.Context, r *Role) error {
+	r.Tags = &Tags{}
+	opt := &iam.ListRoleTagsInput{RoleName: r.Name}
+	for {
+		out, err := c.iamconn.ListRoleTagsWithContext(ctx, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.Tags {
+			if v != nil && v.Key != nil {
+				(*r.Tags)[*v.Key] = v.Value
+			}
+		}
+
+		if !aws.BoolValue(out.IsTruncated) {
+			break
+		}
+		opt.Marker = out.Marker
+	}
+
+	return nil
+}
+
+// setRolePolicies lists the managed policies attached to r
+// and fetches its inline policies
+func (c *AWSClient) setRolePolicies(ctx aws.Context, r *Role) error {
+	attached := &iam.ListAttachedRolePoliciesInput{RoleName: r.Name}
+	for {
+		out, err := c.iamconn.ListAttachedRolePoliciesWithContext(ctx, attached)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.AttachedPolicies {
+			if v != nil && v.PolicyArn != nil {
+				r.AttachedPolicyArns = append(r.AttachedPolicyArns, v.PolicyArn)
+			}
+		}
+
+		if !aws.BoolValue(out.IsTruncated) {
+			break
+		}
+		attached.Marker = out.Marker
+	}
+	sortStrings(r.AttachedPolicyArns)
+
+	inline := &iam.ListRolePoliciesInput{RoleName: r.Name}
+	for {
+		out, err := c.iamconn.ListRolePoliciesWithContext(ctx, inline)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range out.PolicyNames {
+			policy, err := c.iamconn.GetRolePolicyWithContext(ctx, &iam.GetRolePolicyInput{
+				RoleName:   r.Name,
+				PolicyName: name,
+			})
+			if err != nil {
+				return err
+			}
+			r.InlinePolicies = append(r.InlinePolicies, c.newInlinePolicy(r.Name, policy.PolicyName, policy.PolicyDocument))
+		}
+
+		if !aws.BoolValue(out.IsTruncated) {
+			break
+		}
+		inline.Marker = out.Marker
+	}
+
+	return nil
+}
+
+func (c *AWSClient) ListRolesWithContext(ctx aws.Context) (*Roles, error) {
 	opt := iam.ListRolesInput{}
 	var output Roles
 	for {
-		data, err := c.iamconn.ListRoles(&opt)
+		data, err := c.iamconn.ListRolesWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, v := range data.Roles {
+			if v == nil || !c.wantID(v.RoleName, v.RoleId, v.Arn) || !c.wantCreated(v.CreateDate) {
+				continue
+			}
+
+			// Service-linked roles are managed by the services themselves
+			if strings.HasPrefix(aws.StringValue(v.Path), "/aws-service-role/") {
+				continue
+			}
+
 			tmp := Role{
 				AssumeRolePolicyDocument: v.AssumeRolePolicyDocument,
 				Description:              v.Description,
@@ -166,17 +330,31 @@ func (c *AWSClient) ListRoles() (*Roles, error) {
 				tmp.PermissionBoundaryArn = v.PermissionsBoundary.PermissionsBoundaryArn
 			}
 
+			// A document which cannot be unescaped is kept as is,
+			// the template flags it as invalid JSON
 			unEscapeAssumeRole, err := unEscapeHTML(tmp.AssumeRolePolicyDocument)
 			if err != nil {
-				fmt.Println(err)
-				continue
+				c.logf("Role %s: %s", safeString(tmp.Name, ""), err)
 			} else {
 				tmp.AssumeRolePolicyDocument = &unEscapeAssumeRole
 			}
 
+			if err := c.setRoleTags(ctx, &tmp); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+
+			if err := c.setRolePolicies(ctx, &tmp); err != nil {
+				return nil, err
+			}
+
 			output = append(output, &tmp)
 		}
 
+		c.progress("aws_iam_role", len(output))
+
 		if data.IsTruncated != nil && aws.BoolValue(data.IsTruncated) {
 			opt.Marker = data.Marker
 		} else {
@@ -187,26 +365,33 @@ func (c *AWSClient) ListRoles() (*Roles, error) {
 	return &output, nil
 }
 
+// ListRoles calls ListRolesWithContext with a background context
+func (c *AWSClient) ListRoles() (*Roles, error) {
+	return c.ListRolesWithContext(aws.BackgroundContext())
+}
+
 func (r *Roles) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"makeTerraformResourceName": makeTerraformResourceName,
-		"prettyJSON":                prettyJSON,
+		"arnName":                   arnName,
 	}
 
 	tmpl := `
 	{{ if . }}
     {{ range . }}
-    resource "aws_iam_policy" "{{ .Name | makeTerraformResourceName }}" {
+    {{ resourceID "aws_iam_role" .RoleId }}
+    resource "aws_iam_role" "{{ .Name | makeTerraformResourceName | sanitizeName }}" {
       name = "{{ .Name }}"
+      {{ jsonWarning .AssumeRolePolicyDocument }}
       assume_role_policy = <<EOF
-      {{ .AssumeRolePolicyDocument | prettyJSON }}
+      {{ jsonDocument .AssumeRolePolicyDocument }}
 EOF
       {{- if .Path }}
       path = "{{ .Path }}"
       {{- end }}
 
       {{- if .Description }}
-      description = "{{ .Description }}"
+      description = {{ hclString .Description }}
       {{- end }}
 
       {{- if .MaxSessionDuration }}
@@ -216,26 +401,180 @@ EOF
       {{- if .PermissionBoundaryArn}}
       permissions_boundary = "{{ .PermissionBoundaryArn }}"
       {{- end }}
+
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end}}
+      }
+      {{- end }}
     }
+    {{- $role := . }}
+    {{- range .AttachedPolicyArns }}
+
+    resource "aws_iam_role_policy_attachment" "{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $role.Name) (arnName .)) }}" {
+      role = "{{ $role.Name }}"
+      policy_arn = "{{ . }}"
+    }
+    {{- end }}
+    {{- range .InlinePolicies }}
+
+    resource "aws_iam_role_policy" "{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $role.Name) (makeTerraformResourceName .Name)) }}" {
+      name = "{{ .Name }}"
+      role = "{{ $role.Name }}"
+      {{ jsonWarning .Document }}
+      policy = <<EOF
+      {{ jsonDocument .Document }}
+EOF
+    }
+    {{- end }}
     {{- end }}
 	{{- end}}
 	`
 	return renderHCL(w, tmpl, funcMap, r)
 }
 
-//**************** IAM User ****************
+// WriteImports writes the terraform import commands of 'Roles'
+func (r *Roles) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformResourceName": makeTerraformResourceName,
+		"arnName":                   arnName,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_iam_role.{{ .Name | makeTerraformResourceName | sanitizeName }} {{ .Name }}
+{{ $role := . }}{{ range .AttachedPolicyArns -}}
+terraform import aws_iam_role_policy_attachment.{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $role.Name) (arnName .)) }} {{ $role.Name }}/{{ . }}
+{{ end }}{{ range .InlinePolicies -}}
+terraform import aws_iam_role_policy.{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $role.Name) (makeTerraformResourceName .Name)) }} {{ $role.Name }}:{{ .Name }}
+{{ end }}{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, r)
+}
+
+// **************** IAM Instance Profile ****************
+type InstanceProfile struct {
+	Name              *string
+	InstanceProfileId *string
+	Path              *string
+
+	// An instance profile holds at most one role
+	RoleName *string
+
+	// Set by ResolveReferences when the role is exported as well
+	RoleExported bool
+}
+
+type InstanceProfiles []*InstanceProfile
+
+func (c *AWSClient) ListInstanceProfilesWithContext(ctx aws.Context) (*InstanceProfiles, error) {
+	// Instance profiles cannot be tagged, TagFilter excludes all of them,
+	// even the ones of the roles it keeps
+	if c.untaggable() {
+		return &InstanceProfiles{}, nil
+	}
+
+	opt := iam.ListInstanceProfilesInput{}
+	var output InstanceProfiles
+	for {
+		data, err := c.iamconn.ListInstanceProfilesWithContext(ctx, &opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range data.InstanceProfiles {
+			if v == nil || !c.wantID(v.InstanceProfileName, v.InstanceProfileId, v.Arn) || !c.wantCreated(v.CreateDate) {
+				continue
+			}
+
+			tmp := InstanceProfile{
+				Name:              v.InstanceProfileName,
+				InstanceProfileId: v.InstanceProfileId,
+				Path:              v.Path,
+			}
+			if len(v.Roles) > 0 && v.Roles[0] != nil {
+				tmp.RoleName = v.Roles[0].RoleName
+			}
+
+			output = append(output, &tmp)
+		}
+
+		c.progress("aws_iam_instance_profile", len(output))
+
+		if aws.BoolValue(data.IsTruncated) {
+			opt.Marker = data.Marker
+		} else {
+			break
+		}
+	}
+
+	return &output, nil
+}
+
+// ListInstanceProfiles calls ListInstanceProfilesWithContext with a background context
+func (c *AWSClient) ListInstanceProfiles() (*InstanceProfiles, error) {
+	return c.ListInstanceProfilesWithContext(aws.BackgroundContext())
+}
+
+func (p *InstanceProfiles) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformResourceName": makeTerraformResourceName,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_iam_instance_profile" .InstanceProfileId }}
+    resource "aws_iam_instance_profile" "{{ .Name | makeTerraformResourceName | sanitizeName }}" {
+      name = "{{ .Name }}"
+      {{- if .Path }}
+      path = "{{ .Path }}"
+      {{- end }}
+      {{- if .RoleExported }}
+      role = "${aws_iam_role.{{ .RoleName | makeTerraformResourceName | sanitizeName }}.name}"
+      {{- else if .RoleName }}
+      role = "{{ .RoleName }}"
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, p)
+}
+
+// WriteImports writes the terraform import commands of 'InstanceProfiles'
+func (p *InstanceProfiles) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformResourceName": makeTerraformResourceName,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_iam_instance_profile.{{ .Name | makeTerraformResourceName | sanitizeName }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, p)
+}
+
+// **************** IAM User ****************
 type User struct {
 	Path                   *string
 	Tags                   *Tags
 	UserId                 *string
 	UserName               *string
 	PermissionsBoundaryArn *string
+
+	Groups             []*string
+	AttachedPolicyArns []*string
+	InlinePolicies     []*InlinePolicy
 }
 
 func (u *User) setUser(src *iam.User) {
 	u.Path = src.Path
 	u.Tags = &Tags{}
 	for _, v := range src.Tags {
+		if v == nil || v.Key == nil {
+			continue
+		}
 		map[string]*string(*u.Tags)[*v.Key] = v.Value
 	}
 	u.UserId = src.UserId
@@ -247,21 +586,102 @@ func (u *User) setUser(src *iam.User) {
 
 type Users []*User
 
-func (c *AWSClient) ListUsers() (*Users, error) {
+// setUserPolicies lists the groups of u & the managed policies attached
+// to it, and fetches its inline policies
+func (c *AWSClient) setUserPolicies(ctx aws.Context, u *User) error {
+	groups := &iam.ListGroupsForUserInput{UserName: u.UserName}
+	for {
+		out, err := c.iamconn.ListGroupsForUserWithContext(ctx, groups)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.Groups {
+			if v != nil && v.GroupName != nil {
+				u.Groups = append(u.Groups, v.GroupName)
+			}
+		}
+
+		if !aws.BoolValue(out.IsTruncated) {
+			break
+		}
+		groups.Marker = out.Marker
+	}
+	sortStrings(u.Groups)
+
+	attached := &iam.ListAttachedUserPoliciesInput{UserName: u.UserName}
+	for {
+		out, err := c.iamconn.ListAttachedUserPoliciesWithContext(ctx, attached)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.AttachedPolicies {
+			if v != nil && v.PolicyArn != nil {
+				u.AttachedPolicyArns = append(u.AttachedPolicyArns, v.PolicyArn)
+			}
+		}
+
+		if !aws.BoolValue(out.IsTruncated) {
+			break
+		}
+		attached.Marker = out.Marker
+	}
+	sortStrings(u.AttachedPolicyArns)
+
+	inline := &iam.ListUserPoliciesInput{UserName: u.UserName}
+	for {
+		out, err := c.iamconn.ListUserPoliciesWithContext(ctx, inline)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range out.PolicyNames {
+			policy, err := c.iamconn.GetUserPolicyWithContext(ctx, &iam.GetUserPolicyInput{
+				UserName:   u.UserName,
+				PolicyName: name,
+			})
+			if err != nil {
+				return err
+			}
+			u.InlinePolicies = append(u.InlinePolicies, c.newInlinePolicy(u.UserName, policy.PolicyName, policy.PolicyDocument))
+		}
+
+		if !aws.BoolValue(out.IsTruncated) {
+			break
+		}
+		inline.Marker = out.Marker
+	}
+
+	return nil
+}
+
+func (c *AWSClient) ListUsersWithContext(ctx aws.Context) (*Users, error) {
 	opt := iam.ListUsersInput{}
 
 	var output Users
 	for {
-		data, err := c.iamconn.ListUsers(&opt)
+		data, err := c.iamconn.ListUsersWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
 		for _, v := range data.Users {
+			if v == nil || !c.wantID(v.UserName, v.UserId, v.Arn) || !c.wantCreated(v.CreateDate) {
+				continue
+			}
 			var u User
 			u.setUser(v)
+			if !c.matchTags(u.Tags) {
+				continue
+			}
+			if err := c.setUserPolicies(ctx, &u); err != nil {
+				return nil, err
+			}
 			output = append(output, &u)
 		}
 
+		c.progress("aws_iam_user", len(output))
+
 		if data.IsTruncated != nil && aws.BoolValue(data.IsTruncated) {
 			opt.Marker = data.Marker
 		} else {
@@ -272,15 +692,24 @@ func (c *AWSClient) ListUsers() (*Users, error) {
 	return &output, nil
 }
 
+// ListUsers calls ListUsersWithContext with a background context
+func (c *AWSClient) ListUsers() (*Users, error) {
+	return c.ListUsersWithContext(aws.BackgroundContext())
+}
+
 func (r *Users) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"makeTerraformResourceName": makeTerraformResourceName,
+		"arnName":                   arnName,
+		"StringValueSlice":          aws.StringValueSlice,
+		"joinstring":                joinStringSlice,
 	}
 
 	tmpl := `
 	{{ if . }}
     {{ range . }}
-    resource "aws_iam_user" "{{ .UserName | makeTerraformResourceName }}" {
+    {{ resourceID "aws_iam_user" .UserId }}
+    resource "aws_iam_user" "{{ .UserName | makeTerraformResourceName | sanitizeName }}" {
       name = "{{ .UserName }}"
       {{- if .Path }}
       path = "{{ .Path }}"
@@ -290,47 +719,161 @@ func (r *Users) WriteHCL(w io.Writer) error {
       permissions_boundary = "{{ .PermissionsBoundaryArn }}"
       {{- end }}
 
-      {{- if gt (len .Tags) 0 }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
       tags {
-        {{- range $k, $v := .Tags }}
-        "{{ $k }}" = "{{ $v }}"
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
         {{- end}}
       }
       {{- end }}
     }
+    {{- $user := . }}
+    {{- if .Groups }}
+
+    resource "aws_iam_user_group_membership" "{{ .UserName | makeTerraformResourceName | sanitizeName }}" {
+      user = "{{ .UserName }}"
+      groups = [{{ StringValueSlice .Groups | joinstring "," }}]
+    }
+    {{- end }}
+    {{- range .AttachedPolicyArns }}
+
+    resource "aws_iam_user_policy_attachment" "{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $user.UserName) (arnName .)) }}" {
+      user = "{{ $user.UserName }}"
+      policy_arn = "{{ . }}"
+    }
+    {{- end }}
+    {{- range .InlinePolicies }}
+
+    resource "aws_iam_user_policy" "{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $user.UserName) (makeTerraformResourceName .Name)) }}" {
+      name = "{{ .Name }}"
+      user = "{{ $user.UserName }}"
+      {{ jsonWarning .Document }}
+      policy = <<EOF
+      {{ jsonDocument .Document }}
+EOF
+    }
+    {{- end }}
     {{- end }}
 	{{- end}}
 	`
 	return renderHCL(w, tmpl, funcMap, r)
 }
 
-//**************** IAM Group ****************
+// WriteImports writes the terraform import commands of 'Users'
+func (r *Users) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformResourceName": makeTerraformResourceName,
+		"arnName":                   arnName,
+		"StringValueSlice":          aws.StringValueSlice,
+		"join":                      strings.Join,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_iam_user.{{ .UserName | makeTerraformResourceName | sanitizeName }} {{ .UserName }}
+{{ if .Groups -}}
+terraform import aws_iam_user_group_membership.{{ .UserName | makeTerraformResourceName | sanitizeName }} {{ .UserName }}/{{ join (StringValueSlice .Groups) "/" }}
+{{ end }}{{ $user := . }}{{ range .AttachedPolicyArns -}}
+terraform import aws_iam_user_policy_attachment.{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $user.UserName) (arnName .)) }} {{ $user.UserName }}/{{ . }}
+{{ end }}{{ range .InlinePolicies -}}
+terraform import aws_iam_user_policy.{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $user.UserName) (makeTerraformResourceName .Name)) }} {{ $user.UserName }}:{{ .Name }}
+{{ end }}{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, r)
+}
+
+// **************** IAM Group ****************
 type IAMGroup struct {
 	Name *string
 	Id   *string
 	Path *string
+
+	AttachedPolicyArns []*string
+	InlinePolicies     []*InlinePolicy
 }
 
 type IAMGroups []*IAMGroup
 
-func (c *AWSClient) ListIAMGroups() (*IAMGroups, error) {
+// setGroupPolicies lists the managed policies attached to g
+// and fetches its inline policies
+func (c *AWSClient) setGroupPolicies(ctx aws.Context, g *IAMGroup) error {
+	attached := &iam.ListAttachedGroupPoliciesInput{GroupName: g.Name}
+	for {
+		out, err := c.iamconn.ListAttachedGroupPoliciesWithContext(ctx, attached)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.AttachedPolicies {
+			if v != nil && v.PolicyArn != nil {
+				g.AttachedPolicyArns = append(g.AttachedPolicyArns, v.PolicyArn)
+			}
+		}
+
+		if !aws.BoolValue(out.IsTruncated) {
+			break
+		}
+		attached.Marker = out.Marker
+	}
+	sortStrings(g.AttachedPolicyArns)
+
+	inline := &iam.ListGroupPoliciesInput{GroupName: g.Name}
+	for {
+		out, err := c.iamconn.ListGroupPoliciesWithContext(ctx, inline)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range out.PolicyNames {
+			policy, err := c.iamconn.GetGroupPolicyWithContext(ctx, &iam.GetGroupPolicyInput{
+				GroupName:  g.Name,
+				PolicyName: name,
+			})
+			if err != nil {
+				return err
+			}
+			g.InlinePolicies = append(g.InlinePolicies, c.newInlinePolicy(g.Name, policy.PolicyName, policy.PolicyDocument))
+		}
+
+		if !aws.BoolValue(out.IsTruncated) {
+			break
+		}
+		inline.Marker = out.Marker
+	}
+
+	return nil
+}
+
+func (c *AWSClient) ListIAMGroupsWithContext(ctx aws.Context) (*IAMGroups, error) {
+	// IAM groups have no tags, TagFilter excludes all of them
+	if c.untaggable() {
+		return &IAMGroups{}, nil
+	}
+
 	opt := iam.ListGroupsInput{}
 	var output IAMGroups
 	for {
-		data, err := c.iamconn.ListGroups(&opt)
+		data, err := c.iamconn.ListGroupsWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, g := range data.Groups {
+			if g == nil || !c.wantID(g.GroupName, g.GroupId, g.Arn) || !c.wantCreated(g.CreateDate) {
+				continue
+			}
 			tmp := IAMGroup{
 				Name: g.GroupName,
 				Id:   g.GroupId,
 				Path: g.Path,
 			}
+			if err := c.setGroupPolicies(ctx, &tmp); err != nil {
+				return nil, err
+			}
 			output = append(output, &tmp)
 		}
 
+		c.progress("aws_iam_group", len(output))
+
 		if data.IsTruncated != nil && aws.BoolValue(data.IsTruncated) {
 			opt.Marker = data.Marker
 		} else {
@@ -341,22 +884,65 @@ func (c *AWSClient) ListIAMGroups() (*IAMGroups, error) {
 	return &output, nil
 }
 
+// ListIAMGroups calls ListIAMGroupsWithContext with a background context
+func (c *AWSClient) ListIAMGroups() (*IAMGroups, error) {
+	return c.ListIAMGroupsWithContext(aws.BackgroundContext())
+}
+
 func (g *IAMGroups) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"makeTerraformResourceName": makeTerraformResourceName,
+		"arnName":                   arnName,
 	}
 
 	tmpl := `
 	{{ if . }}
     {{ range . }}
-    resource "aws_iam_group" "{{ .Name | makeTerraformResourceName }}" {
+    {{ resourceID "aws_iam_group" .Id }}
+    resource "aws_iam_group" "{{ .Name | makeTerraformResourceName | sanitizeName }}" {
       name = "{{ .Name }}"
       {{- if .Path }}
       path = "{{ .Path }}"
       {{- end }}
     }
+    {{- $group := . }}
+    {{- range .AttachedPolicyArns }}
+
+    resource "aws_iam_group_policy_attachment" "{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $group.Name) (arnName .)) }}" {
+      group = "{{ $group.Name }}"
+      policy_arn = "{{ . }}"
+    }
+    {{- end }}
+    {{- range .InlinePolicies }}
+
+    resource "aws_iam_group_policy" "{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $group.Name) (makeTerraformResourceName .Name)) }}" {
+      name = "{{ .Name }}"
+      group = "{{ $group.Name }}"
+      {{ jsonWarning .Document }}
+      policy = <<EOF
+      {{ jsonDocument .Document }}
+EOF
+    }
+    {{- end }}
     {{- end }}
 	{{- end}}
 	`
 	return renderHCL(w, tmpl, funcMap, g)
 }
+
+// WriteImports writes the terraform import commands of 'IAMGroups'
+func (g *IAMGroups) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformResourceName": makeTerraformResourceName,
+		"arnName":                   arnName,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_iam_group.{{ .Name | makeTerraformResourceName | sanitizeName }} {{ .Name }}
+{{ $group := . }}{{ range .AttachedPolicyArns -}}
+terraform import aws_iam_group_policy_attachment.{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $group.Name) (arnName .)) }} {{ $group.Name }}/{{ . }}
+{{ end }}{{ range .InlinePolicies -}}
+terraform import aws_iam_group_policy.{{ sanitizeName (printf "%s_%s" (makeTerraformResourceName $group.Name) (makeTerraformResourceName .Name)) }} {{ $group.Name }}:{{ .Name }}
+{{ end }}{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, g)
+}
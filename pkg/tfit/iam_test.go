@@ -0,0 +1,82 @@
+package tfit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+func TestPoliciesWriteHCLPolicyVariables(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+
+	doc := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*",` +
+		`"Resource":"arn:aws:s3:::home/${aws:username}/*","Condition":{"StringEquals":{"aws:PrincipalTag/team":"${aws:PrincipalTag/team}"}}}]}`
+	policies := &Policies{{
+		Arn:        aws.String("arn:aws:iam::123456789012:policy/home"),
+		PolicyName: aws.String("home"),
+		Document:   aws.String(doc),
+	}}
+
+	for _, hcl2 := range []bool{false, true} {
+		RenderOpts.HCL2 = hcl2
+
+		var buf bytes.Buffer
+		if err := policies.WriteHCL(&buf); err != nil {
+			t.Fatalf("hcl2=%v: WriteHCL: %v", hcl2, err)
+		}
+
+		out := buf.String()
+		for _, want := range []string{"$${aws:username}", "$${aws:PrincipalTag/team}"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("hcl2=%v: policy variable not escaped, want %s in:\n%s", hcl2, want, out)
+			}
+		}
+	}
+}
+
+func TestListRolesTagFilter(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{}
+
+	tags := map[string][]*iam.Tag{
+		"prod": {{Key: aws.String("Environment"), Value: aws.String("prod")}},
+		"dev":  {{Key: aws.String("Environment"), Value: aws.String("dev")}},
+	}
+	c := &AWSClient{TagFilter: map[string]string{"Environment": "prod"}}
+	c.iamconn = iam.New(stubSession())
+	stubSend(&c.iamconn.Handlers, func(r *request.Request) {
+		switch out := r.Data.(type) {
+		case *iam.ListRolesOutput:
+			for _, name := range []string{"dev", "prod"} {
+				out.Roles = append(out.Roles, &iam.Role{
+					RoleName:                 aws.String(name),
+					RoleId:                   aws.String("AROA" + name),
+					AssumeRolePolicyDocument: aws.String("{}"),
+				})
+			}
+		case *iam.ListRoleTagsOutput:
+			out.Tags = tags[aws.StringValue(r.Params.(*iam.ListRoleTagsInput).RoleName)]
+		}
+	})
+
+	roles, err := c.ListRoles()
+	if err != nil {
+		t.Fatalf("ListRoles: %v", err)
+	}
+	if len(*roles) != 1 || aws.StringValue((*roles)[0].Name) != "prod" {
+		t.Fatalf("want the prod role only, got %v", *roles)
+	}
+
+	var buf bytes.Buffer
+	if err := roles.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	out := strings.Join(strings.Fields(buf.String()), " ")
+	if want := `tags { "Environment" = "prod" }`; !strings.Contains(out, want) {
+		t.Errorf("want %s in:\n%s", want, buf.String())
+	}
+}
@@ -0,0 +1,192 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+)
+
+//**************** Kinesis Stream ****************
+type KinesisStream struct {
+	Name                 *string
+	ARN                  *string
+	Status               *string
+	ShardCount           int
+	RetentionPeriodHours *int64
+	ShardLevelMetrics    []*string
+	EncryptionType       *string
+	KMSKeyID             *string
+	Tags                 *Tags
+}
+
+type KinesisStreams []*KinesisStream
+
+// describe gets the settings of the stream and counts its open shards,
+// those without an ending sequence number
+func (s *KinesisStream) describe(ctx aws.Context, c *AWSClient) error {
+	opt := &kinesis.DescribeStreamInput{StreamName: s.Name}
+	for {
+		out, err := c.kinesisconn.DescribeStreamWithContext(ctx, opt)
+		if err != nil {
+			return err
+		}
+		if out.StreamDescription == nil {
+			return nil
+		}
+
+		desc := out.StreamDescription
+		s.ARN = desc.StreamARN
+		s.Status = desc.StreamStatus
+		s.RetentionPeriodHours = desc.RetentionPeriodHours
+		s.EncryptionType = desc.EncryptionType
+		s.KMSKeyID = desc.KeyId
+
+		s.ShardLevelMetrics = nil
+		for _, v := range desc.EnhancedMonitoring {
+			if v != nil {
+				s.ShardLevelMetrics = append(s.ShardLevelMetrics, v.ShardLevelMetrics...)
+			}
+		}
+		sortStrings(s.ShardLevelMetrics)
+
+		for _, v := range desc.Shards {
+			if v == nil {
+				continue
+			}
+			opt.ExclusiveStartShardId = v.ShardId
+			if v.SequenceNumberRange == nil || v.SequenceNumberRange.EndingSequenceNumber == nil {
+				s.ShardCount++
+			}
+		}
+
+		if !aws.BoolValue(desc.HasMoreShards) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *KinesisStream) getTags(ctx aws.Context, c *AWSClient) error {
+	s.Tags = &Tags{}
+
+	opt := &kinesis.ListTagsForStreamInput{StreamName: s.Name}
+	for {
+		out, err := c.kinesisconn.ListTagsForStreamWithContext(ctx, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.Tags {
+			if v != nil && v.Key != nil {
+				(*s.Tags)[*v.Key] = v.Value
+				opt.ExclusiveStartTagKey = v.Key
+			}
+		}
+
+		if !aws.BoolValue(out.HasMoreTags) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// GetKinesisStreamsWithContext returns the streams which are not being deleted
+func (c *AWSClient) GetKinesisStreamsWithContext(ctx aws.Context) (*KinesisStreams, error) {
+	var res KinesisStreams
+
+	opt := &kinesis.ListStreamsInput{}
+	for {
+		out, err := c.kinesisconn.ListStreamsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.StreamNames {
+			if v == nil {
+				continue
+			}
+			opt.ExclusiveStartStreamName = v
+
+			tmp := &KinesisStream{Name: v}
+			if err := tmp.describe(ctx, c); err != nil {
+				return nil, err
+			}
+			if aws.StringValue(tmp.Status) == kinesis.StreamStatusDeleting || !c.wantID(tmp.Name, tmp.ARN) {
+				continue
+			}
+			if err := tmp.getTags(ctx, c); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_kinesis_stream", len(res))
+
+		if !aws.BoolValue(out.HasMoreStreams) {
+			break
+		}
+	}
+
+	return &res, nil
+}
+
+// GetKinesisStreams calls GetKinesisStreamsWithContext with a background context
+func (c *AWSClient) GetKinesisStreams() (*KinesisStreams, error) {
+	return c.GetKinesisStreamsWithContext(aws.BackgroundContext())
+}
+
+func (s *KinesisStreams) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_kinesis_stream" .Name }}
+    resource "aws_kinesis_stream" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      shard_count = {{ .ShardCount }}
+      retention_period = {{ .RetentionPeriodHours }}
+      {{- if .ShardLevelMetrics }}
+      shard_level_metrics = [{{ StringValueSlice .ShardLevelMetrics | joinstring "," }}]
+      {{- end }}
+      {{- if .EncryptionType }}
+      encryption_type = "{{ .EncryptionType }}"
+      {{- end }}
+      {{- if .KMSKeyID }}
+      kms_key_id = "{{ .KMSKeyID }}"
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, s)
+}
+
+// WriteImports writes the terraform import commands of 'KinesisStreams'
+func (s *KinesisStreams) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_kinesis_stream.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, s)
+}
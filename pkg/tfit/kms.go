@@ -0,0 +1,255 @@
+package tfit
+
+import (
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+//**************** KMS Key ****************
+type KMSKey struct {
+	KeyID       *string
+	Description *string
+	KeyUsage    *string
+	Enabled     *bool
+	Policy      *string
+	Tags        *Tags
+
+	// list-aliases
+	Aliases []*string
+}
+
+type KMSKeys []*KMSKey
+
+// getKMSAliases returns the alias names of every key, by key ID
+func (c *AWSClient) getKMSAliases(ctx aws.Context) (map[string][]*string, error) {
+	res := make(map[string][]*string)
+
+	opt := &kms.ListAliasesInput{}
+	for {
+		out, err := c.kmsconn.ListAliasesWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Aliases {
+			if v == nil || v.TargetKeyId == nil {
+				continue
+			}
+			res[*v.TargetKeyId] = append(res[*v.TargetKeyId], v.AliasName)
+		}
+
+		if !aws.BoolValue(out.Truncated) {
+			break
+		}
+		opt.Marker = out.NextMarker
+	}
+
+	return res, nil
+}
+
+// GetKMSKeysWithContext returns the customer managed keys with their
+// aliases. AWS managed keys, which Terraform can not manage, keys
+// pending deletion and the ones TagFilter excludes are skipped
+func (c *AWSClient) GetKMSKeysWithContext(ctx aws.Context) (*KMSKeys, error) {
+	aliases, err := c.getKMSAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res KMSKeys
+	opt := &kms.ListKeysInput{}
+	for {
+		out, err := c.kmsconn.ListKeysWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Keys {
+			if v == nil || !c.wantID(v.KeyId, v.KeyArn) {
+				continue
+			}
+
+			key, err := c.getKMSKey(ctx, v.KeyId, aliases[aws.StringValue(v.KeyId)])
+			if err != nil {
+				return nil, err
+			}
+			if key != nil {
+				res = append(res, key)
+			}
+		}
+
+		c.progress("aws_kms_key", len(res))
+
+		if !aws.BoolValue(out.Truncated) {
+			break
+		}
+		opt.Marker = out.NextMarker
+	}
+
+	return &res, nil
+}
+
+// getKMSKeyTags returns the tags of the key keyID
+func (c *AWSClient) getKMSKeyTags(ctx aws.Context, keyID *string) (*Tags, error) {
+	res := &Tags{}
+	opt := &kms.ListResourceTagsInput{KeyId: keyID}
+	for {
+		out, err := c.kmsconn.ListResourceTagsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Tags {
+			if v != nil && v.TagKey != nil {
+				(*res)[*v.TagKey] = v.TagValue
+			}
+		}
+
+		if !aws.BoolValue(out.Truncated) {
+			break
+		}
+		opt.Marker = out.NextMarker
+	}
+
+	return res, nil
+}
+
+// getKMSKey describes a key and fetches its tags & default policy. It
+// returns nil for the keys GetKMSKeysWithContext skips
+func (c *AWSClient) getKMSKey(ctx aws.Context, keyID *string, aliases []*string) (*KMSKey, error) {
+	for _, v := range aliases {
+		if strings.HasPrefix(aws.StringValue(v), "alias/aws/") {
+			return nil, nil
+		}
+	}
+
+	desc, err := c.kmsconn.DescribeKeyWithContext(ctx, &kms.DescribeKeyInput{KeyId: keyID})
+	if err != nil {
+		return nil, err
+	}
+
+	meta := desc.KeyMetadata
+	if meta == nil ||
+		aws.StringValue(meta.KeyManager) == kms.KeyManagerTypeAws ||
+		aws.StringValue(meta.KeyState) == kms.KeyStatePendingDeletion ||
+		!c.wantCreated(meta.CreationDate) {
+		return nil, nil
+	}
+
+	tags, err := c.getKMSKeyTags(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.matchTags(tags) {
+		return nil, nil
+	}
+
+	policy, err := c.kmsconn.GetKeyPolicyWithContext(ctx, &kms.GetKeyPolicyInput{
+		KeyId:      keyID,
+		PolicyName: aws.String("default"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortStrings(aliases)
+	return &KMSKey{
+		KeyID:       meta.KeyId,
+		Description: meta.Description,
+		KeyUsage:    meta.KeyUsage,
+		Enabled:     meta.Enabled,
+		Policy:      policy.Policy,
+		Tags:        tags,
+		Aliases:     aliases,
+	}, nil
+}
+
+// GetKMSKeys calls GetKMSKeysWithContext with a background context
+func (c *AWSClient) GetKMSKeys() (*KMSKeys, error) {
+	return c.GetKMSKeysWithContext(aws.BackgroundContext())
+}
+
+func (k *KMSKeys) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"StringValue": aws.StringValue,
+		"BoolValue":   aws.BoolValue,
+		"aliasName":   kmsAliasName,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_kms_key" .KeyID }}
+    resource "aws_kms_key" "{{ sanitizeName .KeyID }}" {
+      {{- if .Description }}
+      description = {{ hclString .Description }}
+      {{- end }}
+      {{- if .KeyUsage }}
+      key_usage = "{{ .KeyUsage }}"
+      {{- end }}
+      {{- if .Enabled }}
+      is_enabled = {{ BoolValue .Enabled }}
+      {{- end }}
+      {{- if .Policy }}
+      {{ jsonWarning .Policy }}
+      policy = <<POLICY
+      {{ jsonDocument .Policy }}
+POLICY
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- $key := . }}
+    {{- range .Aliases }}
+
+    resource "aws_kms_alias" "{{ aliasName . | sanitizeName }}" {
+      name = "{{ StringValue . }}"
+      target_key_id = "{{ StringValue $key.KeyID }}"
+    }
+    {{- end }}
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, k)
+}
+
+// WriteImports writes the terraform import commands of 'KMSKeys'
+func (k *KMSKeys) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"aliasName": kmsAliasName,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_kms_key.{{ sanitizeName .KeyID }} {{ .KeyID }}
+{{ range .Aliases -}}
+terraform import aws_kms_alias.{{ aliasName . | sanitizeName }} {{ . }}
+{{ end }}{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, k)
+}
+
+// kmsAliasName returns the name of an alias without its "alias/" prefix
+func kmsAliasName(alias *string) string {
+	return strings.TrimPrefix(aws.StringValue(alias), "alias/")
+}
+
+// kmsKeyName returns the key ID, or the "alias/..." name, of src which is
+// a key or alias ID or ARN
+func kmsKeyName(src *string) string {
+	name := aws.StringValue(src)
+	if strings.HasPrefix(name, "arn:") {
+		parts := strings.SplitN(name, ":", 6)
+		name = parts[len(parts)-1]
+	}
+
+	return strings.TrimPrefix(name, "key/")
+}
@@ -0,0 +1,257 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+)
+
+//**************** CloudWatch Log Group ****************
+type LogGroup struct {
+	Name            *string
+	RetentionInDays *int64
+	KmsKeyID        *string
+	Tags            *Tags
+
+	MetricFilters       []*LogMetricFilter
+	SubscriptionFilters []*LogSubscriptionFilter
+}
+
+type LogGroups []*LogGroup
+
+type LogMetricFilter struct {
+	Name            *string
+	Pattern         *string
+	Transformations []*cloudwatchlogs.MetricTransformation
+}
+
+type LogSubscriptionFilter struct {
+	Name           *string
+	Pattern        *string
+	DestinationARN *string
+	RoleARN        *string
+	Distribution   *string
+
+	// Set by ResolveReferences when the destination stream or the role
+	// is exported as well
+	DestinationExported *string
+	RoleExported        *string
+}
+
+// getFilters gets the metric filters and the subscription filters of the log group
+func (l *LogGroup) getFilters(ctx aws.Context, c *AWSClient) error {
+	mopt := &cloudwatchlogs.DescribeMetricFiltersInput{LogGroupName: l.Name}
+	for {
+		out, err := c.logsconn.DescribeMetricFiltersWithContext(ctx, mopt)
+		if err != nil {
+			return err
+		}
+		for _, v := range out.MetricFilters {
+			if v == nil {
+				continue
+			}
+			l.MetricFilters = append(l.MetricFilters, &LogMetricFilter{
+				Name:            v.FilterName,
+				Pattern:         v.FilterPattern,
+				Transformations: v.MetricTransformations,
+			})
+		}
+		if out.NextToken == nil {
+			break
+		}
+		mopt.NextToken = out.NextToken
+	}
+
+	sopt := &cloudwatchlogs.DescribeSubscriptionFiltersInput{LogGroupName: l.Name}
+	for {
+		out, err := c.logsconn.DescribeSubscriptionFiltersWithContext(ctx, sopt)
+		if err != nil {
+			return err
+		}
+		for _, v := range out.SubscriptionFilters {
+			if v == nil {
+				continue
+			}
+			l.SubscriptionFilters = append(l.SubscriptionFilters, &LogSubscriptionFilter{
+				Name:           v.FilterName,
+				Pattern:        v.FilterPattern,
+				DestinationARN: v.DestinationArn,
+				RoleARN:        v.RoleArn,
+				Distribution:   v.Distribution,
+			})
+		}
+		if out.NextToken == nil {
+			break
+		}
+		sopt.NextToken = out.NextToken
+	}
+
+	return nil
+}
+
+func (c *AWSClient) GetLogGroupsWithContext(ctx aws.Context) (*LogGroups, error) {
+	var res LogGroups
+
+	opt := &cloudwatchlogs.DescribeLogGroupsInput{}
+	for {
+		out, err := c.logsconn.DescribeLogGroupsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.LogGroups {
+			if v == nil || !c.wantID(v.LogGroupName, v.Arn) || !c.wantCreated(logsTime(v.CreationTime)) {
+				continue
+			}
+
+			tags, err := c.logsconn.ListTagsLogGroupWithContext(ctx, &cloudwatchlogs.ListTagsLogGroupInput{
+				LogGroupName: v.LogGroupName,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			tmp := &LogGroup{
+				Name:            v.LogGroupName,
+				RetentionInDays: v.RetentionInDays,
+				KmsKeyID:        v.KmsKeyId,
+				Tags:            &Tags{},
+			}
+			for k, v := range tags.Tags {
+				(*tmp.Tags)[k] = v
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+			if err := tmp.getFilters(ctx, c); err != nil {
+				return nil, err
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_cloudwatch_log_group", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// logsTime converts the milliseconds since the epoch CloudWatch Logs
+// returns to a time, nil stays nil
+func logsTime(ms *int64) *time.Time {
+	if ms == nil {
+		return nil
+	}
+	return aws.Time(aws.MillisecondsTimeValue(ms))
+}
+
+// GetLogGroups calls GetLogGroupsWithContext with a background context
+func (c *AWSClient) GetLogGroups() (*LogGroups, error) {
+	return c.GetLogGroupsWithContext(aws.BackgroundContext())
+}
+
+// filterLabel builds the label of a filter from the raw log group and filter
+// names, so the label prefix is applied once
+func filterLabel(group, name *string) string {
+	return sanitizeName(aws.StringValue(group) + "_" + aws.StringValue(name))
+}
+
+func (l *LogGroups) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"filterLabel":               filterLabel,
+		"makeTerraformResourceName": makeTerraformResourceName,
+	}
+
+	// Log groups which never expire have no retention, 0 is not valid
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_cloudwatch_log_group" .Name }}
+    resource "aws_cloudwatch_log_group" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      {{- if .RetentionInDays }}
+      retention_in_days = {{ .RetentionInDays }}
+      {{- end }}
+      {{- if .KmsKeyID }}
+      kms_key_id = "{{ .KmsKeyID }}"
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- $group := . }}
+    {{- range .MetricFilters }}
+    {{ resourceID "aws_cloudwatch_log_metric_filter" $group.Name .Name }}
+    resource "aws_cloudwatch_log_metric_filter" "{{ filterLabel $group.Name .Name }}" {
+      name = "{{ .Name }}"
+      pattern = {{ hclString .Pattern }}
+      log_group_name = "${aws_cloudwatch_log_group.{{ sanitizeName $group.Name }}.name}"
+      {{- range .Transformations }}
+      metric_transformation {
+        name = "{{ .MetricName }}"
+        namespace = "{{ .MetricNamespace }}"
+        value = {{ hclString .MetricValue }}
+        {{- if .DefaultValue }}
+        default_value = {{ .DefaultValue }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+    {{- range .SubscriptionFilters }}
+    {{ resourceID "aws_cloudwatch_log_subscription_filter" $group.Name .Name }}
+    resource "aws_cloudwatch_log_subscription_filter" "{{ filterLabel $group.Name .Name }}" {
+      name = "{{ .Name }}"
+      log_group_name = "${aws_cloudwatch_log_group.{{ sanitizeName $group.Name }}.name}"
+      filter_pattern = {{ hclString .Pattern }}
+      {{- with .DestinationExported }}
+      destination_arn = "${aws_kinesis_stream.{{ sanitizeName . }}.arn}"
+      {{- else }}
+      destination_arn = "{{ .DestinationARN }}"
+      {{- end }}
+      {{- with .RoleExported }}
+      role_arn = "${aws_iam_role.{{ . | makeTerraformResourceName | globalName }}.arn}"
+      {{- else }}
+      {{- if .RoleARN }}
+      role_arn = "{{ .RoleARN }}"
+      {{- end }}
+      {{- end }}
+      {{- if .Distribution }}
+      distribution = "{{ .Distribution }}"
+      {{- end }}
+    }
+    {{- end }}
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, l)
+}
+
+// WriteImports writes the terraform import commands of 'LogGroups'
+func (l *LogGroups) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"filterLabel": filterLabel,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_cloudwatch_log_group.{{ sanitizeName .Name }} {{ .Name }}
+{{ $group := . }}{{ range .MetricFilters -}}
+terraform import aws_cloudwatch_log_metric_filter.{{ filterLabel $group.Name .Name }} {{ $group.Name }}:{{ .Name }}
+{{ end }}{{ range .SubscriptionFilters -}}
+terraform import aws_cloudwatch_log_subscription_filter.{{ filterLabel $group.Name .Name }} '{{ $group.Name }}|{{ .Name }}'
+{{ end }}{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, l)
+}
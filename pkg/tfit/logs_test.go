@@ -0,0 +1,108 @@
+package tfit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+)
+
+func TestLogGroupsWriteHCLFilters(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{}
+
+	groups := &LogGroups{{
+		Name: aws.String("app"),
+		MetricFilters: []*LogMetricFilter{{
+			Name:    aws.String("errors"),
+			Pattern: aws.String("ERROR"),
+			Transformations: []*cloudwatchlogs.MetricTransformation{{
+				MetricName:      aws.String("ErrorCount"),
+				MetricNamespace: aws.String("App"),
+				MetricValue:     aws.String("1"),
+				DefaultValue:    aws.Float64(0),
+			}},
+		}},
+		SubscriptionFilters: []*LogSubscriptionFilter{{
+			Name:           aws.String("ship"),
+			Pattern:        aws.String(""),
+			DestinationARN: aws.String("arn:aws:kinesis:us-east-1:123456789012:stream/logs"),
+			RoleARN:        aws.String("arn:aws:iam::123456789012:role/cwl-to-kinesis"),
+			Distribution:   aws.String("ByLogStream"),
+		}},
+	}}
+	snapshot := &Snapshot{
+		LogGroups: groups,
+		KinesisStreams: &KinesisStreams{{
+			Name: aws.String("logs"),
+			ARN:  aws.String("arn:aws:kinesis:us-east-1:123456789012:stream/logs"),
+		}},
+	}
+	snapshot.ResolveReferences()
+
+	var buf bytes.Buffer
+	if err := groups.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	out := strings.Join(strings.Fields(buf.String()), " ")
+
+	for _, want := range []string{
+		`resource "aws_cloudwatch_log_metric_filter" "app_errors" { name = "errors" pattern = "ERROR" log_group_name = "${aws_cloudwatch_log_group.app.name}"`,
+		`metric_transformation { name = "ErrorCount" namespace = "App" value = "1" default_value = 0 }`,
+		`resource "aws_cloudwatch_log_subscription_filter" "app_ship" { name = "ship" log_group_name = "${aws_cloudwatch_log_group.app.name}" filter_pattern = ""`,
+		`destination_arn = "${aws_kinesis_stream.logs.arn}"`,
+		// The role is not exported, it stays literal
+		`role_arn = "arn:aws:iam::123456789012:role/cwl-to-kinesis"`,
+		`distribution = "ByLogStream"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want %s in:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := groups.WriteImports(&buf); err != nil {
+		t.Fatalf("WriteImports: %v", err)
+	}
+	for _, want := range []string{
+		"terraform import aws_cloudwatch_log_metric_filter.app_errors app:errors",
+		"terraform import aws_cloudwatch_log_subscription_filter.app_ship 'app|ship'",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("want %s in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestGetLogGroupsCreatedAfter(t *testing.T) {
+	createdAfter := time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)
+	ms := func(t time.Time) *int64 { return aws.Int64(t.UnixNano() / int64(time.Millisecond)) }
+
+	c := &AWSClient{CreatedAfter: createdAfter}
+	c.logsconn = cloudwatchlogs.New(stubSession())
+	stubSend(&c.logsconn.Handlers, func(r *request.Request) {
+		if out, ok := r.Data.(*cloudwatchlogs.DescribeLogGroupsOutput); ok {
+			out.LogGroups = []*cloudwatchlogs.LogGroup{
+				{LogGroupName: aws.String("old"), CreationTime: ms(createdAfter.Add(-time.Hour))},
+				{LogGroupName: aws.String("new"), CreationTime: ms(createdAfter.Add(time.Hour))},
+				{LogGroupName: aws.String("unknown")},
+			}
+		}
+	})
+
+	groups, err := c.GetLogGroups()
+	if err != nil {
+		t.Fatalf("GetLogGroups: %v", err)
+	}
+	var got []string
+	for _, v := range *groups {
+		got = append(got, aws.StringValue(v.Name))
+	}
+	if want := []string{"new", "unknown"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got log groups %v, want %v", got, want)
+	}
+}
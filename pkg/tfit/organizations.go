@@ -0,0 +1,145 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+)
+
+//**************** Organizations Account ****************
+type Account struct {
+	ID     *string
+	ARN    *string
+	Name   *string
+	Email  *string
+	Status *string
+
+	// Root or organizational unit of the account, only fetched by
+	// detailed clients
+	ParentID *string
+}
+
+type Accounts []*Account
+
+func (a *Account) set(src *organizations.Account) {
+	a.ID = src.Id
+	a.ARN = src.Arn
+	a.Name = src.Name
+	a.Email = src.Email
+	a.Status = src.Status
+}
+
+func (a *Account) getParent(ctx aws.Context, c *AWSClient) error {
+	out, err := c.orgconn.ListParentsWithContext(ctx, &organizations.ListParentsInput{ChildId: a.ID})
+	if err != nil {
+		return err
+	}
+
+	// An account has a single parent
+	if len(out.Parents) > 0 && out.Parents[0] != nil {
+		a.ParentID = out.Parents[0].Id
+	}
+
+	return nil
+}
+
+// GetAccountsWithContext returns the accounts of the organization. Only
+// the management account can list them: from any other account, or one
+// outside of an organization, no account is returned
+func (c *AWSClient) GetAccountsWithContext(ctx aws.Context) (*Accounts, error) {
+	// The Organizations API of the vendored SDK cannot list the tags of
+	// accounts, TagFilter excludes all of them
+	if c.untaggable() {
+		return &Accounts{}, nil
+	}
+
+	var res Accounts
+
+	opt := &organizations.ListAccountsInput{}
+	for {
+		out, err := c.orgconn.ListAccountsWithContext(ctx, opt)
+		if awsErr, ok := err.(awserr.Error); ok {
+			switch awsErr.Code() {
+			case organizations.ErrCodeAWSOrganizationsNotInUseException, organizations.ErrCodeAccessDeniedException:
+				c.logf("ListAccounts: %s, accounts are only listed from the management account", awsErr.Code())
+				return &Accounts{}, nil
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Accounts {
+			if v == nil || !c.wantID(v.Id, v.Arn, v.Name) {
+				continue
+			}
+
+			tmp := &Account{}
+			tmp.set(v)
+			if c.Detailed {
+				if err := tmp.getParent(ctx, c); err != nil {
+					return nil, err
+				}
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_organizations_account", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetAccounts calls GetAccountsWithContext with a background context
+func (c *AWSClient) GetAccounts() (*Accounts, error) {
+	return c.GetAccountsWithContext(aws.BackgroundContext())
+}
+
+func (a *Accounts) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"StringValue": aws.StringValue,
+	}
+
+	// Accounts can not be recreated, destroying one closes it or removes
+	// it from the organization. The blocks are meant to be imported
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_organizations_account" .ID }}
+    # NOTE: account {{ .ID }} can not be recreated, import it and do not destroy it
+    {{- if ne (StringValue .Status) "ACTIVE" }}
+    # WARNING: the account is {{ .Status }}
+    {{- end }}
+    resource "aws_organizations_account" "{{ StringValue .ID | printf "account_%s" | sanitizeName }}" {
+      name = {{ hclString .Name }}
+      email = "{{ .Email }}"
+      {{- if .ParentID }}
+      parent_id = "{{ .ParentID }}"
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, a)
+}
+
+// WriteImports writes the terraform import commands of 'Accounts'
+func (a *Accounts) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"StringValue": aws.StringValue,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_organizations_account.{{ StringValue .ID | printf "account_%s" | sanitizeName }} {{ .ID }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, a)
+}
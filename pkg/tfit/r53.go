@@ -18,7 +18,7 @@ type Route53Zone struct {
 	ZoneId          *string
 	DelegationSetId *string
 	NameServers     []*string
-	Tags            map[*string]*string
+	Tags            *Tags
 }
 
 type Zones []*Route53Zone
@@ -32,12 +32,12 @@ func (z *Route53Zone) set(data *route53.HostedZone) {
 	}
 }
 
-func (c *AWSClient) GetHostZones(maxRoutines int) (*Zones, error) {
+func (c *AWSClient) GetHostZonesWithContext(ctx aws.Context, maxRoutines int) (*Zones, error) {
 	r53 := c.r53conn
 	var res Zones
 	opt := &route53.ListHostedZonesInput{}
 	for {
-		zones, err := r53.ListHostedZones(opt)
+		zones, err := r53.ListHostedZonesWithContext(ctx, opt)
 		if err != nil {
 			return nil, err
 		}
@@ -48,8 +48,14 @@ func (c *AWSClient) GetHostZones(maxRoutines int) (*Zones, error) {
 
 			for _, v := range zones.HostedZones {
 				// Ignore Private hosted zone
-				if *v.Config.PrivateZone {
+				if v.Config != nil && safeBool(v.Config.PrivateZone, false) {
 					ch <- &chanItem{obj: nil, err: fmt.Errorf("Private Zone")}
+					continue
+				}
+
+				if !c.wantID(getZoneId(v.Id)) {
+					ch <- &chanItem{obj: nil, err: fmt.Errorf("Filtered out")}
+					continue
 				}
 
 				// Get lock
@@ -64,16 +70,26 @@ func (c *AWSClient) GetHostZones(maxRoutines int) (*Zones, error) {
 						ResourceType: aws.String("hostedzone"),
 					}
 
-					resp, err := r53.ListTagsForResource(req)
+					resp, err := r53.ListTagsForResourceWithContext(ctx, req)
 					if err != nil {
 						ch <- &chanItem{obj: nil, err: err}
+						<-lock
+						return
 					}
-					z.Tags = make(map[*string]*string)
-					if resp.ResourceTagSet != nil && resp.ResourceTagSet.Tags != nil {
-						for i := range resp.ResourceTagSet.Tags {
-							z.Tags[resp.ResourceTagSet.Tags[i].Key] = resp.ResourceTagSet.Tags[i].Value
+					z.Tags = &Tags{}
+					if resp.ResourceTagSet != nil {
+						for _, t := range resp.ResourceTagSet.Tags {
+							if t == nil || t.Key == nil {
+								continue
+							}
+							(*z.Tags)[*t.Key] = t.Value
 						}
 					}
+					if !c.matchTags(z.Tags) {
+						ch <- &chanItem{obj: nil, err: fmt.Errorf("Filtered out")}
+						<-lock
+						return
+					}
 
 					ch <- &chanItem{obj: z, err: nil}
 					<-lock
@@ -91,6 +107,8 @@ func (c *AWSClient) GetHostZones(maxRoutines int) (*Zones, error) {
 			}
 		}
 
+		c.progress("aws_route53_zone", len(res))
+
 		if zones.IsTruncated != nil && aws.BoolValue(zones.IsTruncated) {
 			opt.Marker = zones.NextMarker
 		} else {
@@ -101,21 +119,28 @@ func (c *AWSClient) GetHostZones(maxRoutines int) (*Zones, error) {
 	return &res, nil
 }
 
+// GetHostZones calls GetHostZonesWithContext with a background context
+func (c *AWSClient) GetHostZones(maxRoutines int) (*Zones, error) {
+	return c.GetHostZonesWithContext(aws.BackgroundContext(), maxRoutines)
+}
+
 func (zs *Zones) WriteHCL(w io.Writer) error {
 
 	tmpl := `
 		{{ if . }}
       {{ range . }}
       {{- $resource_name := TrimSuffix .Name "." }}
-				resource "aws_route53_zone" "{{ replace $resource_name "." "-" -1}}" {
+				{{ resourceID "aws_route53_zone" .ZoneId }}
+				resource "aws_route53_zone" "{{ replace $resource_name "." "-" -1 | sanitizeName }}" {
 					name = "{{ .Name }}"
           {{- if .Comment }}
-          comment = "{{ .Comment }}"
+          comment = {{ hclString .Comment }}
           {{- end}}
-          {{- if .Tags }}
+          {{- $tags := tags .Tags }}
+          {{- if $tags }}
           tags {
-						{{- range $k, $v := .Tags}}
-							"{{$k}}" = "{{$v}}"
+						{{- range $k, $v := $tags }}
+							{{ hclString $k }} = {{ hclString $v }}
             {{end}}
           }
 					{{end}}
@@ -131,6 +156,11 @@ func (zs *Zones) WriteHCL(w io.Writer) error {
 	return renderHCL(w, tmpl, funcMap, *zs)
 }
 
+// WriteImports writes the terraform import commands of 'Zones'
+func (z *Zones) WriteImports(w io.Writer) error {
+	return z.WriteTerraformImportCmd(w)
+}
+
 func (z *Zones) WriteTerraformImportCmd(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"replace":    strings.Replace,
@@ -141,7 +171,7 @@ func (z *Zones) WriteTerraformImportCmd(w io.Writer) error {
   {{if .}}
     {{- range .}}
     {{- $resource_name := TrimSuffix .Name "." }}
-terraform import aws_route53_zone.{{ replace $resource_name "." "-" -1}} {{.ZoneId}}
+terraform import aws_route53_zone.{{ replace $resource_name "." "-" -1 | sanitizeName }} {{.ZoneId}}
     {{- end}}
   {{- end}}
   `
@@ -175,22 +205,28 @@ func (r *RecordSet) setAlias(src *route53.AliasTarget) {
 
 func (r *RecordSet) setValue(src []*route53.ResourceRecord) {
 	for _, v := range src {
+		if v == nil {
+			continue
+		}
 		r.Records = append(r.Records, v.Value)
 	}
 }
 
-func (c *AWSClient) GetResourceRecordSets(ZoneId *string) (*RecordSets, error) {
+func (c *AWSClient) GetResourceRecordSetsWithContext(ctx aws.Context, ZoneId *string) (*RecordSets, error) {
 	r53 := c.r53conn
 	results := RecordSets{}
 
 	opt := &route53.ListResourceRecordSetsInput{HostedZoneId: ZoneId}
 	for {
-		records, err := r53.ListResourceRecordSets(opt)
+		records, err := r53.ListResourceRecordSetsWithContext(ctx, opt)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, v := range records.ResourceRecordSets {
+			if v == nil {
+				continue
+			}
 			r := RecordSet{}
 			if v.AliasTarget != nil {
 				r.setAlias(v.AliasTarget)
@@ -218,9 +254,14 @@ func (c *AWSClient) GetResourceRecordSets(ZoneId *string) (*RecordSets, error) {
 	return &results, nil
 }
 
-func (c *AWSClient) GetAllResourceRecordSets() (*RecordSets, error) {
+// GetResourceRecordSets calls GetResourceRecordSetsWithContext with a background context
+func (c *AWSClient) GetResourceRecordSets(ZoneId *string) (*RecordSets, error) {
+	return c.GetResourceRecordSetsWithContext(aws.BackgroundContext(), ZoneId)
+}
+
+func (c *AWSClient) GetAllResourceRecordSetsWithContext(ctx aws.Context) (*RecordSets, error) {
 	// Get all hosted zones
-	zones, err := c.GetHostZones(5)
+	zones, err := c.GetHostZonesWithContext(ctx, 5)
 	results := RecordSets{}
 
 	if err != nil {
@@ -230,7 +271,7 @@ func (c *AWSClient) GetAllResourceRecordSets() (*RecordSets, error) {
 	for _, v := range []*Route53Zone(*zones) {
 		//		prettyJson, err := url.QueryUnescape(aws.StringValue([]*Policy(*polices)[0].Document))
 		zId := v.ZoneId
-		r, err := c.GetResourceRecordSets(zId)
+		r, err := c.GetResourceRecordSetsWithContext(ctx, zId)
 		if err != nil {
 			return nil, err
 		}
@@ -241,6 +282,16 @@ func (c *AWSClient) GetAllResourceRecordSets() (*RecordSets, error) {
 
 }
 
+// GetAllResourceRecordSets calls GetAllResourceRecordSetsWithContext with a background context
+func (c *AWSClient) GetAllResourceRecordSets() (*RecordSets, error) {
+	return c.GetAllResourceRecordSetsWithContext(aws.BackgroundContext())
+}
+
+// WriteImports writes the terraform import commands of 'RecordSets'
+func (rs *RecordSets) WriteImports(w io.Writer) error {
+	return rs.WriteTerraformImportCmd(w)
+}
+
 func (rs *RecordSets) WriteTerraformImportCmd(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"joinstring":       joinStringSlice,
@@ -254,7 +305,7 @@ func (rs *RecordSets) WriteTerraformImportCmd(w io.Writer) error {
   {{if .}}
     {{range .}}
     {{- $resource_name := TrimSuffix .Name "." }}
-terraform import aws_route53_record.{{ replace $resource_name "." "_" -1 }}-{{.Type}} {{.ZoneId}}_{{TrimSuffix .Name "."}}_{{.Type}}
+terraform import aws_route53_record.{{ replace $resource_name "." "_" -1 | sanitizeName }}-{{.Type}} {{.ZoneId}}_{{TrimSuffix .Name "."}}_{{.Type}}
     {{- end}}
   {{- end}}
   `
@@ -276,7 +327,8 @@ func (rs *RecordSets) WriteHCL(w io.Writer) error {
 	{{if . }}
     {{ range . }}
     {{- $resource_name := TrimSuffix .Name "." }}
-			resource "aws_route53_record" "{{ replace $resource_name "." "_" -1 }}-{{.Type}}" {
+			{{ resourceID "aws_route53_record" .ZoneId .Name .Type }}
+			resource "aws_route53_record" "{{ replace $resource_name "." "_" -1 | sanitizeName }}-{{.Type}}" {
 				zone_id = "{{ .ZoneId }}"
 				name = "{{.Name}}"
         type = "{{.Type}}"
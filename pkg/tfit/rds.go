@@ -0,0 +1,586 @@
+package tfit
+
+import (
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// getRDSTags returns the tags of the RDS resource arn
+func (c *AWSClient) getRDSTags(ctx aws.Context, arn *string) (*Tags, error) {
+	out, err := c.rdsconn.ListTagsForResourceWithContext(ctx, &rds.ListTagsForResourceInput{ResourceName: arn})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Tags{}
+	for _, v := range out.TagList {
+		if v != nil && v.Key != nil {
+			(*res)[*v.Key] = v.Value
+		}
+	}
+
+	return res, nil
+}
+
+//**************** DB Subnet Group ****************
+type DBSubnetGroup struct {
+	Name        *string
+	ARN         *string
+	Description *string
+	SubnetIDs   []*string
+	Tags        *Tags
+}
+
+type DBSubnetGroups []*DBSubnetGroup
+
+func (g *DBSubnetGroup) set(src *rds.DBSubnetGroup) {
+	g.Name = src.DBSubnetGroupName
+	g.ARN = src.DBSubnetGroupArn
+	g.Description = src.DBSubnetGroupDescription
+
+	for _, v := range src.Subnets {
+		if v != nil && v.SubnetIdentifier != nil {
+			g.SubnetIDs = append(g.SubnetIDs, v.SubnetIdentifier)
+		}
+	}
+	sortStrings(g.SubnetIDs)
+}
+
+// GetDBSubnetGroupsWithContext returns the DB subnet groups, except the
+// "default" one of the default VPC whose name Terraform does not accept
+func (c *AWSClient) GetDBSubnetGroupsWithContext(ctx aws.Context) (*DBSubnetGroups, error) {
+	var res DBSubnetGroups
+
+	opt := &rds.DescribeDBSubnetGroupsInput{}
+	for {
+		out, err := c.rdsconn.DescribeDBSubnetGroupsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.DBSubnetGroups {
+			if v == nil || aws.StringValue(v.DBSubnetGroupName) == "default" || !c.wantID(v.DBSubnetGroupName, v.DBSubnetGroupArn) {
+				continue
+			}
+
+			tmp := &DBSubnetGroup{}
+			tmp.set(v)
+			if tmp.Tags, err = c.getRDSTags(ctx, tmp.ARN); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_db_subnet_group", len(res))
+
+		if out.Marker == nil {
+			break
+		}
+		opt.Marker = out.Marker
+	}
+
+	return &res, nil
+}
+
+// GetDBSubnetGroups calls GetDBSubnetGroupsWithContext with a background context
+func (c *AWSClient) GetDBSubnetGroups() (*DBSubnetGroups, error) {
+	return c.GetDBSubnetGroupsWithContext(aws.BackgroundContext())
+}
+
+func (g *DBSubnetGroups) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_db_subnet_group" .Name }}
+    resource "aws_db_subnet_group" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      {{- if .Description }}
+      description = {{ hclString .Description }}
+      {{- end }}
+      subnet_ids = [{{ StringValueSlice .SubnetIDs | joinstring "," }}]
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, g)
+}
+
+// WriteImports writes the terraform import commands of 'DBSubnetGroups'
+func (g *DBSubnetGroups) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_db_subnet_group.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, g)
+}
+
+//**************** DB Parameter Group ****************
+type DBParameterGroup struct {
+	Name        *string
+	ARN         *string
+	Family      *string
+	Description *string
+	Parameters  []*DBParameter
+	Tags        *Tags
+}
+
+// DBParameter is a parameter of a group set by the user
+type DBParameter struct {
+	Name        *string
+	Value       *string
+	ApplyMethod *string
+}
+
+type DBParameterGroups []*DBParameterGroup
+
+func (g *DBParameterGroup) set(src *rds.DBParameterGroup) {
+	g.Name = src.DBParameterGroupName
+	g.ARN = src.DBParameterGroupArn
+	g.Family = src.DBParameterGroupFamily
+	g.Description = src.Description
+}
+
+// getParameters gets the parameters of the group which are set by the
+// user, the ones left to the engine default are not rendered
+func (g *DBParameterGroup) getParameters(ctx aws.Context, c *AWSClient) error {
+	opt := &rds.DescribeDBParametersInput{DBParameterGroupName: g.Name, Source: aws.String("user")}
+	for {
+		out, err := c.rdsconn.DescribeDBParametersWithContext(ctx, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.Parameters {
+			if v == nil || v.ParameterName == nil {
+				continue
+			}
+			g.Parameters = append(g.Parameters, &DBParameter{v.ParameterName, v.ParameterValue, v.ApplyMethod})
+		}
+
+		if out.Marker == nil {
+			break
+		}
+		opt.Marker = out.Marker
+	}
+
+	return nil
+}
+
+// GetDBParameterGroupsWithContext returns the DB parameter groups with
+// their non default parameters. The default groups provided by AWS,
+// "default.<family>", are skipped
+func (c *AWSClient) GetDBParameterGroupsWithContext(ctx aws.Context) (*DBParameterGroups, error) {
+	var res DBParameterGroups
+
+	opt := &rds.DescribeDBParameterGroupsInput{}
+	for {
+		out, err := c.rdsconn.DescribeDBParameterGroupsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.DBParameterGroups {
+			if v == nil || strings.HasPrefix(aws.StringValue(v.DBParameterGroupName), "default.") || !c.wantID(v.DBParameterGroupName, v.DBParameterGroupArn) {
+				continue
+			}
+
+			tmp := &DBParameterGroup{}
+			tmp.set(v)
+			if tmp.Tags, err = c.getRDSTags(ctx, tmp.ARN); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+			if err := tmp.getParameters(ctx, c); err != nil {
+				return nil, err
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_db_parameter_group", len(res))
+
+		if out.Marker == nil {
+			break
+		}
+		opt.Marker = out.Marker
+	}
+
+	return &res, nil
+}
+
+// GetDBParameterGroups calls GetDBParameterGroupsWithContext with a background context
+func (c *AWSClient) GetDBParameterGroups() (*DBParameterGroups, error) {
+	return c.GetDBParameterGroupsWithContext(aws.BackgroundContext())
+}
+
+func (g *DBParameterGroups) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_db_parameter_group" .Name }}
+    resource "aws_db_parameter_group" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      family = "{{ .Family }}"
+      {{- if .Description }}
+      description = {{ hclString .Description }}
+      {{- end }}
+      {{- range .Parameters }}
+
+      parameter {
+        name = "{{ .Name }}"
+        value = {{ hclString .Value }}
+        {{- if .ApplyMethod }}
+        apply_method = "{{ .ApplyMethod }}"
+        {{- end }}
+      }
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, g)
+}
+
+// WriteImports writes the terraform import commands of 'DBParameterGroups'
+func (g *DBParameterGroups) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_db_parameter_group.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, g)
+}
+
+//**************** RDS Cluster ****************
+type DBCluster struct {
+	Identifier                 *string
+	ARN                        *string
+	Engine                     *string
+	EngineMode                 *string
+	EngineVersion              *string
+	DatabaseName               *string
+	MasterUsername             *string
+	SubnetGroupName            *string
+	ParameterGroupName         *string
+	VPCSecurityGroupIDs        []*string
+	Port                       *int64
+	BackupRetentionPeriod      *int64
+	PreferredBackupWindow      *string
+	PreferredMaintenanceWindow *string
+	StorageEncrypted           *bool
+	KMSKeyID                   *string
+	DeletionProtection         *bool
+	Instances                  []*DBClusterInstance
+	Tags                       *Tags
+
+	// Set by ResolveReferences when the subnet group is exported as well
+	SubnetGroupExported bool
+}
+
+// DBClusterInstance is a writer or reader instance of a cluster
+type DBClusterInstance struct {
+	Identifier         *string
+	ARN                *string
+	InstanceClass      *string
+	Engine             *string
+	EngineVersion      *string
+	PromotionTier      *int64
+	PubliclyAccessible *bool
+	AvailabilityZone   *string
+	ParameterGroupName *string
+	Tags               *Tags
+
+	// Set by ResolveReferences when the parameter group is exported as well
+	ParameterGroupExported bool
+}
+
+type DBClusters []*DBCluster
+
+func (d *DBCluster) set(src *rds.DBCluster) {
+	d.Identifier = src.DBClusterIdentifier
+	d.ARN = src.DBClusterArn
+	d.Engine = src.Engine
+	d.EngineMode = src.EngineMode
+	d.EngineVersion = src.EngineVersion
+	d.DatabaseName = src.DatabaseName
+	d.MasterUsername = src.MasterUsername
+	d.SubnetGroupName = src.DBSubnetGroup
+	d.ParameterGroupName = src.DBClusterParameterGroup
+	d.Port = src.Port
+	d.BackupRetentionPeriod = src.BackupRetentionPeriod
+	d.PreferredBackupWindow = src.PreferredBackupWindow
+	d.PreferredMaintenanceWindow = src.PreferredMaintenanceWindow
+	d.StorageEncrypted = src.StorageEncrypted
+	d.KMSKeyID = src.KmsKeyId
+	d.DeletionProtection = src.DeletionProtection
+
+	for _, v := range src.VpcSecurityGroups {
+		if v != nil && v.VpcSecurityGroupId != nil {
+			d.VPCSecurityGroupIDs = append(d.VPCSecurityGroupIDs, v.VpcSecurityGroupId)
+		}
+	}
+	sortStrings(d.VPCSecurityGroupIDs)
+}
+
+func (i *DBClusterInstance) set(src *rds.DBInstance) {
+	i.Identifier = src.DBInstanceIdentifier
+	i.ARN = src.DBInstanceArn
+	i.InstanceClass = src.DBInstanceClass
+	i.Engine = src.Engine
+	i.EngineVersion = src.EngineVersion
+	i.PromotionTier = src.PromotionTier
+	i.PubliclyAccessible = src.PubliclyAccessible
+	i.AvailabilityZone = src.AvailabilityZone
+
+	if len(src.DBParameterGroups) > 0 && src.DBParameterGroups[0] != nil {
+		i.ParameterGroupName = src.DBParameterGroups[0].DBParameterGroupName
+	}
+}
+
+// getInstances gets the writer & reader instances of the cluster. They
+// belong to the cluster, whatever the IDs and TagFilter filters
+func (d *DBCluster) getInstances(ctx aws.Context, c *AWSClient) error {
+	opt := &rds.DescribeDBInstancesInput{
+		Filters: []*rds.Filter{{Name: aws.String("db-cluster-id"), Values: []*string{d.Identifier}}},
+	}
+	for {
+		out, err := c.rdsconn.DescribeDBInstancesWithContext(ctx, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.DBInstances {
+			if v == nil || aws.StringValue(v.DBInstanceStatus) == "deleting" {
+				continue
+			}
+
+			tmp := &DBClusterInstance{}
+			tmp.set(v)
+			if tmp.Tags, err = c.getRDSTags(ctx, tmp.ARN); err != nil {
+				return err
+			}
+
+			d.Instances = append(d.Instances, tmp)
+		}
+
+		if out.Marker == nil {
+			break
+		}
+		opt.Marker = out.Marker
+	}
+
+	return nil
+}
+
+// GetDBClustersWithContext returns the Aurora & RDS clusters which are not
+// being deleted, with their instances
+func (c *AWSClient) GetDBClustersWithContext(ctx aws.Context) (*DBClusters, error) {
+	var res DBClusters
+
+	opt := &rds.DescribeDBClustersInput{}
+	for {
+		out, err := c.rdsconn.DescribeDBClustersWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.DBClusters {
+			if v == nil || aws.StringValue(v.Status) == "deleting" ||
+				!c.wantID(v.DBClusterIdentifier, v.DBClusterArn) || !c.wantCreated(v.ClusterCreateTime) {
+				continue
+			}
+
+			tmp := &DBCluster{}
+			tmp.set(v)
+			if tmp.Tags, err = c.getRDSTags(ctx, tmp.ARN); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+			if err := tmp.getInstances(ctx, c); err != nil {
+				return nil, err
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_rds_cluster", len(res))
+
+		if out.Marker == nil {
+			break
+		}
+		opt.Marker = out.Marker
+	}
+
+	return &res, nil
+}
+
+// GetDBClusters calls GetDBClustersWithContext with a background context
+func (c *AWSClient) GetDBClusters() (*DBClusters, error) {
+	return c.GetDBClustersWithContext(aws.BackgroundContext())
+}
+
+func (d *DBClusters) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+		"BoolValue":        aws.BoolValue,
+		"StringValue":      aws.StringValue,
+	}
+
+	// The master password can not be read back, it is a placeholder to fill
+	// in, ignored once the cluster is created
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{- $cluster := sanitizeName .Identifier }}
+    {{ resourceID "aws_rds_cluster" .Identifier }}
+    resource "aws_rds_cluster" "{{ $cluster }}" {
+      cluster_identifier = "{{ .Identifier }}"
+      engine = "{{ .Engine }}"
+      {{- if and .EngineMode (ne (StringValue .EngineMode) "provisioned") }}
+      engine_mode = "{{ .EngineMode }}"
+      {{- end }}
+      {{- if .EngineVersion }}
+      engine_version = "{{ .EngineVersion }}"
+      {{- end }}
+      {{- if .DatabaseName }}
+      database_name = "{{ .DatabaseName }}"
+      {{- end }}
+      master_username = "{{ .MasterUsername }}"
+      # WARNING: the master password can not be read, set it before creating the cluster
+      master_password = "REDACTED"
+      {{- if .SubnetGroupExported }}
+      db_subnet_group_name = "${aws_db_subnet_group.{{ sanitizeName .SubnetGroupName }}.name}"
+      {{- else if .SubnetGroupName }}
+      db_subnet_group_name = "{{ .SubnetGroupName }}"
+      {{- end }}
+      {{- if .ParameterGroupName }}
+      db_cluster_parameter_group_name = "{{ .ParameterGroupName }}"
+      {{- end }}
+      {{- if .VPCSecurityGroupIDs }}
+      vpc_security_group_ids = [{{ StringValueSlice .VPCSecurityGroupIDs | joinstring "," }}]
+      {{- end }}
+      {{- if .Port }}
+      port = {{ .Port }}
+      {{- end }}
+      {{- if .BackupRetentionPeriod }}
+      backup_retention_period = {{ .BackupRetentionPeriod }}
+      {{- end }}
+      {{- if .PreferredBackupWindow }}
+      preferred_backup_window = "{{ .PreferredBackupWindow }}"
+      {{- end }}
+      {{- if .PreferredMaintenanceWindow }}
+      preferred_maintenance_window = "{{ .PreferredMaintenanceWindow }}"
+      {{- end }}
+      {{- if BoolValue .StorageEncrypted }}
+      storage_encrypted = true
+      {{- end }}
+      {{- if .KMSKeyID }}
+      kms_key_id = "{{ .KMSKeyID }}"
+      {{- end }}
+      {{- if BoolValue .DeletionProtection }}
+      deletion_protection = true
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+
+      lifecycle {
+        ignore_changes = ["master_password"]
+      }
+    }
+    {{- range .Instances }}
+
+    {{ resourceID "aws_rds_cluster_instance" .Identifier }}
+    resource "aws_rds_cluster_instance" "{{ sanitizeName .Identifier }}" {
+      identifier = "{{ .Identifier }}"
+      cluster_identifier = "${aws_rds_cluster.{{ $cluster }}.id}"
+      instance_class = "{{ .InstanceClass }}"
+      engine = "{{ .Engine }}"
+      {{- if .EngineVersion }}
+      engine_version = "{{ .EngineVersion }}"
+      {{- end }}
+      {{- if .PromotionTier }}
+      promotion_tier = {{ .PromotionTier }}
+      {{- end }}
+      {{- if BoolValue .PubliclyAccessible }}
+      publicly_accessible = true
+      {{- end }}
+      {{- if .AvailabilityZone }}
+      availability_zone = "{{ .AvailabilityZone }}"
+      {{- end }}
+      {{- if .ParameterGroupExported }}
+      db_parameter_group_name = "${aws_db_parameter_group.{{ sanitizeName .ParameterGroupName }}.name}"
+      {{- else if .ParameterGroupName }}
+      db_parameter_group_name = "{{ .ParameterGroupName }}"
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, d)
+}
+
+// WriteImports writes the terraform import commands of 'DBClusters' and
+// of their instances
+func (d *DBClusters) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_rds_cluster.{{ sanitizeName .Identifier }} {{ .Identifier }}
+{{ range .Instances -}}
+terraform import aws_rds_cluster_instance.{{ sanitizeName .Identifier }} {{ .Identifier }}
+{{ end -}}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, d)
+}
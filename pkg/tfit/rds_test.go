@@ -0,0 +1,34 @@
+package tfit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestDBClustersMasterPassword(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{}
+
+	clusters := &DBClusters{{
+		Identifier:     aws.String("shop"),
+		Engine:         aws.String("aurora-mysql"),
+		MasterUsername: aws.String("admin"),
+	}}
+
+	var buf bytes.Buffer
+	if err := clusters.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	out := strings.Join(strings.Fields(buf.String()), " ")
+	for _, want := range []string{
+		`master_password = "REDACTED"`,
+		`lifecycle { ignore_changes = ["master_password"] }`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want %s in:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/redshift"
+)
+
+//**************** Redshift Cluster ****************
+type RedshiftCluster struct {
+	ClusterIdentifier      *string
+	NodeType               *string
+	ClusterType            string
+	NumberOfNodes          *int64
+	DatabaseName           *string
+	MasterUsername         *string
+	ClusterSubnetGroupName *string
+	ParameterGroupName     *string
+	VPCSecurityGroupIDs    []*string
+	AvailabilityZone       *string
+	Port                   *int64
+	PubliclyAccessible     *bool
+	Encrypted              *bool
+	KmsKeyID               *string
+	Tags                   *Tags
+}
+
+type RedshiftClusters []*RedshiftCluster
+
+func (r *RedshiftCluster) set(src *redshift.Cluster) {
+	r.ClusterIdentifier = src.ClusterIdentifier
+	r.NodeType = src.NodeType
+	r.NumberOfNodes = src.NumberOfNodes
+	r.DatabaseName = src.DBName
+	r.MasterUsername = src.MasterUsername
+	r.ClusterSubnetGroupName = src.ClusterSubnetGroupName
+	r.AvailabilityZone = src.AvailabilityZone
+	r.PubliclyAccessible = src.PubliclyAccessible
+	r.Encrypted = src.Encrypted
+	r.KmsKeyID = src.KmsKeyId
+
+	r.ClusterType = "multi-node"
+	if aws.Int64Value(src.NumberOfNodes) == 1 {
+		r.ClusterType = "single-node"
+	}
+
+	if src.Endpoint != nil {
+		r.Port = src.Endpoint.Port
+	}
+	if len(src.ClusterParameterGroups) > 0 && src.ClusterParameterGroups[0] != nil {
+		r.ParameterGroupName = src.ClusterParameterGroups[0].ParameterGroupName
+	}
+
+	for _, v := range src.VpcSecurityGroups {
+		if v != nil && v.VpcSecurityGroupId != nil {
+			r.VPCSecurityGroupIDs = append(r.VPCSecurityGroupIDs, v.VpcSecurityGroupId)
+		}
+	}
+	sortStrings(r.VPCSecurityGroupIDs)
+
+	r.Tags = &Tags{}
+	for _, v := range src.Tags {
+		if v != nil && v.Key != nil {
+			(*r.Tags)[*v.Key] = v.Value
+		}
+	}
+}
+
+// GetRedshiftClustersWithContext returns the clusters which are not being deleted
+func (c *AWSClient) GetRedshiftClustersWithContext(ctx aws.Context) (*RedshiftClusters, error) {
+	var res RedshiftClusters
+
+	opt := &redshift.DescribeClustersInput{}
+	for {
+		out, err := c.redshiftconn.DescribeClustersWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Clusters {
+			if v == nil || aws.StringValue(v.ClusterStatus) == "deleting" ||
+				!c.wantID(v.ClusterIdentifier) || !c.wantCreated(v.ClusterCreateTime) {
+				continue
+			}
+
+			tmp := &RedshiftCluster{}
+			tmp.set(v)
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_redshift_cluster", len(res))
+
+		if out.Marker == nil {
+			break
+		}
+		opt.Marker = out.Marker
+	}
+
+	return &res, nil
+}
+
+// GetRedshiftClusters calls GetRedshiftClustersWithContext with a background context
+func (c *AWSClient) GetRedshiftClusters() (*RedshiftClusters, error) {
+	return c.GetRedshiftClustersWithContext(aws.BackgroundContext())
+}
+
+func (r *RedshiftClusters) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+		"BoolValue":        aws.BoolValue,
+	}
+
+	// The master password can not be read back, it is a placeholder to fill in
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_redshift_cluster" .ClusterIdentifier }}
+    resource "aws_redshift_cluster" "{{ sanitizeName .ClusterIdentifier }}" {
+      cluster_identifier = "{{ .ClusterIdentifier }}"
+      node_type = "{{ .NodeType }}"
+      cluster_type = "{{ .ClusterType }}"
+      number_of_nodes = {{ .NumberOfNodes }}
+      {{- if .DatabaseName }}
+      database_name = "{{ .DatabaseName }}"
+      {{- end }}
+      master_username = "{{ .MasterUsername }}"
+      # WARNING: the master password can not be read, set it before creating the cluster
+      master_password = "REDACTED"
+      {{- if .ClusterSubnetGroupName }}
+      cluster_subnet_group_name = "{{ .ClusterSubnetGroupName }}"
+      {{- end }}
+      {{- if .ParameterGroupName }}
+      cluster_parameter_group_name = "{{ .ParameterGroupName }}"
+      {{- end }}
+      {{- if .VPCSecurityGroupIDs }}
+      vpc_security_group_ids = [{{ StringValueSlice .VPCSecurityGroupIDs | joinstring "," }}]
+      {{- end }}
+      {{- if .AvailabilityZone }}
+      availability_zone = "{{ .AvailabilityZone }}"
+      {{- end }}
+      {{- if .Port }}
+      port = {{ .Port }}
+      {{- end }}
+      {{- if .PubliclyAccessible }}
+      publicly_accessible = {{ BoolValue .PubliclyAccessible }}
+      {{- end }}
+      {{- if .Encrypted }}
+      encrypted = {{ BoolValue .Encrypted }}
+      {{- end }}
+      {{- if .KmsKeyID }}
+      kms_key_id = "{{ .KmsKeyID }}"
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+
+      lifecycle {
+        ignore_changes = ["master_password"]
+      }
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, r)
+}
+
+// WriteImports writes the terraform import commands of 'RedshiftClusters'
+func (r *RedshiftClusters) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_redshift_cluster.{{ sanitizeName .ClusterIdentifier }} {{ .ClusterIdentifier }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, r)
+}
@@ -1,6 +1,7 @@
 package tfit
 
 import (
+	"encoding/json"
 	"io"
 	"strings"
 	"text/template"
@@ -21,24 +22,49 @@ type S3LifecycleRule struct {
 type BucketVersioning struct {
 	Enabled   *bool
 	MFADelete *bool
+
+	// Suspended is set when versioning was enabled then suspended, the
+	// versions created meanwhile are kept
+	Suspended *bool
+}
+
+// BucketWebsite is the website configuration of a bucket, either an index
+// document or a redirection of every request
+type BucketWebsite struct {
+	IndexDocument    *string
+	ErrorDocument    *string
+	RedirectHostName *string
+	RedirectProtocol *string
+	RoutingRules     *string
 }
 
 type Bucket struct {
 	Name                              *string
+	Region                            *string
 	Policy                            *string
-	Website                           *s3.GetBucketWebsiteOutput
+	Website                           *BucketWebsite
 	LifecycleRules                    []*S3LifecycleRule
 	ReplicationConfiguration          *s3.ReplicationConfiguration
 	ServerSideEncryptionConfiguration *s3.ServerSideEncryptionConfiguration
 	CORSRules                         []*s3.CORSRule
 	Logging                           *s3.LoggingEnabled
 	Versioning                        *BucketVersioning
+	Tags                              *Tags
+
+	// Set by ResolveReferences to the ID of the exported aws_kms_key the
+	// default encryption uses
+	SSEKMSKeyExported *string
+
+	// Set by ResolveReferences to the name of the exported replication role
+	// and to the names of the exported destination buckets, by ARN
+	ReplicationRoleExported    *string
+	ReplicationBucketsExported map[string]*string
 }
 
 type Buckets []*Bucket
 
-func (b *Bucket) getBucketPoliy(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketPolicy(&s3.GetBucketPolicyInput{Bucket: b.Name})
+func (b *Bucket) getBucketPoliy(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3connFor(b.Region).GetBucketPolicyWithContext(ctx, &s3.GetBucketPolicyInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
@@ -48,21 +74,103 @@ func (b *Bucket) getBucketPoliy(c *AWSClient) error {
 	return nil
 }
 
-func (b *Bucket) getWebsite(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketWebsite(&s3.GetBucketWebsiteInput{Bucket: b.Name})
+func (b *Bucket) getTags(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3connFor(b.Region).GetBucketTaggingWithContext(ctx, &s3.GetBucketTaggingInput{Bucket: b.Name})
+	b.Tags = &Tags{}
+	if err != nil {
+		return handleError(err)
+	}
+
+	for _, v := range output.TagSet {
+		if v == nil || v.Key == nil {
+			continue
+		}
+		(*b.Tags)[*v.Key] = v.Value
+	}
+
+	return nil
+}
+
+func (b *Bucket) getWebsite(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3connFor(b.Region).GetBucketWebsiteWithContext(ctx, &s3.GetBucketWebsiteInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
 
-	b.Website = output
+	website := &BucketWebsite{}
+	if output.IndexDocument != nil {
+		website.IndexDocument = output.IndexDocument.Suffix
+	}
+	if output.ErrorDocument != nil {
+		website.ErrorDocument = output.ErrorDocument.Key
+	}
+	if output.RedirectAllRequestsTo != nil {
+		website.RedirectHostName = output.RedirectAllRequestsTo.HostName
+		website.RedirectProtocol = output.RedirectAllRequestsTo.Protocol
+	}
+	if len(output.RoutingRules) > 0 {
+		rules, err := routingRules(output.RoutingRules)
+		if err != nil {
+			return err
+		}
+		website.RoutingRules = rules
+	}
+
+	if website.IndexDocument != nil || website.RedirectHostName != nil {
+		b.Website = website
+	}
 
 	return nil
 }
 
+// replicationFilterTags returns the tags a replication rule filter matches
+func replicationFilterTags(src *s3.ReplicationRuleFilter) map[string]string {
+	res := make(map[string]string)
+	if src == nil {
+		return res
+	}
+
+	tags := []*s3.Tag{src.Tag}
+	if src.And != nil {
+		tags = src.And.Tags
+	}
+	for _, v := range tags {
+		if v != nil && v.Key != nil {
+			res[*v.Key] = aws.StringValue(v.Value)
+		}
+	}
+
+	return res
+}
+
+// routingRules returns src as the JSON document expected by routing_rules,
+// without the null members of the SDK structs
+func routingRules(src []*s3.RoutingRule) (*string, error) {
+	b, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	b, err = json.Marshal(omitNulls(doc))
+	if err != nil {
+		return nil, err
+	}
+
+	return aws.String(string(b)), nil
+}
+
 func (b *Bucket) setLifecycleRule(src []*s3.LifecycleRule) error {
 	b.LifecycleRules = make([]*S3LifecycleRule, len(src))
 	for i := range src {
 		b.LifecycleRules[i] = &S3LifecycleRule{}
+		if src[i] == nil {
+			continue
+		}
 		b.LifecycleRules[i].ID = src[i].ID
 		b.LifecycleRules[i].Prefix = src[i].Prefix
 		b.LifecycleRules[i].Transition = src[i].Transitions
@@ -76,23 +184,19 @@ func (b *Bucket) setLifecycleRule(src []*s3.LifecycleRule) error {
 	return nil
 }
 
-func (b *Bucket) getBucketLocation(c *AWSClient) (*string, error) {
-	output, err := c.s3conn.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: b.Name})
+// getBucketLocation sets the region of the bucket
+func (b *Bucket) getBucketLocation(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3conn.GetBucketLocationWithContext(ctx, &s3.GetBucketLocationInput{Bucket: b.Name})
 	if err != nil {
-		e := handleError(err)
-		if e != nil {
-			return nil, e
-		} else {
-			return nil, nil
-		}
+		return handleError(err)
 	}
 
-	return output.LocationConstraint, nil
-
+	b.Region = aws.String(s3.NormalizeBucketLocation(aws.StringValue(output.LocationConstraint)))
+	return nil
 }
 
-func (b *Bucket) getReplicationConfiguration(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketReplication(&s3.GetBucketReplicationInput{Bucket: b.Name})
+func (b *Bucket) getReplicationConfiguration(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3connFor(b.Region).GetBucketReplicationWithContext(ctx, &s3.GetBucketReplicationInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
@@ -101,8 +205,8 @@ func (b *Bucket) getReplicationConfiguration(c *AWSClient) error {
 	return nil
 }
 
-func (b *Bucket) getLifecycleRules(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketLifecycleConfiguration(&s3.GetBucketLifecycleConfigurationInput{Bucket: b.Name})
+func (b *Bucket) getLifecycleRules(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3connFor(b.Region).GetBucketLifecycleConfigurationWithContext(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
@@ -110,8 +214,8 @@ func (b *Bucket) getLifecycleRules(c *AWSClient) error {
 	return b.setLifecycleRule(output.Rules)
 }
 
-func (b *Bucket) getServerSideEncryptionConfiguration(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketEncryption(&s3.GetBucketEncryptionInput{Bucket: b.Name})
+func (b *Bucket) getServerSideEncryptionConfiguration(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3connFor(b.Region).GetBucketEncryptionWithContext(ctx, &s3.GetBucketEncryptionInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
@@ -121,8 +225,8 @@ func (b *Bucket) getServerSideEncryptionConfiguration(c *AWSClient) error {
 	return nil
 }
 
-func (b *Bucket) getLogging(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketLogging(&s3.GetBucketLoggingInput{Bucket: b.Name})
+func (b *Bucket) getLogging(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3connFor(b.Region).GetBucketLoggingWithContext(ctx, &s3.GetBucketLoggingInput{Bucket: b.Name})
 	if err != nil {
 		return err
 	}
@@ -132,8 +236,8 @@ func (b *Bucket) getLogging(c *AWSClient) error {
 	return nil
 }
 
-func (b *Bucket) getCORSRule(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketCors(&s3.GetBucketCorsInput{Bucket: b.Name})
+func (b *Bucket) getCORSRule(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3connFor(b.Region).GetBucketCorsWithContext(ctx, &s3.GetBucketCorsInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
@@ -142,8 +246,8 @@ func (b *Bucket) getCORSRule(c *AWSClient) error {
 	return nil
 }
 
-func (b *Bucket) getVersioning(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketVersioning(&s3.GetBucketVersioningInput{Bucket: b.Name})
+func (b *Bucket) getVersioning(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3connFor(b.Region).GetBucketVersioningWithContext(ctx, &s3.GetBucketVersioningInput{Bucket: b.Name})
 	if err != nil {
 		return err
 	}
@@ -151,6 +255,9 @@ func (b *Bucket) getVersioning(c *AWSClient) error {
 	if output.Status != nil && aws.StringValue(output.Status) == s3.BucketVersioningStatusEnabled {
 		b.Versioning.Enabled = aws.Bool(true)
 	}
+	if aws.StringValue(output.Status) == s3.BucketVersioningStatusSuspended {
+		b.Versioning.Suspended = aws.Bool(true)
+	}
 
 	if output.MFADelete != nil && aws.StringValue(output.MFADelete) == s3.MFADeleteStatusEnabled {
 		b.Versioning.MFADelete = aws.Bool(true)
@@ -161,53 +268,58 @@ func (b *Bucket) getVersioning(c *AWSClient) error {
 	return nil
 }
 
-func (b *Bucket) GetBucketDetails(c *AWSClient) error {
+func (b *Bucket) GetBucketDetailsWithContext(ctx aws.Context, c *AWSClient) error {
 	// Get Bucket Policy
-	if err := b.getBucketPoliy(c); err != nil {
+	if err := b.getBucketPoliy(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Website detail
-	if err := b.getWebsite(c); err != nil {
+	if err := b.getWebsite(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Lifecycle Rules
-	if err := b.getLifecycleRules(c); err != nil {
+	if err := b.getLifecycleRules(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Replication Configuration
-	if err := b.getReplicationConfiguration(c); err != nil {
+	if err := b.getReplicationConfiguration(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Server Side Encryption
-	if err := b.getServerSideEncryptionConfiguration(c); err != nil {
+	if err := b.getServerSideEncryptionConfiguration(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Logging
-	if err := b.getLogging(c); err != nil {
+	if err := b.getLogging(ctx, c); err != nil {
 		return err
 	}
 
 	// Get CORS Rules
-	if err := b.getCORSRule(c); err != nil {
+	if err := b.getCORSRule(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Versioning
-	if err := b.getVersioning(c); err != nil {
+	if err := b.getVersioning(ctx, c); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *AWSClient) GetBuckets() (*Buckets, error) {
+// GetBucketDetails calls GetBucketDetailsWithContext with a background context
+func (b *Bucket) GetBucketDetails(c *AWSClient) error {
+	return b.GetBucketDetailsWithContext(aws.BackgroundContext(), c)
+}
+
+func (c *AWSClient) GetBucketsWithContext(ctx aws.Context) (*Buckets, error) {
 	var res Buckets
-	output, err := c.s3conn.ListBuckets(&s3.ListBucketsInput{})
+	output, err := c.s3conn.ListBucketsWithContext(ctx, &s3.ListBucketsInput{})
 	if err != nil {
 		return nil, err
 	}
@@ -215,7 +327,7 @@ func (c *AWSClient) GetBuckets() (*Buckets, error) {
 	/*
 		bucketName := "internal.sw.prd.cost-management"
 		bucket := &Bucket{Name: aws.String(bucketName)}
-		if err := bucket.GetBucketDetails(c); err != nil {
+		if err := bucket.GetBucketDetailsWithContext(ctx, c); err != nil {
 			return nil, err
 		}
 		res = append(res, bucket)
@@ -224,28 +336,38 @@ func (c *AWSClient) GetBuckets() (*Buckets, error) {
 	blk := make(chan struct{}, 10)
 
 	for _, obj := range output.Buckets {
+		if !c.wantID(obj.Name) || !c.wantCreated(obj.CreationDate) {
+			ch <- &chanItem{}
+			continue
+		}
+
 		blk <- struct{}{}
 		go func(obj *s3.Bucket) {
+			defer func() { <-blk }()
+
+			// Buckets of every region are listed, their details are
+			// fetched from their region
 			bucket := &Bucket{Name: obj.Name}
-			region, err := bucket.getBucketLocation(c)
-			if err != nil {
-				panic(err)
+			if err := bucket.getBucketLocation(ctx, c); err != nil {
+				ch <- &chanItem{err: err}
+				return
 			}
 
-			// Ignore buckets in different region now
-			if region != nil {
+			if err := bucket.getTags(ctx, c); err != nil {
+				ch <- &chanItem{err: err}
+				return
+			}
+			if !c.matchTags(bucket.Tags) {
 				ch <- &chanItem{}
 				return
 			}
 
-			if err := bucket.GetBucketDetails(c); err != nil {
+			if err := bucket.GetBucketDetailsWithContext(ctx, c); err != nil {
 				ch <- &chanItem{err: err}
 				return
 			}
 
 			ch <- &chanItem{obj: bucket, err: err}
-			<-blk
-
 		}(obj)
 
 	}
@@ -253,7 +375,7 @@ func (c *AWSClient) GetBuckets() (*Buckets, error) {
 	for range output.Buckets {
 		receiver := <-ch
 		if receiver.err != nil {
-			return nil, err
+			return nil, receiver.err
 		}
 
 		if receiver.obj == nil {
@@ -266,21 +388,51 @@ func (c *AWSClient) GetBuckets() (*Buckets, error) {
 	return &res, nil
 }
 
+// GetBuckets calls GetBucketsWithContext with a background context
+func (c *AWSClient) GetBuckets() (*Buckets, error) {
+	return c.GetBucketsWithContext(aws.BackgroundContext())
+}
+
 func (b *Buckets) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"joinstring":       joinStringSlice,
 		"StringValueSlice": aws.StringValueSlice,
 		"replace":          strings.Replace,
-		"prettyJSON":       prettyJSON,
+		"providerV4":       func() bool { return RenderOpts.ProviderV4 },
+		"BoolValue":        aws.BoolValue,
+		"StringValue":      aws.StringValue,
+
+		"makeTerraformResourceName": makeTerraformResourceName,
+		"replicationFilterTags":     replicationFilterTags,
 	}
 
 	tmpl := `
   {{- if .}}
+    {{- $split := providerV4 }}
     {{- range .}}
-    resource "aws_s3_bucket" "{{ replace .Name "." "_" -1 }}" {
+    {{- $name := replace .Name "." "_" -1 | sanitizeName }}
+    {{- $region := .Region }}
+    {{- $provider := regionProvider .Region }}
+    {{ resourceID "aws_s3_bucket" .Name }}
+    {{- if otherRegion .Region }}
+    # WARNING: the bucket is in {{ .Region }}, bind it to a provider of that region
+    {{- end }}
+    resource "aws_s3_bucket" "{{ $name }}" {
+      {{- with $provider }}
+      provider = "{{ . }}"
+      {{- end }}
       bucket = "{{ .Name }}"
 
-      {{- if .Logging}}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+
+      {{- if and (not $split) .Logging}}
       logging {
         {{- if .Logging.TargetBucket}}
         target_bucket = "{{ .Logging.TargetBucket }}"
@@ -291,13 +443,30 @@ func (b *Buckets) WriteHCL(w io.Writer) error {
       }
       {{- end}}
 
+      {{- if and (not $split) .Website }}
+      website {
+        {{- if .Website.RedirectHostName }}
+        redirect_all_requests_to = "{{ if .Website.RedirectProtocol }}{{ .Website.RedirectProtocol }}://{{ end }}{{ .Website.RedirectHostName }}"
+        {{- else }}
+        index_document = "{{ .Website.IndexDocument }}"
+        {{- if .Website.ErrorDocument }}
+        error_document = "{{ .Website.ErrorDocument }}"
+        {{- end }}
+        {{- if .Website.RoutingRules }}
+        routing_rules = {{ hclString .Website.RoutingRules }}
+        {{- end }}
+        {{- end }}
+      }
+      {{- end}}
+
       {{- if .Policy}}
+      {{ jsonWarning .Policy }}
       policy = <<POLICY
-      {{ prettyJSON .Policy }}
+      {{ jsonDocument .Policy }}
 POLICY
       {{- end}}
 
-      {{- if .Versioning}}
+      {{- if and (not $split) .Versioning}}
       versioning {
         {{- if .Versioning.Enabled}}
         enabled = {{.Versioning.Enabled}}
@@ -308,15 +477,20 @@ POLICY
       }
       {{- end}}
 
-      {{- if .ServerSideEncryptionConfiguration }}
+      {{- $kmsKey := .SSEKMSKeyExported }}
+      {{- $role := .ReplicationRoleExported }}
+      {{- $destinations := .ReplicationBucketsExported }}
+      {{- if and (not $split) .ServerSideEncryptionConfiguration }}
       server_side_encryption_configuration {
         {{- if .ServerSideEncryptionConfiguration.Rules}}
         {{- range .ServerSideEncryptionConfiguration.Rules}}
         rule {
           {{- if .ApplyServerSideEncryptionByDefault }}
           apply_server_side_encryption_by_default  {
-            {{- if .ApplyServerSideEncryptionByDefault.KMSMasterKeyID}}
-            kms_master_key_id = "{{.KMSMasterKeyID.KMSMasterKeyID }}"
+            {{- if $kmsKey }}
+            kms_master_key_id = "${aws_kms_key.{{ regionName $region $kmsKey }}.arn}"
+            {{- else if .ApplyServerSideEncryptionByDefault.KMSMasterKeyID}}
+            kms_master_key_id = "{{ .ApplyServerSideEncryptionByDefault.KMSMasterKeyID }}"
             {{- end}}
             {{- if .ApplyServerSideEncryptionByDefault.SSEAlgorithm}}
             sse_algorithm = "{{ .ApplyServerSideEncryptionByDefault.SSEAlgorithm }}"
@@ -329,101 +503,114 @@ POLICY
       }
       {{- end}}
 
-      {{- if .ReplicationConfiguration}}
+      {{- if and (not $split) .LifecycleRules }}
+      {{- range .LifecycleRules }}
+      lifecycle_rule {
+        id = "{{ .ID }}"
+        prefix = "{{ .Prefix }}"
+        enabled = {{ BoolValue .Enable }}
+
+        {{- range .Transition }}
+        transition {
+          {{- if .Days }}
+          days = {{ .Days }}
+          {{- end }}
+          {{- if .Date }}
+          date = "{{ .Date.Format "2006-01-02" }}"
+          {{- end }}
+          storage_class = "{{ .StorageClass }}"
+        }
+        {{- end }}
+
+        {{- range .NoncurrentVersionTransitions }}
+        noncurrent_version_transition {
+          days = {{ .NoncurrentDays }}
+          storage_class = "{{ .StorageClass }}"
+        }
+        {{- end }}
+
+        {{- if .NoncurrentVersionExpiration }}
+        noncurrent_version_expiration {
+          days = {{ .NoncurrentVersionExpiration.NoncurrentDays }}
+        }
+        {{- end }}
+      }
+      {{- end }}
+      {{- end }}
+
+      {{- if and (not $split) .ReplicationConfiguration }}
       replication_configuration {
-        {{- if .ReplicationConfiguration.Role}}
+        {{- if $role }}
+        role = "${aws_iam_role.{{ $role | makeTerraformResourceName | globalName }}.arn}"
+        {{- else }}
         role = "{{ .ReplicationConfiguration.Role }}"
-        {{- end}}
-        {{- if .ReplicationConfiguration.Rules}}
+        {{- end }}
+        {{- range .ReplicationConfiguration.Rules }}
+
         rules {
-          {{- range .ReplicationConfiguration.Rules}}
-          {{- if .ID}}
+          {{- if .ID }}
           id = "{{ .ID }}"
-          {{- end}}
-
-          {{- if .SourceSelectionCriteria}}
-          source_selection_criteria {
-            {{- if .SourceSelectionCriteria.SseKmsEncryptedObjects }}
-            sse_kms_encrypted_objects {
-              enabled = {{ .SourceSelectionCriteria.SseKmsEncryptedObjects.Status }}
-            }
-            {{- end }}
-          }
-          {{- end}}
-          {{- end}}
-          {{- if .LifecycleRules}}
-          {{- range .LifecycleRules}}
-          lifecycle_rule {
-            id = "{{ .ID}}"
+          {{- end }}
+          {{- if .Priority }}
+          priority = {{ .Priority }}
+          {{- end }}
+          status = "{{ .Status }}"
+          {{- if .Prefix }}
+          prefix = "{{ .Prefix }}"
+          {{- end }}
+          {{- with .Filter }}
+          filter {
+            {{- if .Prefix }}
             prefix = "{{ .Prefix }}"
-            enabled = {{ .Enable }}
-            {{- if .NoncurrentVersionTransitions }}
-            {{- range .}}
-            noncurrent_version_transition  {
-              {{- if .StorageClass}}
-              storage_class = "{{ .StorageClass }}"
-              {{- end}}
-              {{- if .NoncurrentDays }}
-              days = {{ .NoncurrentDays }}
-              {{- end}}
+            {{- else if and .And .And.Prefix }}
+            prefix = "{{ .And.Prefix }}"
+            {{- end }}
+            {{- $filterTags := replicationFilterTags . }}
+            {{- if $filterTags }}
+            tags {
+              {{- range $k, $v := $filterTags }}
+              {{ hclString $k }} = {{ hclString $v }}
+              {{- end }}
             }
-            {{- end}}
             {{- end }}
-
-            {{- if .NoncurrentVersionExpiration }}
-            {{- range .NoncurrentVersionExpiration}}
-            noncurrent_version_expiration {
-              days = {{ .NoncurrentDays }}
+          }
+          {{- end }}
+          {{- with .DeleteMarkerReplication }}
+          delete_marker_replication_status = "{{ .Status }}"
+          {{- end }}
+          {{- if and .SourceSelectionCriteria .SourceSelectionCriteria.SseKmsEncryptedObjects }}
+          source_selection_criteria {
+            sse_kms_encrypted_objects {
+              enabled = {{ eq (StringValue .SourceSelectionCriteria.SseKmsEncryptedObjects.Status) "Enabled" }}
             }
-            {{- end}}
-            {{- end}}
+          }
+          {{- end }}
 
-            {{- if .Transition }}
-            {{- range .Transition}}
-            transition {
-              {{- if .StorageClass}}
-              storage_class = "{{ .StorageClass}}"
-              {{- end}}
-              {{- if .Days}}
-              days = {{ .Days}}
-              {{- end}}
-              {{- if .Date}}
-              date = "{{ .Date }}"
-              {{- end}}
+          destination {
+            {{- with index $destinations (StringValue .Destination.Bucket) }}
+            bucket = "${aws_s3_bucket.{{ replace . "." "_" -1 | sanitizeName }}.arn}"
+            {{- else }}
+            bucket = "{{ .Destination.Bucket }}"
+            {{- end }}
+            {{- if .Destination.StorageClass }}
+            storage_class = "{{ .Destination.StorageClass }}"
+            {{- end }}
+            {{- if .Destination.EncryptionConfiguration }}
+            replica_kms_key_id = "{{ .Destination.EncryptionConfiguration.ReplicaKmsKeyID }}"
+            {{- end }}
+            {{- if .Destination.Account }}
+            account_id = "{{ .Destination.Account }}"
+            {{- end }}
+            {{- if .Destination.AccessControlTranslation }}
+            access_control_translation {
+              owner = "{{ .Destination.AccessControlTranslation.Owner }}"
             }
-            {{- end}}
             {{- end }}
           }
-          {{- end}}
-          {{- end }}
-
-          {{- if .Prefix }}
-          prefix = "{{ .Prefix}}"
-          {{- end}}
-          {{- if .Status}}
-          status = "{{ .Status }}"
-          {{- end}}
-          {{- if .Destination }}
-          {{- if .Destination.Bucket }}
-          bucket = "{{ .Destination.Bucket }}"
-          {{- end}}
-          {{- if .Destination.StorageClass}}
-          storage_class = "{{ .Destination.StorageClass}}"
-          {{- end}}
-          {{- if .Destination.EncryptionConfiguration}}
-          replica_kms_key_id  = {{ .Destination.EncryptionConfiguration.ReplicaKmsKeyID}}
-          {{- end }}
-          {{- if .Destination.AccessControlTranslation}}
-          access_control_translation = "{{ .Destination.AccessControlTranslation.Owner}}"
-          {{- end}}
-          {{- if .Destination.Account}}
-          account_id = "{{ .Destination.Account }}"
-          {{- end}}
-          {{- end}}
         }
-        {{- end}}
+        {{- end }}
       }
-      {{- end}}
+      {{- end }}
 
       {{- if .CORSRules}}
        {{- range .CORSRules}}
@@ -451,9 +638,277 @@ POLICY
        {{- end}}
       {{- end}}
     }
+
+    {{- if $split }}
+    {{- if .Logging }}
+
+    resource "aws_s3_bucket_logging" "{{ $name }}" {
+      {{- with $provider }}
+      provider = "{{ . }}"
+      {{- end }}
+      bucket = "${aws_s3_bucket.{{ $name }}.id}"
+      {{- if .Logging.TargetBucket }}
+      target_bucket = "{{ .Logging.TargetBucket }}"
+      {{- end }}
+      {{- if .Logging.TargetPrefix }}
+      target_prefix = "{{ .Logging.TargetPrefix }}"
+      {{- end }}
+    }
+    {{- end }}
+
+    {{- with .Website }}
+
+    resource "aws_s3_bucket_website_configuration" "{{ $name }}" {
+      {{- with $provider }}
+      provider = "{{ . }}"
+      {{- end }}
+      bucket = "${aws_s3_bucket.{{ $name }}.id}"
+      {{- if .RedirectHostName }}
+      redirect_all_requests_to {
+        host_name = "{{ .RedirectHostName }}"
+        {{- if .RedirectProtocol }}
+        protocol = "{{ .RedirectProtocol }}"
+        {{- end }}
+      }
+      {{- else }}
+      index_document {
+        suffix = "{{ .IndexDocument }}"
+      }
+      {{- if .ErrorDocument }}
+      error_document {
+        key = "{{ .ErrorDocument }}"
+      }
+      {{- end }}
+      {{- if .RoutingRules }}
+      routing_rules = {{ hclString .RoutingRules }}
+      {{- end }}
+      {{- end }}
+    }
+    {{- end }}
+
+    {{- if and .Versioning (or (BoolValue .Versioning.Enabled) (BoolValue .Versioning.Suspended)) }}
+
+    resource "aws_s3_bucket_versioning" "{{ $name }}" {
+      {{- with $provider }}
+      provider = "{{ . }}"
+      {{- end }}
+      bucket = "${aws_s3_bucket.{{ $name }}.id}"
+      versioning_configuration {
+        {{- if BoolValue .Versioning.Enabled }}
+        status = "Enabled"
+        {{- else }}
+        status = "Suspended"
+        {{- end }}
+        {{- if BoolValue .Versioning.MFADelete }}
+        mfa_delete = "Enabled"
+        {{- end }}
+      }
+    }
+    {{- end }}
+
+    {{- if and .ServerSideEncryptionConfiguration .ServerSideEncryptionConfiguration.Rules }}
+
+    resource "aws_s3_bucket_server_side_encryption_configuration" "{{ $name }}" {
+      {{- with $provider }}
+      provider = "{{ . }}"
+      {{- end }}
+      bucket = "${aws_s3_bucket.{{ $name }}.id}"
+      {{- range .ServerSideEncryptionConfiguration.Rules }}
+      rule {
+        {{- if .ApplyServerSideEncryptionByDefault }}
+        apply_server_side_encryption_by_default {
+          {{- if $kmsKey }}
+          kms_master_key_id = "${aws_kms_key.{{ regionName $region $kmsKey }}.arn}"
+          {{- else if .ApplyServerSideEncryptionByDefault.KMSMasterKeyID }}
+          kms_master_key_id = "{{ .ApplyServerSideEncryptionByDefault.KMSMasterKeyID }}"
+          {{- end }}
+          sse_algorithm = "{{ .ApplyServerSideEncryptionByDefault.SSEAlgorithm }}"
+        }
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+
+    {{- if .ReplicationConfiguration }}
+
+    resource "aws_s3_bucket_replication_configuration" "{{ $name }}" {
+      {{- with $provider }}
+      provider = "{{ . }}"
+      {{- end }}
+      bucket = "${aws_s3_bucket.{{ $name }}.id}"
+      {{- if $role }}
+      role = "${aws_iam_role.{{ $role | makeTerraformResourceName | globalName }}.arn}"
+      {{- else }}
+      role = "{{ .ReplicationConfiguration.Role }}"
+      {{- end }}
+      {{- range .ReplicationConfiguration.Rules }}
+
+      rule {
+        {{- if .ID }}
+        id = "{{ .ID }}"
+        {{- end }}
+        {{- if .Priority }}
+        priority = {{ .Priority }}
+        {{- end }}
+        status = "{{ .Status }}"
+        {{- if .Prefix }}
+        prefix = "{{ .Prefix }}"
+        {{- end }}
+        {{- with .Filter }}
+        filter {
+          {{- if .And }}
+          and {
+            {{- if .And.Prefix }}
+            prefix = "{{ .And.Prefix }}"
+            {{- end }}
+            {{- $filterTags := replicationFilterTags . }}
+            {{- if $filterTags }}
+            tags {
+              {{- range $k, $v := $filterTags }}
+              {{ hclString $k }} = {{ hclString $v }}
+              {{- end }}
+            }
+            {{- end }}
+          }
+          {{- else if .Tag }}
+          tag {
+            key = {{ hclString .Tag.Key }}
+            value = {{ hclString .Tag.Value }}
+          }
+          {{- else }}
+          prefix = "{{ .Prefix }}"
+          {{- end }}
+        }
+        {{- end }}
+        {{- with .DeleteMarkerReplication }}
+        delete_marker_replication {
+          status = "{{ .Status }}"
+        }
+        {{- end }}
+        {{- if and .SourceSelectionCriteria .SourceSelectionCriteria.SseKmsEncryptedObjects }}
+        source_selection_criteria {
+          sse_kms_encrypted_objects {
+            status = "{{ .SourceSelectionCriteria.SseKmsEncryptedObjects.Status }}"
+          }
+        }
+        {{- end }}
+
+        destination {
+          {{- with index $destinations (StringValue .Destination.Bucket) }}
+          bucket = "${aws_s3_bucket.{{ replace . "." "_" -1 | sanitizeName }}.arn}"
+          {{- else }}
+          bucket = "{{ .Destination.Bucket }}"
+          {{- end }}
+          {{- if .Destination.StorageClass }}
+          storage_class = "{{ .Destination.StorageClass }}"
+          {{- end }}
+          {{- if .Destination.Account }}
+          account = "{{ .Destination.Account }}"
+          {{- end }}
+          {{- if .Destination.EncryptionConfiguration }}
+          encryption_configuration {
+            replica_kms_key_id = "{{ .Destination.EncryptionConfiguration.ReplicaKmsKeyID }}"
+          }
+          {{- end }}
+          {{- if .Destination.AccessControlTranslation }}
+          access_control_translation {
+            owner = "{{ .Destination.AccessControlTranslation.Owner }}"
+          }
+          {{- end }}
+        }
+      }
+      {{- end }}
+    }
+    {{- end }}
+
+    {{- if .LifecycleRules }}
+
+    resource "aws_s3_bucket_lifecycle_configuration" "{{ $name }}" {
+      {{- with $provider }}
+      provider = "{{ . }}"
+      {{- end }}
+      bucket = "${aws_s3_bucket.{{ $name }}.id}"
+      {{- range .LifecycleRules }}
+      rule {
+        id = "{{ .ID }}"
+        {{- if .Enable }}
+        status = "Enabled"
+        {{- else }}
+        status = "Disabled"
+        {{- end }}
+
+        filter {
+          prefix = "{{ .Prefix }}"
+        }
+
+        {{- range .Transition }}
+        transition {
+          {{- if .Days }}
+          days = {{ .Days }}
+          {{- end }}
+          {{- if .Date }}
+          date = "{{ .Date.Format "2006-01-02T15:04:05Z07:00" }}"
+          {{- end }}
+          storage_class = "{{ .StorageClass }}"
+        }
+        {{- end }}
+
+        {{- range .NoncurrentVersionTransitions }}
+        noncurrent_version_transition {
+          noncurrent_days = {{ .NoncurrentDays }}
+          storage_class = "{{ .StorageClass }}"
+        }
+        {{- end }}
+
+        {{- if .NoncurrentVersionExpiration }}
+        noncurrent_version_expiration {
+          noncurrent_days = {{ .NoncurrentVersionExpiration.NoncurrentDays }}
+        }
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+    {{- end }}
     {{- end }}
   {{- end }}
   `
 
 	return renderHCL(w, tmpl, funcMap, b)
 }
+
+// WriteImports writes the terraform import commands of 'Buckets'
+func (b *Buckets) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"replace":    strings.Replace,
+		"providerV4": func() bool { return RenderOpts.ProviderV4 },
+		"BoolValue":  aws.BoolValue,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+{{ $name := replace .Name "." "_" -1 | sanitizeName -}}
+terraform import aws_s3_bucket.{{ $name }} {{ .Name }}
+{{ if providerV4 -}}
+{{ if .Logging -}}
+terraform import aws_s3_bucket_logging.{{ $name }} {{ .Name }}
+{{ end -}}
+{{ if .Website -}}
+terraform import aws_s3_bucket_website_configuration.{{ $name }} {{ .Name }}
+{{ end -}}
+{{ if and .Versioning (or (BoolValue .Versioning.Enabled) (BoolValue .Versioning.Suspended)) -}}
+terraform import aws_s3_bucket_versioning.{{ $name }} {{ .Name }}
+{{ end -}}
+{{ if and .ServerSideEncryptionConfiguration .ServerSideEncryptionConfiguration.Rules -}}
+terraform import aws_s3_bucket_server_side_encryption_configuration.{{ $name }} {{ .Name }}
+{{ end -}}
+{{ if .LifecycleRules -}}
+terraform import aws_s3_bucket_lifecycle_configuration.{{ $name }} {{ .Name }}
+{{ end -}}
+{{ if .ReplicationConfiguration -}}
+terraform import aws_s3_bucket_replication_configuration.{{ $name }} {{ .Name }}
+{{ end -}}
+{{ end -}}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, b)
+}
@@ -0,0 +1,192 @@
+package tfit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestBucketsWriteHCLInjectedTags(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{
+		ExtraTags: map[string]string{"ManagedBy": "terraform", "Team": "platform"},
+	}
+
+	buckets := &Buckets{{
+		Name: aws.String("logs"),
+		Tags: &Tags{"Team": aws.String("data"), "Env": aws.String("prod")},
+	}}
+
+	var buf bytes.Buffer
+	if err := buckets.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	out := strings.Join(strings.Fields(buf.String()), " ")
+
+	// The bucket's own tags win over the injected ones
+	want := `tags { "Env" = "prod" "ManagedBy" = "terraform" "Team" = "data" }`
+	if !strings.Contains(out, want) {
+		t.Errorf("want %s in:\n%s", want, buf.String())
+	}
+}
+
+func TestBucketsWriteHCLProviderV4(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+
+	lifecycle := []*S3LifecycleRule{{
+		ID:     aws.String("archive"),
+		Enable: aws.Bool(true),
+		Prefix: aws.String("logs/"),
+		Transition: []*s3.Transition{{
+			Days:         aws.Int64(30),
+			StorageClass: aws.String("GLACIER"),
+		}},
+	}}
+
+	tests := []struct {
+		name   string
+		bucket *Bucket
+		want   []string
+		unwant []string
+	}{
+		{
+			name: "enabled versioning",
+			bucket: &Bucket{
+				Name:           aws.String("logs"),
+				Versioning:     &BucketVersioning{Enabled: aws.Bool(true), MFADelete: aws.Bool(false)},
+				LifecycleRules: lifecycle,
+			},
+			want: []string{
+				`resource "aws_s3_bucket_versioning" "logs" { bucket = "${aws_s3_bucket.logs.id}" versioning_configuration { status = "Enabled" } }`,
+				`resource "aws_s3_bucket_lifecycle_configuration" "logs" { bucket = "${aws_s3_bucket.logs.id}" rule { id = "archive" status = "Enabled" filter { prefix = "logs/" } transition { days = 30 storage_class = "GLACIER" } } }`,
+				"terraform import aws_s3_bucket_versioning.logs logs",
+				"terraform import aws_s3_bucket_lifecycle_configuration.logs logs",
+			},
+			unwant: []string{"versioning {", "lifecycle_rule {"},
+		},
+		{
+			name: "suspended versioning",
+			bucket: &Bucket{
+				Name:       aws.String("logs"),
+				Versioning: &BucketVersioning{Suspended: aws.Bool(true), MFADelete: aws.Bool(false)},
+			},
+			want: []string{
+				`resource "aws_s3_bucket_versioning" "logs" { bucket = "${aws_s3_bucket.logs.id}" versioning_configuration { status = "Suspended" } }`,
+				"terraform import aws_s3_bucket_versioning.logs logs",
+			},
+			unwant: []string{"aws_s3_bucket_lifecycle_configuration"},
+		},
+		{
+			name: "never versioned",
+			bucket: &Bucket{
+				Name:       aws.String("logs"),
+				Versioning: &BucketVersioning{MFADelete: aws.Bool(false)},
+			},
+			unwant: []string{"aws_s3_bucket_versioning"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*RenderOpts = RenderOptions{ProviderV4: true}
+
+			var buf bytes.Buffer
+			if err := (&Buckets{tt.bucket}).WriteHCL(&buf); err != nil {
+				t.Fatalf("WriteHCL: %v", err)
+			}
+			if err := (&Buckets{tt.bucket}).WriteImports(&buf); err != nil {
+				t.Fatalf("WriteImports: %v", err)
+			}
+			out := strings.Join(strings.Fields(buf.String()), " ")
+
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("want %s in:\n%s", want, buf.String())
+				}
+			}
+			for _, unwant := range tt.unwant {
+				if strings.Contains(out, unwant) {
+					t.Errorf("unexpected %s in:\n%s", unwant, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestBucketsWriteHCLRegions(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+
+	bucket := &Bucket{
+		Name:                              aws.String("eu"),
+		Region:                            aws.String("eu-west-1"),
+		Versioning:                        &BucketVersioning{Enabled: aws.Bool(true)},
+		ServerSideEncryptionConfiguration: sseKMS("key-eu"),
+		SSEKMSKeyExported:                 aws.String("key-eu"),
+	}
+
+	tests := []struct {
+		name   string
+		opts   RenderOptions
+		want   []string
+		unwant []string
+	}{
+		{
+			name: "regions exported apart",
+			opts: RenderOptions{
+				ProviderV4:        true,
+				Region:            "us-east-1",
+				LabelPrefix:       "prod_",
+				Provider:          "aws.prod",
+				Regions:           map[string]string{"eu-west-1": "prod_eu-west-1"},
+				GlobalLabelPrefix: "prod_",
+			},
+			want: []string{
+				`resource "aws_s3_bucket" "prod_eu" { provider = "aws.prod_eu-west-1"`,
+				`resource "aws_s3_bucket_versioning" "prod_eu" { provider = "aws.prod_eu-west-1"`,
+				`kms_master_key_id = "${aws_kms_key.prod_eu-west-1_key-eu.arn}"`,
+			},
+			unwant: []string{`"aws.prod"`, "WARNING"},
+		},
+		{
+			name: "region of the provider",
+			opts: RenderOptions{Region: "eu-west-1"},
+			want: []string{
+				`resource "aws_s3_bucket" "eu" { bucket = "eu"`,
+				`kms_master_key_id = "${aws_kms_key.key-eu.arn}"`,
+			},
+			unwant: []string{"provider", "WARNING"},
+		},
+		{
+			name:   "other region",
+			opts:   RenderOptions{Region: "us-east-1"},
+			want:   []string{"# WARNING: the bucket is in eu-west-1, bind it to a provider of that region"},
+			unwant: []string{"provider ="},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*RenderOpts = tt.opts
+
+			var buf bytes.Buffer
+			if err := (&Buckets{bucket}).WriteHCL(&buf); err != nil {
+				t.Fatalf("WriteHCL: %v", err)
+			}
+			out := strings.Join(strings.Fields(buf.String()), " ")
+
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("want %s in:\n%s", want, buf.String())
+				}
+			}
+			for _, unwant := range tt.unwant {
+				if strings.Contains(out, unwant) {
+					t.Errorf("unexpected %s in:\n%s", unwant, buf.String())
+				}
+			}
+		})
+	}
+}
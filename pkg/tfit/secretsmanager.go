@@ -0,0 +1,148 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+//**************** Secrets Manager Secret ****************
+type Secret struct {
+	Name              *string
+	ARN               *string
+	Description       *string
+	KmsKeyID          *string
+	RotationEnabled   bool
+	RotationLambdaARN *string
+	RotationDays      *int64
+	Tags              *Tags
+}
+
+type Secrets []*Secret
+
+// GetSecretsWithContext returns the metadata of the secrets, their
+// values are never fetched. Secrets scheduled for deletion are skipped
+func (c *AWSClient) GetSecretsWithContext(ctx aws.Context) (*Secrets, error) {
+	var res Secrets
+
+	opt := &secretsmanager.ListSecretsInput{}
+	for {
+		out, err := c.secretsconn.ListSecretsWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.SecretList {
+			if v == nil || v.DeletedDate != nil || !c.wantID(v.Name, v.ARN) {
+				continue
+			}
+
+			tmp := &Secret{
+				Name:              v.Name,
+				ARN:               v.ARN,
+				Description:       v.Description,
+				KmsKeyID:          v.KmsKeyId,
+				RotationEnabled:   aws.BoolValue(v.RotationEnabled),
+				RotationLambdaARN: v.RotationLambdaARN,
+				Tags:              &Tags{},
+			}
+			if v.RotationRules != nil {
+				tmp.RotationDays = v.RotationRules.AutomaticallyAfterDays
+			}
+			for _, t := range v.Tags {
+				if t != nil && t.Key != nil {
+					(*tmp.Tags)[*t.Key] = t.Value
+				}
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_secretsmanager_secret", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetSecrets calls GetSecretsWithContext with a background context
+func (c *AWSClient) GetSecrets() (*Secrets, error) {
+	return c.GetSecretsWithContext(aws.BackgroundContext())
+}
+
+func (s *Secrets) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"placeholders": func() bool { return RenderOpts.SecretPlaceholders },
+	}
+
+	// The placeholder versions have to be filled in before applying, the
+	// real secret values are never exported
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{- $name := sanitizeName .Name }}
+    {{ resourceID "aws_secretsmanager_secret" .ARN }}
+    resource "aws_secretsmanager_secret" "{{ $name }}" {
+      name = "{{ .Name }}"
+      {{- if .Description }}
+      description = {{ hclString .Description }}
+      {{- end }}
+      {{- if .KmsKeyID }}
+      kms_key_id = "{{ .KmsKeyID }}"
+      {{- end }}
+      {{- if .RotationEnabled }}
+      {{- if .RotationLambdaARN }}
+      rotation_lambda_arn = "{{ .RotationLambdaARN }}"
+      {{- end }}
+      {{- if .RotationDays }}
+      rotation_rules {
+        automatically_after_days = {{ .RotationDays }}
+      }
+      {{- end }}
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- if placeholders }}
+
+    resource "aws_secretsmanager_secret_version" "{{ $name }}" {
+      secret_id = "${aws_secretsmanager_secret.{{ $name }}.id}"
+      # WARNING: placeholder value, set the secret before applying
+      secret_string = "REDACTED"
+
+      lifecycle {
+        ignore_changes = ["secret_string"]
+      }
+    }
+    {{- end }}
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, s)
+}
+
+// WriteImports writes the terraform import commands of 'Secrets'.
+// The placeholder versions are new resources, they have nothing to import
+func (s *Secrets) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_secretsmanager_secret.{{ sanitizeName .Name }} {{ .ARN }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, s)
+}
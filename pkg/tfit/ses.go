@@ -0,0 +1,221 @@
+package tfit
+
+import (
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ses"
+)
+
+//**************** SES Identity ****************
+type SESIdentity struct {
+	Identity *string
+	// "Domain" or "EmailAddress"
+	Type                   string
+	VerificationStatus     *string
+	DKIMEnabled            *bool
+	DKIMVerificationStatus *string
+	NotificationTopics     []*SESNotificationTopic
+}
+
+// SESNotificationTopic is the SNS topic notified of the bounces,
+// complaints or deliveries of an identity
+type SESNotificationTopic struct {
+	// "Bounce", "Complaint" or "Delivery"
+	Type                   string
+	TopicARN               *string
+	IncludeOriginalHeaders *bool
+}
+
+type SESIdentities []*SESIdentity
+
+// sesResourceType returns the Terraform resource type of an identity type
+func sesResourceType(identityType string) string {
+	if identityType == ses.IdentityTypeDomain {
+		return "aws_ses_domain_identity"
+	}
+	return "aws_ses_email_identity"
+}
+
+func (i *SESIdentity) setNotifications(src *ses.IdentityNotificationAttributes) {
+	topics := []*SESNotificationTopic{
+		{ses.NotificationTypeBounce, src.BounceTopic, src.HeadersInBounceNotificationsEnabled},
+		{ses.NotificationTypeComplaint, src.ComplaintTopic, src.HeadersInComplaintNotificationsEnabled},
+		{ses.NotificationTypeDelivery, src.DeliveryTopic, src.HeadersInDeliveryNotificationsEnabled},
+	}
+
+	for _, v := range topics {
+		if len(aws.StringValue(v.TopicARN)) > 0 {
+			i.NotificationTopics = append(i.NotificationTopics, v)
+		}
+	}
+}
+
+// getSESAttributes gets the verification, DKIM & notification attributes of
+// identities, at most 100 of them
+func (c *AWSClient) getSESAttributes(ctx aws.Context, identities []*SESIdentity) error {
+	names := make([]*string, len(identities))
+	for k, v := range identities {
+		names[k] = v.Identity
+	}
+
+	verification, err := c.sesconn.GetIdentityVerificationAttributesWithContext(ctx, &ses.GetIdentityVerificationAttributesInput{Identities: names})
+	if err != nil {
+		return err
+	}
+	dkim, err := c.sesconn.GetIdentityDkimAttributesWithContext(ctx, &ses.GetIdentityDkimAttributesInput{Identities: names})
+	if err != nil {
+		return err
+	}
+	notification, err := c.sesconn.GetIdentityNotificationAttributesWithContext(ctx, &ses.GetIdentityNotificationAttributesInput{Identities: names})
+	if err != nil {
+		return err
+	}
+
+	for _, v := range identities {
+		name := aws.StringValue(v.Identity)
+		if attr := verification.VerificationAttributes[name]; attr != nil {
+			v.VerificationStatus = attr.VerificationStatus
+		}
+		if attr := dkim.DkimAttributes[name]; attr != nil {
+			v.DKIMEnabled = attr.DkimEnabled
+			v.DKIMVerificationStatus = attr.DkimVerificationStatus
+		}
+		if attr := notification.NotificationAttributes[name]; attr != nil {
+			v.setNotifications(attr)
+		}
+	}
+
+	return nil
+}
+
+// GetSESIdentitiesWithContext returns the domains & email addresses of SES,
+// verified or not
+func (c *AWSClient) GetSESIdentitiesWithContext(ctx aws.Context) (*SESIdentities, error) {
+	// SES identities carry no tags, TagFilter excludes all of them
+	if c.untaggable() {
+		return &SESIdentities{}, nil
+	}
+
+	var res SESIdentities
+
+	// The attributes are fetched by up to 100 identities
+	opt := &ses.ListIdentitiesInput{MaxItems: aws.Int64(100)}
+	for {
+		out, err := c.sesconn.ListIdentitiesWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []*SESIdentity
+		for _, v := range out.Identities {
+			if v == nil || !c.wantID(v) {
+				continue
+			}
+
+			tmp := &SESIdentity{Identity: v, Type: ses.IdentityTypeDomain}
+			if strings.Contains(*v, "@") {
+				tmp.Type = ses.IdentityTypeEmailAddress
+			}
+			page = append(page, tmp)
+		}
+
+		if len(page) > 0 {
+			if err := c.getSESAttributes(ctx, page); err != nil {
+				return nil, err
+			}
+			res = append(res, page...)
+		}
+
+		c.progress("aws_ses_identity", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetSESIdentities calls GetSESIdentitiesWithContext with a background context
+func (c *AWSClient) GetSESIdentities() (*SESIdentities, error) {
+	return c.GetSESIdentitiesWithContext(aws.BackgroundContext())
+}
+
+func (i *SESIdentities) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"BoolValue":                 aws.BoolValue,
+		"StringValue":               aws.StringValue,
+		"makeTerraformResourceName": makeTerraformResourceName,
+		"sesResourceType":           sesResourceType,
+	}
+
+	// Identities are verified out of band, by a TXT record for domains and
+	// by the link of an email for addresses
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{- $name := makeTerraformResourceName .Identity }}
+    {{- $label := sanitizeName $name }}
+    {{- $type := sesResourceType .Type }}
+    {{ resourceID $type .Identity }}
+    {{- if ne (StringValue .VerificationStatus) "Success" }}
+    {{- if eq .Type "Domain" }}
+    # WARNING: {{ .Identity }} is not verified ({{ .VerificationStatus }}), publish its verification TXT record
+    {{- else }}
+    # WARNING: {{ .Identity }} is not verified ({{ .VerificationStatus }}), follow the link of the verification email
+    {{- end }}
+    {{- end }}
+    resource "{{ $type }}" "{{ $label }}" {
+      {{- if eq .Type "Domain" }}
+      domain = "{{ .Identity }}"
+      {{- else }}
+      email = "{{ .Identity }}"
+      {{- end }}
+    }
+    {{- if and (eq .Type "Domain") (BoolValue .DKIMEnabled) }}
+
+    resource "aws_ses_domain_dkim" "{{ $label }}" {
+      domain = "${aws_ses_domain_identity.{{ $label }}.domain}"
+    }
+    {{- end }}
+    {{- range .NotificationTopics }}
+
+    resource "aws_ses_identity_notification_topic" "{{ printf "%s_%s" $name .Type | sanitizeName }}" {
+      identity = "{{ printf "${%s.%s.arn}" $type $label }}"
+      notification_type = "{{ .Type }}"
+      topic_arn = "{{ .TopicARN }}"
+      include_original_headers = {{ BoolValue .IncludeOriginalHeaders }}
+    }
+    {{- end }}
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, i)
+}
+
+// WriteImports writes the terraform import commands of 'SESIdentities', of
+// their DKIM & of their notification topics
+func (i *SESIdentities) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"BoolValue":                 aws.BoolValue,
+		"makeTerraformResourceName": makeTerraformResourceName,
+		"sesResourceType":           sesResourceType,
+	}
+
+	tmpl := `{{ if . }}{{ range . -}}
+{{ $name := makeTerraformResourceName .Identity -}}
+{{ $id := .Identity -}}
+terraform import {{ sesResourceType .Type }}.{{ sanitizeName $name }} {{ .Identity }}
+{{ if and (eq .Type "Domain") (BoolValue .DKIMEnabled) -}}
+terraform import aws_ses_domain_dkim.{{ sanitizeName $name }} {{ .Identity }}
+{{ end -}}
+{{ range .NotificationTopics -}}
+terraform import aws_ses_identity_notification_topic.{{ printf "%s_%s" $name .Type | sanitizeName }} '{{ $id }}|{{ .Type }}'
+{{ end -}}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, i)
+}
@@ -0,0 +1,138 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sfn"
+)
+
+//**************** Step Functions State Machine ****************
+type StateMachine struct {
+	Name       *string
+	ARN        *string
+	Definition *string
+	RoleARN    *string
+	Tags       *Tags
+
+	// Set by ResolveReferences when the role is exported as well
+	RoleExported *string
+}
+
+type StateMachines []*StateMachine
+
+func (s *StateMachine) getTags(ctx aws.Context, c *AWSClient) error {
+	output, err := c.sfnconn.ListTagsForResourceWithContext(ctx, &sfn.ListTagsForResourceInput{ResourceArn: s.ARN})
+	if err != nil {
+		return err
+	}
+
+	s.Tags = &Tags{}
+	for _, v := range output.Tags {
+		if v != nil && v.Key != nil {
+			(*s.Tags)[*v.Key] = v.Value
+		}
+	}
+
+	return nil
+}
+
+// GetStateMachinesWithContext returns the state machines which are not
+// being deleted, with their definition
+func (c *AWSClient) GetStateMachinesWithContext(ctx aws.Context) (*StateMachines, error) {
+	var res StateMachines
+
+	opt := &sfn.ListStateMachinesInput{}
+	for {
+		out, err := c.sfnconn.ListStateMachinesWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.StateMachines {
+			if v == nil || !c.wantID(v.Name, v.StateMachineArn) {
+				continue
+			}
+
+			tmp := &StateMachine{Name: v.Name, ARN: v.StateMachineArn}
+			if err := tmp.getTags(ctx, c); err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tmp.Tags) {
+				continue
+			}
+
+			desc, err := c.sfnconn.DescribeStateMachineWithContext(ctx, &sfn.DescribeStateMachineInput{StateMachineArn: v.StateMachineArn})
+			if err != nil {
+				return nil, err
+			}
+			if aws.StringValue(desc.Status) == sfn.StateMachineStatusDeleting {
+				continue
+			}
+			tmp.Definition = desc.Definition
+			tmp.RoleARN = desc.RoleArn
+
+			res = append(res, tmp)
+		}
+
+		c.progress("aws_sfn_state_machine", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+// GetStateMachines calls GetStateMachinesWithContext with a background context
+func (c *AWSClient) GetStateMachines() (*StateMachines, error) {
+	return c.GetStateMachinesWithContext(aws.BackgroundContext())
+}
+
+func (s *StateMachines) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformResourceName": makeTerraformResourceName,
+	}
+
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_sfn_state_machine" .ARN }}
+    resource "aws_sfn_state_machine" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      {{- with .RoleExported }}
+      role_arn = "${aws_iam_role.{{ . | makeTerraformResourceName | globalName }}.arn}"
+      {{- else }}
+      role_arn = "{{ .RoleARN }}"
+      {{- end }}
+      {{ jsonWarning .Definition }}
+      definition = <<EOF
+      {{ jsonDocument .Definition }}
+EOF
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, s)
+}
+
+// WriteImports writes the terraform import commands of 'StateMachines'
+func (s *StateMachines) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_sfn_state_machine.{{ sanitizeName .Name }} {{ .ARN }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, s)
+}
@@ -0,0 +1,313 @@
+package tfit
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// Snapshot holds every resource collected by the export command.
+// A resource type which failed to be collected is left nil
+type Snapshot struct {
+	Instances                    *Instances
+	VPCs                         *VPCs
+	Subnets                      *Subnets
+	SecurityGroups               *SecurityGroups
+	RouteTables                  *RouteTables
+	NetworkACLs                  *NetworkACLs
+	VPCPeerings                  *VPCPeerings
+	VPCEndpoints                 *VPCEndpoints
+	DHCPOptions                  *DHCPOptionsList
+	ENIs                         *ENIs
+	PlacementGroups              *PlacementGroups
+	SpotRequests                 *SpotRequests
+	TransitGateways              *TransitGateways
+	TransitGatewayVPCAttachments *TransitGatewayVPCAttachments
+	VPNGateways                  *VPNGateways
+	CustomerGateways             *CustomerGateways
+	VPNConnections               *VPNConnections
+	Zones                        *Zones
+	RecordSets                   *RecordSets
+	Policies                     *Policies
+	Roles                        *Roles
+	InstanceProfiles             *InstanceProfiles
+	Users                        *Users
+	IAMGroups                    *IAMGroups
+	Buckets                      *Buckets
+	AutoScalingGroups            *AutoScalingGroups
+	LaunchConfigurations         *LaunchConfigurations
+	ELBs                         *ELBs
+	KMSKeys                      *KMSKeys
+	LogGroups                    *LogGroups
+	MetricAlarms                 *MetricAlarms
+	ECRRepositories              *ECRRepositories
+	ElastiCacheClusters          *ElastiCacheClusters
+	ElastiCacheReplicationGroups *ElastiCacheReplicationGroups
+	SSMParameters                *SSMParameters
+	Secrets                      *Secrets
+	EFSFileSystems               *EFSFileSystems
+	ACMCertificates              *ACMCertificates
+	RestAPIs                     *RestAPIs
+	RedshiftClusters             *RedshiftClusters
+	ECSClusters                  *ECSClusters
+	ECSServices                  *ECSServices
+	Distributions                *Distributions
+	DynamoDBScalableTargets      *AppAutoScalingTargets
+	BeanstalkApps                *BeanstalkApps
+	BeanstalkEnvs                *BeanstalkEnvs
+	KinesisStreams               *KinesisStreams
+	Trails                       *Trails
+	SESIdentities                *SESIdentities
+	StateMachines                *StateMachines
+	GlacierVaults                *GlacierVaults
+	CodeBuildProjects            *CodeBuildProjects
+	DBSubnetGroups               *DBSubnetGroups
+	DBParameterGroups            *DBParameterGroups
+	DBClusters                   *DBClusters
+	Accounts                     *Accounts
+}
+
+// RunPostCollect calls the RenderOpts.PostCollect hook, if any
+func RunPostCollect(snapshot *Snapshot) error {
+	if RenderOpts.PostCollect == nil {
+		return nil
+	}
+	return RenderOpts.PostCollect(snapshot)
+}
+
+// WithGlobal returns a copy of s, the snapshot of region, holding the
+// resource types of global it lacks, so that ResolveReferences links its
+// resources to the global ones they depend on, and the other way round.
+// Only the buckets of region are kept, the references between global
+// resources are resolved by resolving global alone afterwards
+func (s *Snapshot) WithGlobal(global *Snapshot, region string) *Snapshot {
+	res := *s
+	dst, src := reflect.ValueOf(&res).Elem(), reflect.ValueOf(global).Elem()
+	for i := 0; i < dst.NumField(); i++ {
+		if dst.Field(i).IsNil() {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+
+	if s.Buckets == nil && global.Buckets != nil {
+		buckets := Buckets{}
+		for _, v := range *global.Buckets {
+			if aws.StringValue(v.Region) == region {
+				buckets = append(buckets, v)
+			}
+		}
+		res.Buckets = &buckets
+	}
+
+	return &res
+}
+
+// ResolveReferences makes the resources of the snapshot reference the
+// ones they depend on, when these are exported along with them. Others
+// keep referencing them by name or ID. The instances of the exported spot
+// requests are left out, the aws_spot_instance_request resources create
+// them
+func (s *Snapshot) ResolveReferences() {
+	if s.Instances != nil && s.SpotRequests != nil {
+		requests := make(map[string]bool)
+		for _, v := range *s.SpotRequests {
+			requests[aws.StringValue(v.ID)] = true
+		}
+		instances := Instances{}
+		for _, v := range *s.Instances {
+			if v.SpotInstanceRequestID == nil || !requests[*v.SpotInstanceRequestID] {
+				instances = append(instances, v)
+			}
+		}
+		*s.Instances = instances
+	}
+
+	if s.Instances != nil && s.InstanceProfiles != nil {
+		profiles := make(map[string]bool)
+		for _, v := range *s.InstanceProfiles {
+			profiles[aws.StringValue(v.Name)] = true
+		}
+		for _, v := range *s.Instances {
+			v.IamInstanceProfileExported = profiles[aws.StringValue(v.IamInstanceProfile)]
+		}
+	}
+
+	if s.Instances != nil && s.PlacementGroups != nil {
+		groups := make(map[string]bool)
+		for _, v := range *s.PlacementGroups {
+			groups[aws.StringValue(v.Name)] = true
+		}
+		for _, v := range *s.Instances {
+			v.PlacementGroupExported = groups[aws.StringValue(v.PlacementGroup)]
+		}
+	}
+
+	if s.InstanceProfiles != nil && s.Roles != nil {
+		roles := make(map[string]bool)
+		for _, v := range *s.Roles {
+			roles[aws.StringValue(v.Name)] = true
+		}
+		for _, v := range *s.InstanceProfiles {
+			v.RoleExported = roles[aws.StringValue(v.RoleName)]
+		}
+	}
+
+	if s.BeanstalkEnvs != nil && s.BeanstalkApps != nil {
+		apps := make(map[string]bool)
+		for _, v := range *s.BeanstalkApps {
+			apps[aws.StringValue(v.Name)] = true
+		}
+		for _, v := range *s.BeanstalkEnvs {
+			v.ApplicationExported = apps[aws.StringValue(v.ApplicationName)]
+		}
+	}
+
+	if s.StateMachines != nil || s.CodeBuildProjects != nil {
+		roles := make(map[string]*string)
+		if s.Roles != nil {
+			for _, v := range *s.Roles {
+				roles[aws.StringValue(v.Name)] = v.Name
+			}
+		}
+		// arn:aws:iam::<account>:role/<path><name>
+		roleName := func(arn *string) string {
+			tokens := strings.Split(aws.StringValue(arn), "/")
+			return tokens[len(tokens)-1]
+		}
+		if s.StateMachines != nil {
+			for _, v := range *s.StateMachines {
+				v.RoleExported = roles[roleName(v.RoleARN)]
+			}
+		}
+		if s.CodeBuildProjects != nil {
+			for _, v := range *s.CodeBuildProjects {
+				v.ServiceRoleExported = roles[roleName(v.ServiceRole)]
+			}
+		}
+	}
+
+	if s.DBClusters != nil {
+		subnetGroups := make(map[string]bool)
+		if s.DBSubnetGroups != nil {
+			for _, v := range *s.DBSubnetGroups {
+				subnetGroups[aws.StringValue(v.Name)] = true
+			}
+		}
+		parameterGroups := make(map[string]bool)
+		if s.DBParameterGroups != nil {
+			for _, v := range *s.DBParameterGroups {
+				parameterGroups[aws.StringValue(v.Name)] = true
+			}
+		}
+		for _, v := range *s.DBClusters {
+			v.SubnetGroupExported = subnetGroups[aws.StringValue(v.SubnetGroupName)]
+			for _, i := range v.Instances {
+				i.ParameterGroupExported = parameterGroups[aws.StringValue(i.ParameterGroupName)]
+			}
+		}
+	}
+
+	if s.ECSServices != nil && s.ECSClusters != nil {
+		clusters := make(map[string]bool)
+		for _, v := range *s.ECSClusters {
+			clusters[aws.StringValue(v.ARN)] = true
+		}
+		for _, v := range *s.ECSServices {
+			v.ClusterExported = clusters[aws.StringValue(v.ClusterARN)]
+		}
+	}
+
+	if s.Trails != nil {
+		buckets := make(map[string]bool)
+		if s.Buckets != nil {
+			for _, v := range *s.Buckets {
+				buckets[aws.StringValue(v.Name)] = true
+			}
+		}
+		keys := make(map[string]*string)
+		if s.KMSKeys != nil {
+			for _, v := range *s.KMSKeys {
+				keys[aws.StringValue(v.KeyID)] = v.KeyID
+			}
+		}
+		for _, v := range *s.Trails {
+			v.BucketExported = buckets[aws.StringValue(v.S3BucketName)]
+			v.KMSKeyExported = nil
+			if v.KMSKeyID != nil {
+				v.KMSKeyExported = keys[kmsKeyName(v.KMSKeyID)]
+			}
+		}
+	}
+
+	if s.Buckets != nil && s.KMSKeys != nil {
+		keys := make(map[string]*string)
+		for _, v := range *s.KMSKeys {
+			keys[aws.StringValue(v.KeyID)] = v.KeyID
+			for _, alias := range v.Aliases {
+				keys[aws.StringValue(alias)] = v.KeyID
+			}
+		}
+		for _, v := range *s.Buckets {
+			v.SSEKMSKeyExported = nil
+			if v.ServerSideEncryptionConfiguration == nil {
+				continue
+			}
+			for _, rule := range v.ServerSideEncryptionConfiguration.Rules {
+				if rule != nil && rule.ApplyServerSideEncryptionByDefault != nil && rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID != nil {
+					v.SSEKMSKeyExported = keys[kmsKeyName(rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID)]
+				}
+			}
+		}
+	}
+
+	if s.Buckets != nil {
+		buckets := make(map[string]*string)
+		for _, v := range *s.Buckets {
+			buckets["arn:aws:s3:::"+aws.StringValue(v.Name)] = v.Name
+		}
+		roles := make(map[string]*string)
+		if s.Roles != nil {
+			for _, v := range *s.Roles {
+				roles[aws.StringValue(v.Name)] = v.Name
+			}
+		}
+		for _, v := range *s.Buckets {
+			v.ReplicationRoleExported = nil
+			v.ReplicationBucketsExported = nil
+			if v.ReplicationConfiguration == nil {
+				continue
+			}
+			// arn:aws:iam::<account>:role/<path><name>
+			role := strings.Split(aws.StringValue(v.ReplicationConfiguration.Role), "/")
+			v.ReplicationRoleExported = roles[role[len(role)-1]]
+			v.ReplicationBucketsExported = buckets
+		}
+	}
+
+	if s.LogGroups != nil {
+		streams := make(map[string]*string)
+		if s.KinesisStreams != nil {
+			for _, v := range *s.KinesisStreams {
+				streams[aws.StringValue(v.ARN)] = v.Name
+			}
+		}
+		roles := make(map[string]*string)
+		if s.Roles != nil {
+			for _, v := range *s.Roles {
+				roles[aws.StringValue(v.Name)] = v.Name
+			}
+		}
+		for _, g := range *s.LogGroups {
+			for _, v := range g.SubscriptionFilters {
+				v.DestinationExported = streams[aws.StringValue(v.DestinationARN)]
+				v.RoleExported = nil
+				if v.RoleARN != nil {
+					// arn:aws:iam::<account>:role/<path><name>
+					role := strings.Split(aws.StringValue(v.RoleARN), "/")
+					v.RoleExported = roles[role[len(role)-1]]
+				}
+			}
+		}
+	}
+}
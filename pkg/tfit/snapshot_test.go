@@ -0,0 +1,207 @@
+package tfit
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestRunPostCollect(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{}
+
+	RenderOpts.PostCollect = func(s *Snapshot) error {
+		for _, v := range *s.Instances {
+			if v.Tags == nil {
+				v.Tags = &Tags{}
+			}
+			(*v.Tags)["CostCenter"] = aws.String("cc-42")
+		}
+		return nil
+	}
+
+	snapshot := &Snapshot{Instances: &Instances{
+		{InstanceID: aws.String("i-1"), ImageID: aws.String("ami-12345678"), InstanceType: aws.String("t2.micro")},
+		{InstanceID: aws.String("i-2"), ImageID: aws.String("ami-12345678"), InstanceType: aws.String("t2.micro"),
+			Tags: &Tags{"Name": aws.String("web")}},
+	}}
+	if err := RunPostCollect(snapshot); err != nil {
+		t.Fatalf("RunPostCollect: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := snapshot.Instances.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	out := strings.Join(strings.Fields(buf.String()), " ")
+	if n := strings.Count(out, `"CostCenter" = "cc-42"`); n != 2 {
+		t.Errorf("want the tag on both instances, found %d in:\n%s", n, buf.String())
+	}
+	if !strings.Contains(out, `"Name" = "web"`) {
+		t.Errorf("existing tag lost in:\n%s", buf.String())
+	}
+}
+
+func TestSnapshotWithGlobal(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{
+		Regions:           map[string]string{"us-east-1": "prod_us-east-1", "eu-west-1": "prod_eu-west-1"},
+		GlobalLabelPrefix: "prod_",
+	}
+
+	global := &Snapshot{
+		InstanceProfiles: &InstanceProfiles{{Name: aws.String("web")}},
+		Buckets: &Buckets{
+			{Name: aws.String("us"), Region: aws.String("us-east-1"), ServerSideEncryptionConfiguration: sseKMS("key-us")},
+			{Name: aws.String("eu"), Region: aws.String("eu-west-1"), ServerSideEncryptionConfiguration: sseKMS("key-eu")},
+		},
+	}
+	regions := map[string]*Snapshot{
+		"us-east-1": {
+			Instances: &Instances{{InstanceID: aws.String("i-1"), ImageID: aws.String("ami-12345678"), InstanceType: aws.String("t2.micro"),
+				IamInstanceProfile: aws.String("web")}},
+			KMSKeys: &KMSKeys{{KeyID: aws.String("key-us")}},
+		},
+		"eu-west-1": {KMSKeys: &KMSKeys{{KeyID: aws.String("key-eu")}}},
+	}
+	for region, s := range regions {
+		s.WithGlobal(global, region).ResolveReferences()
+	}
+	global.ResolveReferences()
+
+	if v := (*regions["us-east-1"].Instances)[0]; !v.IamInstanceProfileExported {
+		t.Errorf("the instance does not reference the global instance profile")
+	}
+	for _, v := range *global.Buckets {
+		if aws.StringValue(v.SSEKMSKeyExported) != "key-"+aws.StringValue(v.Name) {
+			t.Errorf("bucket %s references key %q, want the key of its region", aws.StringValue(v.Name), aws.StringValue(v.SSEKMSKeyExported))
+		}
+	}
+
+	// The regional instance references the profile by its global label
+	RenderOpts.LabelPrefix = "prod_us-east-1_"
+	var buf bytes.Buffer
+	if err := regions["us-east-1"].Instances.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	want := `iam_instance_profile = "${aws_iam_instance_profile.prod_web.name}"`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("want %s in:\n%s", want, buf.String())
+	}
+}
+
+func sseKMS(keyID string) *s3.ServerSideEncryptionConfiguration {
+	return &s3.ServerSideEncryptionConfiguration{Rules: []*s3.ServerSideEncryptionRule{{
+		ApplyServerSideEncryptionByDefault: &s3.ServerSideEncryptionByDefault{
+			SSEAlgorithm:   aws.String("aws:kms"),
+			KMSMasterKeyID: aws.String(keyID),
+		},
+	}}}
+}
+
+func TestResolveReferencesECSServices(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{}
+
+	arn := func(name string) *string {
+		return aws.String("arn:aws:ecs:us-east-1:123456789012:cluster/" + name)
+	}
+	service := func(cluster string) *ECSService {
+		return &ECSService{
+			Name:           aws.String("web"),
+			ClusterName:    aws.String(cluster),
+			ClusterARN:     arn(cluster),
+			TaskDefinition: aws.String("web:1"),
+			DesiredCount:   aws.Int64(1),
+		}
+	}
+
+	s := &Snapshot{
+		ECSClusters: &ECSClusters{{Name: aws.String("exported"), ARN: arn("exported")}},
+		ECSServices: &ECSServices{service("exported"), service("other")},
+	}
+	s.ResolveReferences()
+
+	var buf bytes.Buffer
+	if err := s.ECSServices.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	out := strings.Join(strings.Fields(buf.String()), " ")
+	for _, want := range []string{
+		`cluster = "${aws_ecs_cluster.exported.id}"`,
+		`cluster = "arn:aws:ecs:us-east-1:123456789012:cluster/other"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want %s in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestResolveReferencesPlacementGroups(t *testing.T) {
+	defer func(opts RenderOptions) { *RenderOpts = opts }(*RenderOpts)
+	*RenderOpts = RenderOptions{}
+
+	instance := func(id, group string) *Instance {
+		return &Instance{
+			InstanceID:     aws.String(id),
+			ImageID:        aws.String("ami-12345678"),
+			InstanceType:   aws.String("c5.large"),
+			PlacementGroup: aws.String(group),
+		}
+	}
+
+	s := &Snapshot{
+		PlacementGroups: &PlacementGroups{{Name: aws.String("hpc"), Strategy: aws.String("cluster")}},
+		Instances:       &Instances{instance("i-1", "hpc"), instance("i-2", "other")},
+	}
+	s.ResolveReferences()
+
+	var buf bytes.Buffer
+	if err := s.Instances.WriteHCL(&buf); err != nil {
+		t.Fatalf("WriteHCL: %v", err)
+	}
+	out := strings.Join(strings.Fields(buf.String()), " ")
+	for _, want := range []string{
+		`placement_group = "${aws_placement_group.hpc.id}"`,
+		`placement_group = "other"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want %s in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestResolveReferencesSpotInstances(t *testing.T) {
+	var instances Instances
+	for _, v := range []struct{ id, request string }{{"i-1", ""}, {"i-2", "sir-exported"}, {"i-3", "sir-other"}} {
+		src := ec2Instance(v.id, 16)
+		if v.request != "" {
+			src.InstanceLifecycle = aws.String(ec2.InstanceLifecycleTypeSpot)
+			src.SpotInstanceRequestId = aws.String(v.request)
+		}
+		i := &Instance{}
+		if err := i.set(src); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+		instances = append(instances, i)
+	}
+
+	s := &Snapshot{
+		Instances:    &instances,
+		SpotRequests: &SpotRequests{{ID: aws.String("sir-exported"), SpotPrice: aws.String("0.05")}},
+	}
+	s.ResolveReferences()
+
+	var ids []string
+	for _, v := range *s.Instances {
+		ids = append(ids, aws.StringValue(v.InstanceID))
+	}
+	if want := []string{"i-1", "i-3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("instances %q, want %q", ids, want)
+	}
+}
@@ -0,0 +1,202 @@
+package tfit
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// Maximum number of names accepted by ssm:GetParameters
+const ssmGetParametersMax = 10
+
+//**************** SSM Parameter ****************
+type SSMParameter struct {
+	Name           *string
+	Type           *string
+	Description    *string
+	KeyID          *string
+	AllowedPattern *string
+	Tags           *Tags
+
+	// Nil for a SecureString which was not decrypted
+	Value *string
+}
+
+type SSMParameters []*SSMParameter
+
+// GetSSMParametersWithContext returns the parameters with their value.
+// SecureString values are fetched, decrypted, only when
+// DecryptSecureParams is set
+func (c *AWSClient) GetSSMParametersWithContext(ctx aws.Context) (*SSMParameters, error) {
+	var res SSMParameters
+
+	opt := &ssm.DescribeParametersInput{MaxResults: aws.Int64(50)}
+	for {
+		out, err := c.ssmconn.DescribeParametersWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range out.Parameters {
+			if v == nil || !c.wantID(v.Name) {
+				continue
+			}
+
+			tags, err := c.getSSMParameterTags(ctx, v.Name)
+			if err != nil {
+				return nil, err
+			}
+			if !c.matchTags(tags) {
+				continue
+			}
+
+			res = append(res, &SSMParameter{
+				Name:           v.Name,
+				Type:           v.Type,
+				Description:    v.Description,
+				KeyID:          v.KeyId,
+				AllowedPattern: v.AllowedPattern,
+				Tags:           tags,
+			})
+		}
+
+		c.progress("aws_ssm_parameter", len(res))
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	if err := c.setSSMParameterValues(ctx, res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// getSSMParameterTags returns the tags of the parameter name
+func (c *AWSClient) getSSMParameterTags(ctx aws.Context, name *string) (*Tags, error) {
+	out, err := c.ssmconn.ListTagsForResourceWithContext(ctx, &ssm.ListTagsForResourceInput{
+		ResourceId:   name,
+		ResourceType: aws.String(ssm.ResourceTypeForTaggingParameter),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Tags{}
+	for _, v := range out.TagList {
+		if v != nil && v.Key != nil {
+			(*res)[*v.Key] = v.Value
+		}
+	}
+
+	return res, nil
+}
+
+// setSSMParameterValues fetches the values of params, by batches of
+// ssmGetParametersMax
+func (c *AWSClient) setSSMParameterValues(ctx aws.Context, params SSMParameters) error {
+	byName := make(map[string]*SSMParameter)
+	var names []*string
+	for _, v := range params {
+		if aws.StringValue(v.Type) == ssm.ParameterTypeSecureString && !c.DecryptSecureParams {
+			continue
+		}
+		byName[aws.StringValue(v.Name)] = v
+		names = append(names, v.Name)
+	}
+
+	for i := 0; i < len(names); i += ssmGetParametersMax {
+		end := i + ssmGetParametersMax
+		if end > len(names) {
+			end = len(names)
+		}
+
+		out, err := c.ssmconn.GetParametersWithContext(ctx, &ssm.GetParametersInput{
+			Names:          names[i:end],
+			WithDecryption: aws.Bool(c.DecryptSecureParams),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, v := range out.Parameters {
+			if v == nil {
+				continue
+			}
+			if param, ok := byName[aws.StringValue(v.Name)]; ok {
+				param.Value = v.Value
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetSSMParameters calls GetSSMParametersWithContext with a background context
+func (c *AWSClient) GetSSMParameters() (*SSMParameters, error) {
+	return c.GetSSMParametersWithContext(aws.BackgroundContext())
+}
+
+func (p *SSMParameters) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	// A SecureString which was not decrypted gets a placeholder value,
+	// real secrets are only written on demand. Changes to the value are
+	// ignored then, so that applying does not overwrite the real one
+	tmpl := `
+	{{ if . }}
+    {{ range . }}
+    {{ resourceID "aws_ssm_parameter" .Name }}
+    resource "aws_ssm_parameter" "{{ sanitizeName .Name }}" {
+      name = "{{ .Name }}"
+      type = "{{ .Type }}"
+      {{- if .Value }}
+      value = {{ hclString .Value }}
+      {{- else }}
+      # WARNING: SecureString value redacted, set it before applying or export with --decrypt-secure-params
+      value = "REDACTED"
+      {{- end }}
+      {{- if .Description }}
+      description = {{ hclString .Description }}
+      {{- end }}
+      {{- if .KeyID }}
+      key_id = "{{ .KeyID }}"
+      {{- end }}
+      {{- if .AllowedPattern }}
+      allowed_pattern = {{ hclString .AllowedPattern }}
+      {{- end }}
+      {{- $tags := tags .Tags }}
+      {{- if $tags }}
+      tags {
+        {{- range $k, $v := $tags }}
+        {{ hclString $k }} = {{ hclString $v }}
+        {{- end }}
+      }
+      {{- end }}
+      {{- if not .Value }}
+
+      lifecycle {
+        ignore_changes = ["value"]
+      }
+      {{- end }}
+    }
+    {{- end }}
+	{{- end}}
+	`
+	return renderHCL(w, tmpl, funcMap, p)
+}
+
+// WriteImports writes the terraform import commands of 'SSMParameters'
+func (p *SSMParameters) WriteImports(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `{{ if . }}{{ range . -}}
+terraform import aws_ssm_parameter.{{ sanitizeName .Name }} {{ .Name }}
+{{ end }}{{ end }}`
+	return renderTerraformImportCmd(w, tmpl, funcMap, p)
+}
@@ -2,13 +2,15 @@ package tfit
 
 const EC2_ROUTE_TABLE = `{{ if . }}
   {{- range .}}
-resource "aws_route_table" "{{ .Id }}" {
+{{ resourceID "aws_route_table" .Id }}
+resource "aws_route_table" "{{ sanitizeName .Id }}" {
   vpc_id = "{{ .VpcId }}"
 
-  {{- if .Tags }}
+  {{- $tags := tags .Tags }}
+  {{- if $tags }}
   tags {
-    {{- range .Tags }}
-    "{{ .Key }}" = "{{ .Value }}"
+    {{- range $k, $v := $tags }}
+    {{ hclString $k }} = {{ hclString $v }}
     {{- end }}
   }
   {{- end }}
@@ -54,3 +56,252 @@ resource "aws_route_table" "{{ .Id }}" {
 }
   {{- end }}
 {{ end }}`
+
+const EC2_NETWORK_ACL = `{{ if . }}
+  {{- range . }}
+{{ resourceID "aws_network_acl" .Id }}
+resource "aws_network_acl" "{{ sanitizeName .Id }}" {
+  vpc_id = "{{ .VpcId }}"
+
+  {{- $tags := tags .Tags }}
+  {{- if $tags }}
+  tags {
+    {{- range $k, $v := $tags }}
+    {{ hclString $k }} = {{ hclString $v }}
+    {{- end }}
+  }
+  {{- end }}
+
+  {{- range .Ingresses }}
+  ingress {
+    rule_no = {{ .RuleNumber }}
+    protocol = "{{ .Protocol }}"
+    action = "{{ .RuleAction }}"
+    {{- if .CIDRBlock }}
+    cidr_block = "{{ .CIDRBlock }}"
+    {{- end }}
+    {{- if .IPv6CIDRBlock }}
+    ipv6_cidr_block = "{{ .IPv6CIDRBlock }}"
+    {{- end }}
+    {{- if .FromPort }}
+    from_port = {{ .FromPort }}
+    {{- else }}
+    from_port = 0
+    {{- end }}
+    {{- if .ToPort }}
+    to_port = {{ .ToPort }}
+    {{- else }}
+    to_port = 0
+    {{- end }}
+    {{- if .ICMPType }}
+    icmp_type = {{ .ICMPType }}
+    {{- end }}
+    {{- if .ICMPCode }}
+    icmp_code = {{ .ICMPCode }}
+    {{- end }}
+  }
+  {{- end }}
+
+  {{- range .Egresses }}
+  egress {
+    rule_no = {{ .RuleNumber }}
+    protocol = "{{ .Protocol }}"
+    action = "{{ .RuleAction }}"
+    {{- if .CIDRBlock }}
+    cidr_block = "{{ .CIDRBlock }}"
+    {{- end }}
+    {{- if .IPv6CIDRBlock }}
+    ipv6_cidr_block = "{{ .IPv6CIDRBlock }}"
+    {{- end }}
+    {{- if .FromPort }}
+    from_port = {{ .FromPort }}
+    {{- else }}
+    from_port = 0
+    {{- end }}
+    {{- if .ToPort }}
+    to_port = {{ .ToPort }}
+    {{- else }}
+    to_port = 0
+    {{- end }}
+    {{- if .ICMPType }}
+    icmp_type = {{ .ICMPType }}
+    {{- end }}
+    {{- if .ICMPCode }}
+    icmp_code = {{ .ICMPCode }}
+    {{- end }}
+  }
+  {{- end }}
+
+}
+    {{- $aclId := .Id }}
+    {{- range .Associations }}
+{{ resourceID "aws_network_acl_association" .Id }}
+resource "aws_network_acl_association" "{{ sanitizeName .Id }}" {
+  network_acl_id = "{{ $aclId }}"
+  subnet_id = "{{ .SubnetId }}"
+}
+    {{- end }}
+  {{- end }}
+{{ end }}`
+
+const EC2_VPC_PEERING_CONNECTION = `{{ if . }}
+  {{- range . }}
+{{ resourceID "aws_vpc_peering_connection" .Id }}
+resource "aws_vpc_peering_connection" "{{ sanitizeName .Id }}" {
+  vpc_id = "{{ .VpcId }}"
+  peer_vpc_id = "{{ .PeerVpcId }}"
+
+  {{- if .PeerOwnerId }}
+  peer_owner_id = "{{ .PeerOwnerId }}"
+  {{- end }}
+
+  {{- if .PeerRegion }}
+  peer_region = "{{ .PeerRegion }}"
+  {{- end }}
+
+  {{- if .AutoAccept }}
+  auto_accept = {{ .AutoAccept }}
+  {{- end }}
+
+  {{- $tags := tags .Tags }}
+  {{- if $tags }}
+  tags {
+    {{- range $k, $v := $tags }}
+    {{ hclString $k }} = {{ hclString $v }}
+    {{- end }}
+  }
+  {{- end }}
+}
+  {{- end }}
+{{ end }}`
+
+const EC2_VPC_ENDPOINT = `{{ if . }}
+  {{- range . }}
+{{ resourceID "aws_vpc_endpoint" .Id }}
+resource "aws_vpc_endpoint" "{{ sanitizeName .Id }}" {
+  vpc_id = "{{ .VpcId }}"
+  service_name = "{{ .ServiceName }}"
+
+  {{- if .VpcEndpointType }}
+  vpc_endpoint_type = "{{ .VpcEndpointType }}"
+  {{- end }}
+
+  {{- if .RouteTableIds }}
+  route_table_ids = [{{ .RouteTableIds | makeTerraformList }}]
+  {{- end }}
+
+  {{- if .SubnetIds }}
+  subnet_ids = [{{ .SubnetIds | makeTerraformList }}]
+  {{- end }}
+
+  {{- if .SecurityGroupIds }}
+  security_group_ids = [{{ .SecurityGroupIds | makeTerraformList }}]
+  {{- end }}
+
+  {{- if .PrivateDnsEnabled }}
+  private_dns_enabled = {{ .PrivateDnsEnabled }}
+  {{- end }}
+
+  {{- $tags := tags .Tags }}
+  {{- if $tags }}
+  tags {
+    {{- range $k, $v := $tags }}
+    {{ hclString $k }} = {{ hclString $v }}
+    {{- end }}
+  }
+  {{- end }}
+
+  {{- if .Policy }}
+  {{ jsonWarning .Policy }}
+  policy = <<POLICY
+{{ jsonDocument .Policy }}
+POLICY
+  {{- end }}
+}
+  {{- end }}
+{{ end }}`
+
+const EC2_DHCP_OPTIONS = `{{ if . }}
+  {{- range . }}
+{{ resourceID "aws_vpc_dhcp_options" .Id }}
+resource "aws_vpc_dhcp_options" "{{ sanitizeName .Id }}" {
+  {{- if .DomainName }}
+  domain_name = "{{ .DomainName }}"
+  {{- end }}
+
+  {{- if .DomainNameServers }}
+  domain_name_servers = [{{ .DomainNameServers | makeTerraformList }}]
+  {{- end }}
+
+  {{- if .NTPServers }}
+  ntp_servers = [{{ .NTPServers | makeTerraformList }}]
+  {{- end }}
+
+  {{- if .NetbiosNameServers }}
+  netbios_name_servers = [{{ .NetbiosNameServers | makeTerraformList }}]
+  {{- end }}
+
+  {{- if .NetbiosNodeType }}
+  netbios_node_type = "{{ .NetbiosNodeType }}"
+  {{- end }}
+
+  {{- $tags := tags .Tags }}
+  {{- if $tags }}
+  tags {
+    {{- range $k, $v := $tags }}
+    {{ hclString $k }} = {{ hclString $v }}
+    {{- end }}
+  }
+  {{- end }}
+}
+    {{- $dhcpId := .Id }}
+    {{- range .VpcIds }}
+{{ resourceID "aws_vpc_dhcp_options_association" $dhcpId . }}
+resource "aws_vpc_dhcp_options_association" "{{ sanitizeName (printf "%s_%s" (StringValue $dhcpId) (StringValue .)) }}" {
+  vpc_id = "{{ . }}"
+  dhcp_options_id = "{{ $dhcpId }}"
+}
+    {{- end }}
+  {{- end }}
+{{ end }}`
+
+const EC2_NETWORK_INTERFACE = `{{ if . }}
+  {{- range . }}
+{{ resourceID "aws_network_interface" .Id }}
+resource "aws_network_interface" "{{ sanitizeName .Id }}" {
+  subnet_id = "{{ .SubnetId }}"
+
+  {{- if .Description }}
+  description = {{ hclString .Description }}
+  {{- end }}
+
+  {{- if .PrivateIps }}
+  private_ips = [{{ .PrivateIps | makeTerraformList }}]
+  {{- end }}
+
+  {{- if .SecurityGroups }}
+  security_groups = [{{ .SecurityGroups | makeTerraformList }}]
+  {{- end }}
+
+  {{- if .SourceDestCheck }}
+  source_dest_check = {{ .SourceDestCheck }}
+  {{- end }}
+
+  {{- if .Attachment }}
+  attachment {
+    instance = "{{ .Attachment.InstanceId }}"
+    device_index = {{ .Attachment.DeviceIndex }}
+  }
+  {{- end }}
+
+  {{- $tags := tags .Tags }}
+  {{- if $tags }}
+  tags {
+    {{- range $k, $v := $tags }}
+    {{ hclString $k }} = {{ hclString $v }}
+    {{- end }}
+  }
+  {{- end }}
+}
+  {{- end }}
+{{ end }}`